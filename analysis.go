@@ -0,0 +1,375 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxReachabilityCombinations caps the number of dependency answer combinations evaluated
+// to decide whether a question is reachable. Questions with more combinations are assumed reachable.
+const maxReachabilityCombinations = 10000
+
+// detectUnreachableQuestions reports the questions that can never be shown:
+// questions whose condition is false for every possible answer of their dependencies,
+// and questions depending on an unreachable question.
+func (q *questionnaire) detectUnreachableQuestions() []Warning {
+	reachable := make(map[string]bool, len(q.QuestionList))
+	for _, question := range q.QuestionList {
+		q.isReachable(question, reachable)
+	}
+
+	var warnings []Warning
+	for _, question := range q.QuestionList {
+		if reachable[question.Id] {
+			continue
+		}
+		warnings = append(warnings, unreachableQuestionWarning(question, reachable))
+	}
+	return warnings
+}
+
+// isReachable reports whether the question can be shown for at least one combination of answers.
+// Results are memoized in reachable; dependencies must not be circular.
+func (q *questionnaire) isReachable(question question, reachable map[string]bool) bool {
+	if result, ok := reachable[question.Id]; ok {
+		return result
+	}
+
+	result := true
+	for _, depID := range question.DependsOn {
+		dep := q.findQuestionByID(depID)
+		if dep == nil || !q.isReachable(*dep, reachable) {
+			result = false
+			break
+		}
+	}
+	if result {
+		result = q.isConditionSatisfiable(question)
+	}
+
+	reachable[question.Id] = result
+	return result
+}
+
+// isConditionSatisfiable reports whether the question condition holds for at least one
+// combination of answers of its dependencies.
+// Conditions failing to evaluate are considered satisfiable: Next reports the error.
+func (q *questionnaire) isConditionSatisfiable(question question) bool {
+	if question.Condition == "" {
+		return true
+	}
+
+	values := make([][]int, len(question.DependsOn))
+	combinations := 1
+	for i, depID := range question.DependsOn {
+		dep := q.findQuestionByID(depID)
+		if !dep.isSingleChoice() || dep.hasProvidedOptions() {
+			// Texts, numbers, sets of choices and options only known at runtime can't be enumerated
+			return true
+		}
+		for answer := 1; answer <= len(dep.Answers); answer++ {
+			values[i] = append(values[i], answer)
+		}
+		if dep.canBeSkipped() {
+			values[i] = append(values[i], SkipAnswer)
+		}
+
+		combinations *= len(values[i])
+		if combinations > maxReachabilityCombinations {
+			return true
+		}
+	}
+
+	answers := make(map[string]int, len(question.DependsOn))
+	return q.anyCombinationSatisfies(question.Condition, question.DependsOn, values, answers)
+}
+
+// anyCombinationSatisfies enumerates the answer combinations of the questions
+// and reports whether the condition holds for one of them.
+func (q *questionnaire) anyCombinationSatisfies(condition string, questionIDs []string, values [][]int, answers map[string]int) bool {
+	depth := len(answers)
+	if depth == len(questionIDs) {
+		show, err := q.evaluateCondition(condition, answers)
+		return err != nil || show
+	}
+
+	for _, value := range values[depth] {
+		answers[questionIDs[depth]] = value
+		if q.anyCombinationSatisfies(condition, questionIDs, values, answers) {
+			return true
+		}
+	}
+	delete(answers, questionIDs[depth])
+	return false
+}
+
+// unreachableQuestionWarning builds the warning reported for a question that can never be shown.
+func unreachableQuestionWarning(question question, reachable map[string]bool) Warning {
+	message := fmt.Sprintf("question '%s' can never be shown: its condition is false for every answer of its dependencies", question.Id)
+	for _, depID := range question.DependsOn {
+		if !reachable[depID] {
+			message = fmt.Sprintf("question '%s' can never be shown: it depends on unreachable question '%s'", question.Id, depID)
+			break
+		}
+	}
+
+	return Warning{
+		Type:       UnreachableQuestionWarning,
+		QuestionID: question.Id,
+		Message:    message,
+	}
+}
+
+// maxAnalyzedStates caps the number of answer states explored by Analyze.
+const maxAnalyzedStates = 10000
+
+// pathExplorer walks every answer path of a questionnaire, the way a respondent would
+// going through Next, and records what is shown along the way.
+type pathExplorer struct {
+	q        *questionnaire
+	options  *nextOptions
+	visited  map[string]bool
+	shown    map[string]bool // IDs of the questions shown on at least one path
+	remarks  map[string]bool // IDs of the closing remarks shown on at least one path
+	deadEnds int             // Number of completed paths without any closing remark
+	outcomes int             // Number of completed paths
+	ended    int             // Number of completed paths ended early by the questionnaire
+	longest  int             // Most questions answered on a completed path
+	example  map[string]int  // Answers of the first completed path without any closing remark
+	failures []error         // Condition evaluation errors met along the paths, one per distinct error
+	failed   map[string]bool // Messages of the recorded evaluation errors
+	relevant map[string]bool // IDs of the questions referenced by conditions, nil when every answer matters
+
+	truncation string // Why the exploration stopped before covering every path, empty when it covered them all
+}
+
+// Analyze explores every answer path of the questionnaire and reports:
+//   - paths completing the questionnaire without any closing remark
+//   - questions and closing remarks that are never shown on any path
+//
+// Questions already reported by Warnings are not reported again.
+// Exploration stops after a fixed number of answer states; an AnalysisTruncatedWarning is
+// then reported and questions or remarks not reached are not flagged.
+func (q *questionnaire) Analyze() []Warning {
+	return q.analysisWarnings(q.explorePaths())
+}
+
+// explorePaths walks every answer path of the questionnaire from the first question.
+func (q *questionnaire) explorePaths() *pathExplorer {
+	explorer := &pathExplorer{
+		q:        q,
+		options:  newNextOptions([]NextOption{WithSeed(0)}, q.DefaultLocale),
+		visited:  make(map[string]bool),
+		shown:    make(map[string]bool),
+		remarks:  make(map[string]bool),
+		failed:   make(map[string]bool),
+		relevant: q.referencedQuestions(),
+	}
+	explorer.explore(map[string]int{})
+	return explorer
+}
+
+// analysisWarnings builds the warnings reported by Analyze from the explored paths.
+func (q *questionnaire) analysisWarnings(explorer *pathExplorer) []Warning {
+	var warnings []Warning
+	if explorer.deadEnds > 0 {
+		warnings = append(warnings, Warning{
+			Type: DeadEndWarning,
+			Message: fmt.Sprintf("%d answer path(s) complete the questionnaire without any closing remark, e.g. %s",
+				explorer.deadEnds, q.formatAnswers(explorer.example)),
+		})
+	}
+	if explorer.truncation != "" {
+		return append(warnings, Warning{
+			Type:    AnalysisTruncatedWarning,
+			Message: explorer.truncation,
+		})
+	}
+
+	reported := make(map[string]bool, len(q.warnings))
+	for _, warning := range q.warnings {
+		reported[warning.QuestionID] = true
+	}
+	for _, question := range q.QuestionList {
+		if !explorer.shown[question.Id] && !reported[question.Id] {
+			warnings = append(warnings, Warning{
+				Type:       UnreachableQuestionWarning,
+				QuestionID: question.Id,
+				Message:    fmt.Sprintf("question '%s' is never shown on any path through the questionnaire", question.Id),
+			})
+		}
+	}
+	for _, remark := range q.Remarks {
+		if !explorer.remarks[remark.Id] {
+			warnings = append(warnings, Warning{
+				Type:     UnreachableClosingRemarkWarning,
+				RemarkID: remark.Id,
+				Message:  fmt.Sprintf("closing remark '%s' is never shown on any path through the questionnaire", remark.Id),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// explore records what is shown for the answers, then answers the first eligible question
+// with each of its available answers and explores the resulting states.
+// Paths on which a condition fails to evaluate are abandoned and the failure is recorded.
+func (e *pathExplorer) explore(answers map[string]int) {
+	key := answersKey(answers)
+	if e.visited[key] {
+		return
+	}
+	if len(e.visited) >= maxAnalyzedStates {
+		e.truncation = fmt.Sprintf("analysis stopped after exploring %d answer states", maxAnalyzedStates)
+		return
+	}
+	e.visited[key] = true
+
+	terminated, err := e.q.isTerminated(answers)
+	if err != nil {
+		e.fail(answers, err)
+		return
+	}
+
+	var questions []Question
+	if !terminated {
+		questions, err = e.q.getNextQuestions(answers, e.options)
+		if err != nil {
+			e.fail(answers, err)
+			return
+		}
+	}
+
+	if !hasRequiredQuestion(questions) {
+		remarks, err := e.q.getClosingRemarks(answers, e.options)
+		if err != nil {
+			e.fail(answers, err)
+			return
+		}
+		e.outcomes++
+		if terminated {
+			e.ended++
+		}
+		e.longest = max(e.longest, len(answers))
+		for _, remark := range remarks {
+			e.remarks[remark.Id] = true
+		}
+		if len(remarks) == 0 && len(e.q.Remarks) > 0 {
+			if e.deadEnds == 0 {
+				e.example = answers
+			}
+			e.deadEnds++
+		}
+		return
+	}
+
+	for _, question := range questions {
+		e.shown[question.Id] = true
+	}
+
+	next := e.q.findQuestionByID(questions[0].Id)
+	if !next.isSingleChoice() {
+		// Texts, numbers and sets of choices can't be enumerated
+		e.truncation = fmt.Sprintf("analysis stopped at question '%s': answers to %s questions can't be enumerated", next.Id, next.kind())
+		return
+	}
+	values, err := e.q.availableAnswers(*next, answers)
+	if err != nil {
+		e.fail(answers, err)
+		return
+	}
+	if next.canBeSkipped() {
+		values = append(values, SkipAnswer)
+	}
+	if e.relevant != nil && !e.relevant[next.Id] {
+		// No condition depends on this answer: every value leads to the same paths
+		values = values[:1]
+	}
+	for _, value := range values {
+		nextAnswers := maps.Clone(answers)
+		nextAnswers[next.Id] = value
+		e.explore(nextAnswers)
+	}
+}
+
+// referencedQuestions returns the IDs of the questions referenced by at least one condition
+// or having an answer that ends the questionnaire.
+// It returns nil when a condition uses the score, as every answer may then change the flow.
+func (q *questionnaire) referencedQuestions() map[string]bool {
+	var conditions []string
+	referenced := make(map[string]bool)
+	for _, question := range q.QuestionList {
+		conditions = append(conditions, question.Condition, question.TerminateIf)
+		for i, option := range question.Answers {
+			conditions = append(conditions, option.Condition)
+			if question.terminatesWith(i + 1) {
+				// The chosen answer decides whether the questionnaire ends
+				referenced[question.Id] = true
+			}
+		}
+	}
+	for _, remark := range q.Remarks {
+		conditions = append(conditions, remark.Condition)
+	}
+	conditions = append(conditions, q.CompleteWhen)
+
+	for _, condition := range conditions {
+		if referencesIdentifier(condition, "score") {
+			return nil
+		}
+		for _, id := range extractReferences(condition, q.Computed) {
+			referenced[id] = true
+		}
+	}
+	return referenced
+}
+
+// referencesIdentifier reports whether the condition uses the identifier (e.g. a variable).
+func referencesIdentifier(condition, identifier string) bool {
+	for i := 0; i+len(identifier) <= len(condition); i++ {
+		if condition[i:i+len(identifier)] != identifier {
+			continue
+		}
+		before := i == 0 || !isIdentifierChar(condition[i-1])
+		after := i+len(identifier) == len(condition) || !isIdentifierChar(condition[i+len(identifier)])
+		if before && after {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records a condition evaluation error met with the answers, unless it was already met on another path.
+func (e *pathExplorer) fail(answers map[string]int, err error) {
+	if e.failed[err.Error()] {
+		return
+	}
+	e.failed[err.Error()] = true
+	e.failures = append(e.failures, conditionEvaluationError(e.q.formatAnswers(answers), err))
+}
+
+// answersKey returns a canonical representation of the answers.
+func answersKey(answers map[string]int) string {
+	var key strings.Builder
+	for _, id := range slices.Sorted(maps.Keys(answers)) {
+		fmt.Fprintf(&key, "%s=%d;", id, answers[id])
+	}
+	return key.String()
+}
+
+// formatAnswers formats the answers in question order, e.g. "q1=2, q2=1".
+func (q *questionnaire) formatAnswers(answers map[string]int) string {
+	parts := make([]string, 0, len(answers))
+	for _, question := range q.QuestionList {
+		if answer, ok := answers[question.Id]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%d", question.Id, answer))
+		}
+	}
+	if len(parts) == 0 {
+		return "no answers"
+	}
+	return strings.Join(parts, ", ")
+}
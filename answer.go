@@ -0,0 +1,491 @@
+package go_dynamic_questionnaire
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Question types, set with the type field of a question.
+const (
+	// ChoiceQuestion is the default question type: a single answer option is chosen.
+	ChoiceQuestion = "choice"
+
+	// MultipleChoiceQuestion lets respondents choose several answer options.
+	MultipleChoiceQuestion = "multiple_choice"
+
+	// TextQuestion is answered with free text. It has no answer options.
+	TextQuestion = "text"
+
+	// NumberQuestion is answered with a number. It has no answer options.
+	NumberQuestion = "number"
+
+	// NPSQuestion is a Net Promoter Score question, answered with an integer from 0 to 10. It has no answer options.
+	// Conditions classify its answer with npsCategory (see the NPS categories).
+	NPSQuestion = "nps"
+
+	// ConsentQuestion asks for consent (e.g. to the processing of personal data) before the questionnaire goes on.
+	// It is a single choice question whose first answer option grants consent: any other option declines it
+	// and ends the questionnaire immediately, with the closing remarks matching the declined answer.
+	ConsentQuestion = "consent"
+)
+
+// NPS categories, returned by npsCategory("question_id") in conditions.
+const (
+	// NPSPromoter is the category of NPS answers of 9 or 10.
+	NPSPromoter = "promoter"
+
+	// NPSPassive is the category of NPS answers of 7 or 8.
+	NPSPassive = "passive"
+
+	// NPSDetractor is the category of NPS answers from 0 to 6.
+	NPSDetractor = "detractor"
+)
+
+// Answer is the answer given to a question of any type, passed to NextAnswers:
+//
+//	answers := map[string]gdq.Answer{
+//	    "plan":     gdq.Choice(2),     // choice question
+//	    "features": gdq.Choices(1, 3), // multiple_choice question
+//	    "company":  gdq.Text("Acme"),  // text question
+//	    "seats":    gdq.Number(25),    // number question
+//	    "referral": gdq.Skipped(),     // skipped question
+//	}
+//
+// Answers are encoded in JSON as their natural value: a number, an array of numbers,
+// a string, or null when skipped.
+type Answer struct {
+	kind    answerKind
+	choice  int
+	number  float64
+	choices []int
+	text    string
+}
+
+// answerKind is the kind of value held by an Answer.
+type answerKind int
+
+const (
+	noAnswer      answerKind = iota // The zero Answer, rejected by NextAnswers
+	choiceAnswer                    // A single, 1-indexed answer choice (or an integral number)
+	choicesAnswer                   // Several 1-indexed answer choices
+	textAnswer                      // Free text
+	numberAnswer                    // A number
+	skipAnswer                      // The question is skipped
+)
+
+// Choice answers a choice question with a 1-indexed answer choice (or SkipAnswer).
+func Choice(choice int) Answer {
+	return Answer{kind: choiceAnswer, choice: choice}
+}
+
+// Choices answers a multiple_choice question with 1-indexed answer choices.
+func Choices(choices ...int) Answer {
+	return Answer{kind: choicesAnswer, choices: slices.Clone(choices)}
+}
+
+// Text answers a text question.
+func Text(text string) Answer {
+	return Answer{kind: textAnswer, text: text}
+}
+
+// Number answers a number question.
+func Number(number float64) Answer {
+	return Answer{kind: numberAnswer, number: number}
+}
+
+// Skipped skips an optional or skippable question of any type (see SkipAnswer).
+func Skipped() Answer {
+	return Answer{kind: skipAnswer}
+}
+
+// IsSkipped reports whether the answer skips the question.
+func (a Answer) IsSkipped() bool {
+	return a.kind == skipAnswer
+}
+
+// Value returns the value of the answer: an int for Choice, a []int for Choices, a string for Text,
+// a float64 for Number, and nil for Skipped.
+func (a Answer) Value() interface{} {
+	switch a.kind {
+	case choiceAnswer:
+		return a.choice
+	case choicesAnswer:
+		return slices.Clone(a.choices)
+	case textAnswer:
+		return a.text
+	case numberAnswer:
+		return a.number
+	default:
+		return nil
+	}
+}
+
+// MarshalJSON encodes the answer as its value (see Value).
+func (a Answer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Value())
+}
+
+// UnmarshalJSON decodes an answer from its value: integral numbers are decoded as Choice,
+// other numbers as Number, arrays as Choices, strings as Text, and null as Skipped.
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*a = Skipped()
+	case len(data) > 0 && data[0] == '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		*a = Text(text)
+	case len(data) > 0 && data[0] == '[':
+		var choices []int
+		if err := json.Unmarshal(data, &choices); err != nil {
+			return fmt.Errorf("answer choices must be integers: %w", err)
+		}
+		*a = Choices(choices...)
+	default:
+		var number float64
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("answer must be a number, an array of numbers, a string or null: %w", err)
+		}
+		*a = Number(number)
+		if choice, ok := a.asChoice(); ok {
+			*a = Choice(choice)
+		}
+	}
+	return nil
+}
+
+// asChoice returns the answer as a single answer choice: a Choice or an integral Number.
+func (a Answer) asChoice() (int, bool) {
+	switch a.kind {
+	case choiceAnswer:
+		return a.choice, true
+	case numberAnswer:
+		if a.number == math.Trunc(a.number) && math.Abs(a.number) <= math.MaxInt32 {
+			return int(a.number), true
+		}
+	}
+	return 0, false
+}
+
+// kind returns the type of the question, ChoiceQuestion when it isn't set.
+func (q question) kind() string {
+	if q.Type == "" {
+		return ChoiceQuestion
+	}
+	return q.Type
+}
+
+// isSingleChoice reports whether the question is answered with a single answer choice,
+// which is the only kind of question that can be answered through Next.
+func (q question) isSingleChoice() bool {
+	return q.kind() == ChoiceQuestion || q.kind() == ConsentQuestion
+}
+
+// answerRange describes the valid answers of the question, e.g. "1-3" for a choice question with 3 options, "0-10" for an nps question,
+// or "0-any" for a number question with a min of 0 and no max.
+func (q question) answerRange() string {
+	switch q.kind() {
+	case NPSQuestion:
+		return "0-10"
+	case NumberQuestion:
+		return describeBound(q.Min) + "-" + describeBound(q.Max)
+	}
+	return fmt.Sprintf("1-%d", len(q.Answers))
+}
+
+// describeBound formats a bound of a number question for an error message, "any" when it isn't set.
+func describeBound(bound *float64) string {
+	if bound == nil {
+		return "any"
+	}
+	return strconv.FormatFloat(*bound, 'g', -1, 64)
+}
+
+// inBounds reports whether the answer of a number question is between its min and max, when they are set.
+func (q question) inBounds(number float64) bool {
+	return (q.Min == nil || number >= *q.Min) && (q.Max == nil || number <= *q.Max)
+}
+
+// validNumberBounds reports whether the min and max of the question can be met:
+// they are only set on number questions, min below max.
+func (q question) validNumberBounds() bool {
+	if q.Min == nil && q.Max == nil {
+		return true
+	}
+	return q.kind() == NumberQuestion && (q.Min == nil || q.Max == nil || *q.Min <= *q.Max)
+}
+
+// maxChoices returns the maximum number of options chosen in the question: its max_choices, every option when it has none.
+func (q question) maxChoices() int {
+	if q.MaxChoices > 0 {
+		return q.MaxChoices
+	}
+	return len(q.Answers)
+}
+
+// validChoiceLimits reports whether the min_choices and max_choices of the question can be met:
+// they are only set on multiple choice questions, between 0 and the number of options, min below max.
+func (q question) validChoiceLimits() bool {
+	if q.MinChoices == 0 && q.MaxChoices == 0 {
+		return true
+	}
+	if q.hasProvidedOptions() {
+		// The number of options is only known at runtime
+		return q.kind() == MultipleChoiceQuestion && q.MinChoices >= 0 && q.MaxChoices >= 0 &&
+			(q.MaxChoices == 0 || q.MinChoices <= q.MaxChoices)
+	}
+	return q.kind() == MultipleChoiceQuestion && q.MinChoices >= 0 && q.MaxChoices >= 0 &&
+		q.MinChoices <= q.maxChoices() && q.maxChoices() <= len(q.Answers)
+}
+
+// npsCategory classifies an NPS answer: promoter from 9, passive from 7 and detractor below.
+func npsCategory(score float64) string {
+	switch {
+	case score >= 9:
+		return NPSPromoter
+	case score >= 7:
+		return NPSPassive
+	default:
+		return NPSDetractor
+	}
+}
+
+// hasOptions reports whether the question is answered by choosing answer options.
+func (q question) hasOptions() bool {
+	return q.isSingleChoice() || q.kind() == MultipleChoiceQuestion
+}
+
+// validConsent reports whether a consent question can be used to collect consent: it must be answered
+// (required and not skippable), offer an answer declining consent besides the one granting it,
+// and not grant consent by default.
+func (q question) validConsent() bool {
+	if q.kind() != ConsentQuestion {
+		return true
+	}
+	return q.isRequired() && !q.Skippable && len(q.Answers) >= 2 && q.Default == 0
+}
+
+// AnswerValue is the type of the answers accepted by the Next function:
+// answer choices as an int (or any type based on int, such as an enum of yes/no answers), or answers of any question type.
+type AnswerValue interface {
+	~int | Answer
+}
+
+// Next processes the answers with Questionnaire.Next when they are answer choices, and with Questionnaire.NextAnswers
+// when they are Answer values. Questionnaires only made of choice questions can keep a compact map of ints,
+// while richer questionnaires use Answer values, with the same call.
+//
+// Example usage:
+//
+//	type YesNo int
+//
+//	const (
+//	    Yes YesNo = iota + 1
+//	    No
+//	)
+//
+//	response, err := gdq.Next(q, map[string]YesNo{"employed": Yes})
+func Next[T AnswerValue](q Questionnaire, answers map[string]T, opts ...NextOption) (*Response, error) {
+	switch typed := any(answers).(type) {
+	case map[string]int:
+		return q.Next(typed, opts...)
+	case map[string]Answer:
+		return q.NextAnswers(typed, opts...)
+	}
+
+	// The answers are of a type based on int
+	choices := make(map[string]int, len(answers))
+	for questionID, answer := range answers {
+		choices[questionID] = int(reflect.ValueOf(answer).Int())
+	}
+	return q.Next(choices, opts...)
+}
+
+// NextAnswers works like Next, with answers of any question type.
+//
+// The answers of the questions that aren't single choice are exposed to conditions through values
+// (e.g. values["seats"] > 10, 2 in values["features"]); answers only records that they were answered.
+func (q *questionnaire) NextAnswers(answers map[string]Answer, opts ...NextOption) (*Response, error) {
+	options := newNextOptions(opts, q.DefaultLocale)
+	// The answers are checked against the provided options, which aren't requested again by Next
+	provided, err := q.withProvidedOptions(options)
+	if err != nil {
+		if q.metrics != nil {
+			q.metrics.NextCalled()
+		}
+		return nil, fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	q = provided
+	choices, values, ignored, err := q.splitAnswers(answers, options)
+	if err != nil {
+		if q.metrics != nil {
+			q.metrics.NextCalled()
+		}
+		q.recordValidationErrors(err)
+		return nil, fmt.Errorf("invalid answers provided: %w", err)
+	}
+	if len(ignored) > 0 {
+		opts = append(slices.Clip(opts), func(o *nextOptions) { o.ignored = ignored })
+	}
+	if len(values) == 0 {
+		return q.Next(choices, opts...)
+	}
+
+	// The values only exist for this call: they are set on a copy of the questionnaire,
+	// which stays immutable and safe for concurrent use
+	scoped := *q
+	scoped.values = values
+	return scoped.Next(choices, opts...)
+}
+
+// splitAnswers checks that the answers match the type of their question and splits them into
+// the answer choices used by Next, and the values of the questions that aren't single choice.
+// Those questions are recorded in the answer choices with a zero value.
+// In lenient mode, answers to unknown questions and out-of-range choices are left out and returned as ignored
+// (see WithLenientAnswers); with WithAllAnswerErrors, every invalid answer is reported.
+func (q *questionnaire) splitAnswers(answers map[string]Answer, options *nextOptions) (map[string]int, map[string]interface{}, []ValidationError, error) {
+	choices := make(map[string]int, len(answers))
+	var values map[string]interface{}
+	var ignored []ValidationError
+	var errs []error
+	for _, questionID := range slices.Sorted(maps.Keys(answers)) {
+		choice, value, err := q.splitAnswer(questionID, answers[questionID])
+		if err != nil {
+			if validationErr, ok := ignorableAnswerError(err); ok && options.lenient {
+				ignored = append(ignored, validationErr)
+				continue
+			}
+			if !options.allErrors {
+				return nil, nil, nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		choices[questionID] = choice
+		if value != nil {
+			if values == nil {
+				values = make(map[string]interface{})
+			}
+			values[questionID] = value
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, nil, errors.Join(errs...)
+	}
+	return choices, values, ignored, nil
+}
+
+// splitAnswer checks that the answer matches the type of the question and returns its answer choice,
+// and its value when the question isn't single choice.
+func (q *questionnaire) splitAnswer(questionID string, answer Answer) (int, interface{}, error) {
+	question := q.findQuestionByID(questionID)
+	if question == nil {
+		return 0, nil, q.withSuggestedQuestionIDs(invalidQuestionIDError(questionID, answer.Value()), questionID)
+	}
+	if answer.IsSkipped() {
+		return SkipAnswer, nil, nil
+	}
+
+	switch question.kind() {
+	case ChoiceQuestion, ConsentQuestion:
+		choice, ok := answer.asChoice()
+		if !ok {
+			return 0, nil, invalidAnswerTypeError(question, answer.Value())
+		}
+		return choice, nil, nil
+	case MultipleChoiceQuestion:
+		selected := answer.choices
+		if choice, ok := answer.asChoice(); ok && choice == SkipAnswer {
+			return SkipAnswer, nil, nil
+		} else if ok {
+			selected = []int{choice}
+		} else if answer.kind != choicesAnswer {
+			return 0, nil, invalidAnswerTypeError(question, answer.Value())
+		}
+		for _, choice := range selected {
+			if choice < 1 || choice > len(question.Answers) {
+				return 0, nil, invalidAnswerRangeError(question, choice)
+			}
+		}
+		selected = slices.Clone(selected)
+		slices.Sort(selected)
+		selected = slices.Compact(selected)
+		if len(selected) < question.MinChoices || len(selected) > question.maxChoices() {
+			return 0, nil, invalidChoiceCountError(question, selected)
+		}
+		if len(selected) > 1 {
+			for _, choice := range selected {
+				if question.Answers[choice-1].Exclusive {
+					return 0, nil, exclusiveAnswerError(question, choice, selected)
+				}
+			}
+		}
+		return 0, selected, nil
+	case TextQuestion:
+		if answer.kind != textAnswer {
+			return 0, nil, invalidAnswerTypeError(question, answer.Value())
+		}
+		return 0, answer.text, nil
+	case NPSQuestion:
+		score, ok := answer.asChoice()
+		if !ok {
+			return 0, nil, invalidAnswerTypeError(question, answer.Value())
+		}
+		if score < 0 || score > 10 {
+			return 0, nil, invalidAnswerRangeError(question, score)
+		}
+		return 0, float64(score), nil
+	default:
+		var number float64
+		switch answer.kind {
+		case numberAnswer:
+			number = answer.number
+		case choiceAnswer:
+			number = float64(answer.choice)
+		default:
+			return 0, nil, invalidAnswerTypeError(question, answer.Value())
+		}
+		if !question.inBounds(number) {
+			return 0, nil, invalidAnswerRangeError(question, number)
+		}
+		return 0, number, nil
+	}
+}
+
+// ignorableAnswerError reports whether the error is left out in lenient mode (see WithLenientAnswers):
+// an answer to an unknown question or an out-of-range answer.
+func ignorableAnswerError(err error) (ValidationError, bool) {
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		return ValidationError{}, false
+	}
+	return validationErr, validationErr.Type == InvalidQuestionIDErrType || validationErr.Type == InvalidAnswerRangeErrType
+}
+
+// selectedChoices returns the answer choices selected for the question: the chosen option of a choice question,
+// or the options of a multiple_choice question (see NextAnswers). It returns nil when no option is selected.
+func (q *questionnaire) selectedChoices(question *question, answers map[string]int) []int {
+	answer, answered := answers[question.Id]
+	switch {
+	case !answered:
+		return nil
+	case question.kind() == MultipleChoiceQuestion:
+		selected, _ := q.values[question.Id].([]int)
+		return selected
+	case answer >= 1 && answer <= len(question.Answers):
+		return []int{answer}
+	default:
+		return nil
+	}
+}
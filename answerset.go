@@ -0,0 +1,48 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateAnswerSet checks the answers as Next does (answer ranges, available options, consistency rules), then checks
+// that every answered question is on the path taken through the questionnaire: its dependencies are answered
+// and its condition holds given the other answers (see WithStaleAnswers). Each question answered off the path
+// is reported with an unreachable_answer error, so that tampered or stale submissions are rejected before being stored.
+//
+// Answers given to hidden questions are ignored, as by Next. WithContextAnswers answers them, WithAllAnswerErrors
+// reports every invalid answer rather than the first one found; other options are ignored.
+func (q *questionnaire) ValidateAnswerSet(answers map[string]int, opts ...NextOption) error {
+	options := newNextOptions(opts, q.DefaultLocale)
+	q, err := q.withProvidedOptions(options)
+	if err != nil {
+		return fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	q, answers, err = q.fillHiddenAnswers(answers, options)
+	if err != nil {
+		return fmt.Errorf("failed to answer hidden questions: %w", err)
+	}
+	if err := q.validateAnswers(answers, options.allErrors); err != nil {
+		q.recordValidationErrors(err)
+		return fmt.Errorf("invalid answers provided: %w", err)
+	}
+	if err := q.checkRules(answers, options); err != nil {
+		q.recordValidationErrors(err)
+		return fmt.Errorf("invalid answers provided: %w", err)
+	}
+
+	unreachable, err := q.staleAnswers(answers)
+	if err != nil {
+		return fmt.Errorf("failed to check the path of the answers: %w", err)
+	}
+	if len(unreachable) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(unreachable))
+	for _, id := range unreachable {
+		errs = append(errs, unreachableAnswerError(q.findQuestionByID(id), answers[id]))
+	}
+	err = errors.Join(errs...)
+	q.recordValidationErrors(err)
+	return fmt.Errorf("invalid answers provided: %w", err)
+}
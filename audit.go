@@ -0,0 +1,133 @@
+package go_dynamic_questionnaire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// AuditSink receives an audit record for every Next call of a questionnaire (see WithAuditSink),
+	// for instance to keep the trail of decisions required by compliance questionnaires in an append-only store.
+	//
+	// Implementations must be safe for concurrent use, as a questionnaire is shared between goroutines:
+	//
+	//	type dbSink struct{ db *sql.DB }
+	//
+	//	func (s dbSink) Record(record gdq.AuditRecord) error {
+	//	    content, err := json.Marshal(record)
+	//	    if err != nil {
+	//	        return err
+	//	    }
+	//	    _, err = s.db.Exec("INSERT INTO audit_trail (recorded_at, record) VALUES ($1, $2)", record.Time, content)
+	//	    return err
+	//	}
+	AuditSink interface {
+		// Record stores the record. Next fails when the record can't be stored,
+		// so that no decision is returned without being audited.
+		Record(record AuditRecord) error
+	}
+
+	// AuditRecord describes a Next call, as passed to the audit sink (see WithAuditSink).
+	// The record only holds the hash of the answers, not the answers themselves.
+	//
+	// Example JSON representation:
+	//
+	//	{
+	//	  "time": "2025-03-01T10:15:00Z",
+	//	  "checksum": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	//	  "answers_hash": "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b",
+	//	  "questions": ["income"],
+	//	  "completed": false,
+	//	  "evaluations": [
+	//	    {"condition": "answers[\"employed\"] == 1", "result": true}
+	//	  ]
+	//	}
+	AuditRecord struct {
+		Time           time.Time             `json:"time"`                      // When Next was called (see WithClock)
+		Checksum       string                `json:"checksum"`                  // Fingerprint of the questionnaire definition (see Checksum)
+		AnswersHash    string                `json:"answers_hash"`              // Hash of the answers passed to Next (see HashAnswers), sensitive answers excluded (see WithSensitiveAnswers)
+		Questions      []string              `json:"questions,omitempty"`       // IDs of the questions returned
+		ClosingRemarks []string              `json:"closing_remarks,omitempty"` // IDs of the closing remarks returned
+		Completed      bool                  `json:"completed"`                 // Whether the questionnaire is complete
+		Terminated     bool                  `json:"terminated,omitempty"`      // Whether the questionnaire was ended early
+		Evaluations    []ConditionEvaluation `json:"evaluations,omitempty"`     // Conditions evaluated to answer the call, in evaluation order
+		Error          string                `json:"error,omitempty"`           // Why Next failed, empty when it succeeded, sensitive answers redacted (see WithSensitiveAnswers)
+	}
+
+	// ConditionEvaluation is the outcome of a condition evaluated during a Next call.
+	ConditionEvaluation struct {
+		Condition string `json:"condition"`          // The evaluated expression
+		Result    bool   `json:"result"`             // The result of the evaluation, false when it failed
+		Error     string `json:"error,omitempty"`    // Why the evaluation failed, empty when it succeeded
+		Redacted  bool   `json:"redacted,omitempty"` // Whether the result is hidden because the condition references a sensitive question (see RedactSensitiveAnswers)
+	}
+
+	// auditTrail collects the evaluations of the Next call in progress.
+	auditTrail struct {
+		evaluations []ConditionEvaluation
+	}
+)
+
+// HashAnswers returns the SHA-256, in hexadecimal, of the canonical JSON encoding of the answers and values:
+// {"answers":{...},"values":{...}} with keys sorted, values omitted when empty.
+// The same answers always give the same hash, so a hash recorded in the audit trail can be checked
+// against the answers stored separately.
+func HashAnswers(answers map[string]int, values map[string]interface{}) (string, error) {
+	content, err := json.Marshal(struct {
+		Answers map[string]int         `json:"answers"`
+		Values  map[string]interface{} `json:"values,omitempty"`
+	}{answers, values})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash answers: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// auditedNext runs Next on a copy of the questionnaire collecting the conditions it evaluates,
+// then records the call to the audit sink.
+func (q *questionnaire) auditedNext(answers map[string]int, opts []NextOption) (*Response, error) {
+	record := AuditRecord{Time: q.currentTime(), Checksum: q.checksum}
+	hash, err := HashAnswers(withoutSensitive(q, answers), withoutSensitive(q, q.values))
+	if err != nil {
+		return nil, err
+	}
+	record.AnswersHash = hash
+
+	scoped := *q
+	scoped.audit = &auditTrail{}
+	response, err := scoped.Next(answers, opts...)
+	record.Evaluations = q.redactEvaluations(scoped.audit.evaluations)
+	if err != nil {
+		record.Error = q.redactError(err)
+	} else {
+		for _, question := range response.Questions {
+			record.Questions = append(record.Questions, question.Id)
+		}
+		for _, remark := range response.ClosingRemarks {
+			record.ClosingRemarks = append(record.ClosingRemarks, remark.Id)
+		}
+		record.Completed = response.Completed
+		record.Terminated = response.Terminated
+	}
+
+	if recordErr := q.auditSink.Record(record); recordErr != nil {
+		return nil, fmt.Errorf("failed to record audit trail: %w", recordErr)
+	}
+	return response, err
+}
+
+// auditEvaluation adds the outcome of a condition evaluation to the audit trail of the Next call in progress, if any.
+func (q *questionnaire) auditEvaluation(condition string, result bool, err error) {
+	if q.audit == nil {
+		return
+	}
+	evaluation := ConditionEvaluation{Condition: condition, Result: result}
+	if err != nil {
+		evaluation.Error = err.Error()
+	}
+	q.audit.evaluations = append(q.audit.evaluations, evaluation)
+}
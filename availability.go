@@ -0,0 +1,38 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"time"
+)
+
+// validateAvailability checks that the availability window of the questionnaire isn't empty.
+func (q *questionnaire) validateAvailability() error {
+	if !q.AvailableFrom.IsZero() && !q.AvailableUntil.IsZero() && !q.AvailableUntil.After(q.AvailableFrom) {
+		return invalidAvailabilityError(q.AvailableFrom, q.AvailableUntil, errors.New("available_until must be after available_from"))
+	}
+	return nil
+}
+
+// checkAvailability returns an error when the questionnaire isn't open yet or has expired at the current time
+// (see WithClock): respondents can answer from available_from included until available_until excluded.
+func (q *questionnaire) checkAvailability() error {
+	if q.AvailableFrom.IsZero() && q.AvailableUntil.IsZero() {
+		return nil
+	}
+	now := q.currentTime()
+	if !q.AvailableFrom.IsZero() && now.Before(q.AvailableFrom) {
+		return notYetAvailableError(q.AvailableFrom)
+	}
+	if !q.AvailableUntil.IsZero() && !now.Before(q.AvailableUntil) {
+		return expiredQuestionnaireError(q.AvailableUntil)
+	}
+	return nil
+}
+
+// currentTime returns the current time according to the clock of the questionnaire (see WithClock).
+func (q *questionnaire) currentTime() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+	return time.Now()
+}
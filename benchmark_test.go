@@ -0,0 +1,72 @@
+package go_dynamic_questionnaire_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// largeQuestionnaire generates a questionnaire of size questions: every question
+// but the first depends on the previous one, and half of them have a condition.
+func largeQuestionnaire(b *testing.B, size int) gdq.Questionnaire {
+	b.Helper()
+
+	var config strings.Builder
+	config.WriteString("questions:\n")
+	for i := 1; i <= size; i++ {
+		fmt.Fprintf(&config, "  - id: \"q%d\"\n    text: \"Question %d?\"\n    answers: [\"A\", \"B\", \"C\"]\n", i, i)
+		if i > 1 {
+			fmt.Fprintf(&config, "    depends_on: [\"q%d\"]\n", i-1)
+			if i%2 == 0 {
+				fmt.Fprintf(&config, "    condition: 'answers[\"q%d\"] != 3'\n", i-1)
+			} else {
+				fmt.Fprintf(&config, "    condition: 'answered(\"q%d\")'\n", i-1)
+			}
+		}
+	}
+	config.WriteString("closing_remarks:\n  - id: \"done\"\n    text: \"Done!\"\n    condition: 'answers[\"q1\"] == 1'\n")
+
+	q, err := gdq.New([]byte(config.String()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return q
+}
+
+// answersUpTo answers the questions q1 to qn with the first answer.
+func answersUpTo(n int) map[string]int {
+	answers := make(map[string]int, n)
+	for i := 1; i <= n; i++ {
+		answers[fmt.Sprintf("q%d", i)] = 1
+	}
+	return answers
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("questions=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				largeQuestionnaire(b, size)
+			}
+		})
+	}
+}
+
+func BenchmarkNext(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		q := largeQuestionnaire(b, size)
+		for _, answered := range []int{0, size / 2, size} {
+			answers := answersUpTo(answered)
+			b.Run(fmt.Sprintf("questions=%d/answered=%d", size, answered), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := q.Next(answers); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package go_dynamic_questionnaire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// AnswerLink is a submission of answers chained to the submissions before it (see ChainAnswers).
+// Its hash covers the answers and the hash of the previous link, so that modifying, removing or reordering
+// a submission after the fact breaks every following link.
+//
+// Example JSON representation:
+//
+//	{
+//	  "answers": {"employed": 1},
+//	  "previous": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	  "hash": "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+//	}
+type AnswerLink struct {
+	Answers  map[string]int         `json:"answers"`          // The answers submitted
+	Values   map[string]interface{} `json:"values,omitempty"` // The values submitted for the questions that aren't single choice
+	Previous string                 `json:"previous"`         // Hash of the previous link, or the genesis hash for the first link
+	Hash     string                 `json:"hash"`             // Hash of the link, to pass as previous to the next link
+}
+
+// ChainAnswers returns the link chaining a submission of answers to the previous link, given its hash.
+// The first link of a chain is chained to a genesis hash, usually the checksum of the questionnaire,
+// which binds the chain to the definition the answers were given to:
+//
+//	first, err := gdq.ChainAnswers(q.Checksum(), map[string]int{"employed": 1}, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	second, err := gdq.ChainAnswers(first.Hash, map[string]int{"employed": 1, "income": 2}, nil)
+//
+// Store the links along with the response, and check them with VerifyAnswerChain.
+func ChainAnswers(previous string, answers map[string]int, values map[string]interface{}) (AnswerLink, error) {
+	hash, err := linkHash(previous, answers, values)
+	if err != nil {
+		return AnswerLink{}, err
+	}
+	return AnswerLink{Answers: answers, Values: values, Previous: previous, Hash: hash}, nil
+}
+
+// VerifyAnswerChain checks that the links form an unbroken chain from the genesis hash (see ChainAnswers):
+// every link is chained to the one before it, and its hash matches its answers.
+// The returned error wraps ErrAnswerChainBroken and tells which link was modified.
+func VerifyAnswerChain(genesis string, links []AnswerLink) error {
+	previous := genesis
+	for i, link := range links {
+		if link.Previous != previous {
+			return fmt.Errorf("%w: link %d isn't chained to the previous link", ErrAnswerChainBroken, i+1)
+		}
+		hash, err := linkHash(link.Previous, link.Answers, link.Values)
+		if err != nil {
+			return err
+		}
+		if link.Hash != hash {
+			return fmt.Errorf("%w: the answers of link %d don't match its hash", ErrAnswerChainBroken, i+1)
+		}
+		previous = link.Hash
+	}
+	return nil
+}
+
+// linkHash returns the SHA-256, in hexadecimal, of the previous hash followed by the hash of the answers (see HashAnswers).
+func linkHash(previous string, answers map[string]int, values map[string]interface{}) (string, error) {
+	answersHash, err := HashAnswers(answers, values)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(previous + "\n" + answersHash))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,26 @@
+package go_dynamic_questionnaire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// computeChecksum fingerprints the definition of the questionnaire, as loaded from the configuration:
+// the SHA-256 of its JSON encoding, in hexadecimal. Environment variables and params are already applied,
+// so the same configuration rendered with other values gets another checksum.
+// Hashing the parsed definition rather than the configuration content makes the checksum independent
+// of the format (YAML or JSON), of the formatting and of comments.
+func (q *questionnaire) computeChecksum() (string, error) {
+	definition, err := json.Marshal(q)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(definition)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Checksum returns the fingerprint of the questionnaire definition.
+func (q *questionnaire) Checksum() string {
+	return q.checksum
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/goccy/go-yaml"
+)
+
+// runConvert implements "gdq convert": it converts a questionnaire between YAML and JSON,
+// the formats being chosen from the file extensions.
+//
+// The document is converted as written, keys order included, rather than as loaded,
+// so that repeating groups, macros and templates are kept as they are.
+// The questionnaire is loaded before and after the conversion, and the conversion fails
+// when the converted questionnaire doesn't have the checksum of the original one.
+//
+// The exit code is exitOK when the questionnaire is converted, exitInvalid when it is invalid,
+// and exitUsage when a file can't be read or written.
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq convert <in.yaml|in.json> <out.yaml|out.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	in, out := flags.Arg(0), flags.Arg(1)
+	toJSON, err := isJSON(out)
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+
+	q, err := gdq.New(in)
+	if err != nil {
+		if validationErrs := gdq.ValidationErrors(err); len(validationErrs) > 0 {
+			for _, validationErr := range validationErrs {
+				fmt.Fprintf(stderr, "%s: error: %s: %s\n", in, validationErr.Type, validationErr.Message)
+			}
+			return exitInvalid
+		}
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+
+	content, err := os.ReadFile(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+	converted, err := convert(content, toJSON)
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: failed to convert %s: %v\n", in, err)
+		return exitUsage
+	}
+	if check, err := gdq.New(converted); err != nil || check.Checksum() != q.Checksum() {
+		fmt.Fprintf(stderr, "gdq: failed to convert %s: the converted questionnaire differs from the original one\n", in)
+		return exitUsage
+	}
+
+	if err := os.WriteFile(out, converted, 0o644); err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+	fmt.Fprintf(stdout, "%s: converted to %s\n", in, out)
+	return exitOK
+}
+
+// isJSON reports whether the file is a JSON file rather than a YAML one, from its extension.
+func isJSON(file string) (bool, error) {
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".json":
+		return true, nil
+	case ".yaml", ".yml":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported file extension %q: expected .yaml, .yml, or .json", ext)
+	}
+}
+
+// convert encodes the YAML or JSON document in JSON, indented with two spaces, or in YAML.
+// JSON being valid YAML, the document is always decoded as YAML, keeping the order of the keys.
+func convert(content []byte, toJSON bool) ([]byte, error) {
+	var document interface{}
+	if err := yaml.UnmarshalWithOptions(content, &document, yaml.UseOrderedMap()); err != nil {
+		return nil, err
+	}
+
+	if !toJSON {
+		return yaml.MarshalWithOptions(document, yaml.IndentSequence(true), yaml.UseLiteralStyleIfMultiline(true))
+	}
+	encoded, err := yaml.MarshalWithOptions(document, yaml.JSON())
+	if err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(encoded), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteString("\n")
+	return indented.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("convert", func() {
+	var (
+		stdout, stderr *bytes.Buffer
+		dir            string
+	)
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		dir = GinkgoT().TempDir()
+	})
+
+	It("should convert YAML to JSON, keeping the order of the keys", func() {
+		out := filepath.Join(dir, "valid.json")
+		Expect(run([]string{"convert", "testdata/valid.yaml", out}, stdout, stderr)).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal("testdata/valid.yaml: converted to " + out + "\n"))
+
+		content, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix(`{
+  "questions": [
+    {
+      "id": "experience",
+      "text": "Do you have programming experience?",
+      "answers": [
+        "Yes",
+        "No"
+      ]
+    },
+`))
+		Expect(string(content)).To(HaveSuffix("}\n"))
+	})
+
+	It("should convert JSON back to the same questionnaire in YAML", func() {
+		converted, back := filepath.Join(dir, "valid.json"), filepath.Join(dir, "valid.yml")
+		Expect(run([]string{"convert", "testdata/valid.yaml", converted}, stdout, stderr)).To(Equal(exitOK))
+		Expect(run([]string{"convert", converted, back}, stdout, stderr)).To(Equal(exitOK))
+
+		original, err := gdq.New("testdata/valid.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		roundTrip, err := gdq.New(back)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roundTrip.Checksum()).To(Equal(original.Checksum()))
+	})
+
+	It("should not convert an invalid questionnaire", func() {
+		out := filepath.Join(dir, "invalid.json")
+		Expect(run([]string{"convert", "testdata/invalid.yaml", out}, stdout, stderr)).To(Equal(exitInvalid))
+		Expect(stderr.String()).To(ContainSubstring("testdata/invalid.yaml: error: "))
+		Expect(out).NotTo(BeAnExistingFile())
+	})
+
+	It("should reject an unsupported output format", func() {
+		Expect(run([]string{"convert", "testdata/valid.yaml", filepath.Join(dir, "valid.toml")}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(Equal("gdq: unsupported file extension \".toml\": expected .yaml, .yml, or .json\n"))
+	})
+
+	It("should print the usage without both files", func() {
+		Expect(run([]string{"convert", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq convert"))
+	})
+})
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// runDiff implements "gdq diff": it compares two versions of a questionnaire semantically (see gdq.Diff)
+// and prints the changes, flagging the ones breaking stored answers.
+//
+// The exit code is exitOK when no change is breaking, exitInvalid when a change is,
+// and exitUsage when a file can't be loaded.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq diff [flags] <old.yaml|old.json> <new.yaml|new.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	versions := make([]gdq.Questionnaire, 2)
+	for i, file := range flags.Args() {
+		q, err := gdq.New(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "gdq: %v\n", err)
+			return exitUsage
+		}
+		versions[i] = q
+	}
+	changes, err := gdq.Diff(versions[0], versions[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+
+	code := exitOK
+	for _, change := range changes {
+		if change.Breaking {
+			code = exitInvalid
+		}
+	}
+
+	if *format == "json" {
+		if changes == nil {
+			changes = []gdq.Change{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			fmt.Fprintf(stderr, "gdq: %v\n", err)
+			return exitUsage
+		}
+		return code
+	}
+
+	// Changes are printed in a grep-friendly format:
+	//
+	//	breaking: question_removed: question 'team' was removed: stored answers to it are rejected
+	//	change: question_added: question 'feedback' was added
+	for _, change := range changes {
+		severity := "change"
+		if change.Breaking {
+			severity = "breaking"
+		}
+		fmt.Fprintf(stdout, "%s: %s: %s\n", severity, change.Type, change.Message)
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "no changes")
+	}
+	return code
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diff", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("should print the changes, flagging the breaking ones", func() {
+		code := run([]string{"diff", "testdata/valid.yaml", "testdata/valid_v2.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+		Expect(stdout.String()).To(Equal(`change: text_changed: text of question 'experience' changed from "Do you have programming experience?" to "Do you have any programming experience?"
+breaking: answer_moved: answer 1 of question 'experience' ("Yes") moved to 2: stored answers 1 now mean "No"
+breaking: answer_moved: answer 2 of question 'experience' ("No") moved to 1: stored answers 2 now mean "Yes"
+change: condition_changed: condition of question 'language' changed from 'answers["experience"] == 1' to 'answers["experience"] == 2'
+change: answer_added: answer 3 ("Rust") was added to question 'language'
+`))
+	})
+
+	It("should print the changes as JSON", func() {
+		code := run([]string{"diff", "-format", "json", "testdata/valid.yaml", "testdata/valid_v2.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+
+		var changes []gdq.Change
+		Expect(json.Unmarshal(stdout.Bytes(), &changes)).To(Succeed())
+		Expect(changes).To(HaveLen(5))
+		Expect(changes[1].Type).To(Equal(gdq.AnswerMovedChange))
+		Expect(changes[1].Breaking).To(BeTrue())
+	})
+
+	It("should report identical versions", func() {
+		Expect(run([]string{"diff", "testdata/valid.yaml", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal("no changes\n"))
+	})
+
+	It("should succeed when no change is breaking", func() {
+		Expect(run([]string{"diff", "testdata/valid.yaml", "testdata/warnings.yaml"}, stdout, stderr)).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal(`change: condition_changed: condition of question 'language' changed from 'answers["experience"] == 1' to 'answers["experience"] == 3'
+change: closing_remark_removed: closing remark 'thanks' was removed
+`))
+	})
+
+	It("should fail when a file can't be loaded", func() {
+		Expect(run([]string{"diff", "testdata/valid.yaml", "testdata/invalid.yaml"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("gdq: "))
+	})
+
+	It("should print the usage without both files", func() {
+		Expect(run([]string{"diff", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq diff"))
+	})
+})
@@ -0,0 +1,79 @@
+// Command gdq is a command-line tool for questionnaire authors.
+//
+// Usage:
+//
+//	gdq <command> [arguments]
+//
+// The commands are:
+//
+//	validate    check questionnaire files and report errors and warnings
+//	run         walk through a questionnaire interactively in the terminal
+//	simulate    answer a questionnaire randomly many times and report coverage and errors
+//	convert     convert a questionnaire between YAML and JSON
+//	stats       print the complexity measures of a questionnaire
+//	diff        compare two versions of a questionnaire, flagging the changes breaking stored answers
+//
+// Run "gdq <command> -h" for the arguments of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes returned by the commands.
+const (
+	exitOK      = 0 // The command succeeded
+	exitInvalid = 1 // A questionnaire is invalid or fails a check
+	exitUsage   = 2 // The command line is invalid or a file can't be loaded
+)
+
+// command is a gdq sub-command.
+type command struct {
+	name    string                                            // Name used on the command line
+	summary string                                            // One-line description shown in the usage
+	run     func(args []string, stdout, stderr io.Writer) int // Runs the command and returns the exit code
+}
+
+// commands lists the available sub-commands, in the order they are shown in the usage.
+var commands = []command{
+	{name: "validate", summary: "check questionnaire files and report errors and warnings", run: runValidate},
+	{name: "run", summary: "walk through a questionnaire interactively in the terminal", run: runRun},
+	{name: "simulate", summary: "answer a questionnaire randomly many times and report coverage and errors", run: runSimulate},
+	{name: "convert", summary: "convert a questionnaire between YAML and JSON", run: runConvert},
+	{name: "stats", summary: "print the complexity measures of a questionnaire", run: runStats},
+	{name: "diff", summary: "compare two versions of a questionnaire, flagging the changes breaking stored answers", run: runDiff},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches the arguments to the requested command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return exitUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "gdq: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitUsage
+}
+
+// usage prints the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gdq <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// stdin is the input the interactive commands read the answers from.
+var stdin io.Reader = os.Stdin
+
+// errEndOfInput is returned when the input ends before the questionnaire is completed.
+var errEndOfInput = errors.New("unexpected end of input")
+
+// runRun implements "gdq run": it walks through a questionnaire in the terminal, reading the answers from stdin,
+// with the progress after every step and the closing remarks once completed.
+//
+// Answers rejected by the questionnaire are reported and asked again. The exit code is exitOK once the questionnaire
+// is completed, exitInvalid when it fails, and exitUsage when the file can't be loaded or the input ends early.
+func runRun(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	locale := flags.String("locale", "", "locale of the texts, e.g. fr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq run [flags] <file.yaml|file.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	q, err := gdq.New(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+	var opts []gdq.NextOption
+	if *locale != "" {
+		opts = append(opts, gdq.WithLocale(*locale))
+	}
+
+	input := bufio.NewScanner(stdin)
+	answers := map[string]gdq.Answer{}
+	response, err := q.NextAnswers(answers, opts...)
+	// Once completed, the optional questions left are still asked
+	for err == nil && len(response.Questions) > 0 {
+		fmt.Fprintf(stdout, "\nProgress: %d/%d (%d%%)\n", response.Progress.Current, response.Progress.Total, response.Progress.Percent)
+		merged := maps.Clone(answers)
+		for _, question := range response.Questions {
+			answer, err := ask(input, stdout, question)
+			if err != nil {
+				fmt.Fprintf(stderr, "gdq: %v\n", err)
+				return exitUsage
+			}
+			merged[question.Id] = answer
+		}
+
+		// The answers of a step are asked again together when the questionnaire rejects them
+		next, nextErr := q.NextAnswers(merged, opts...)
+		if len(gdq.ValidationErrors(nextErr)) > 0 {
+			fmt.Fprintf(stdout, "Invalid answers: %s\n", gdq.LocalizeError(nextErr, *locale))
+			continue
+		}
+		answers, response, err = merged, next, nextErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitInvalid
+	}
+	printClosingRemarks(stdout, response.ClosingRemarks)
+	return exitOK
+}
+
+// ask prints the question and reads its answer, asking again until the input is a valid answer.
+func ask(input *bufio.Scanner, stdout io.Writer, question gdq.Question) (gdq.Answer, error) {
+	fmt.Fprintf(stdout, "\n%s\n", question.Text)
+	for _, text := range []string{question.Description, question.Help} {
+		if text != "" {
+			fmt.Fprintf(stdout, "  %s\n", text)
+		}
+	}
+	for i, answer := range question.Answers {
+		fmt.Fprintf(stdout, "  %d) %s\n", i+1, answer)
+	}
+
+	for {
+		fmt.Fprintf(stdout, "%s: ", prompt(question))
+		if !input.Scan() {
+			if err := input.Err(); err != nil {
+				return gdq.Answer{}, err
+			}
+			return gdq.Answer{}, errEndOfInput
+		}
+		answer, err := parseAnswer(question, strings.TrimSpace(input.Text()))
+		if err == nil {
+			return answer, nil
+		}
+		fmt.Fprintf(stdout, "Invalid answer: %v\n", err)
+	}
+}
+
+// prompt returns the prompt asking for the answer of the question, depending on its type.
+func prompt(question gdq.Question) string {
+	var p string
+	switch question.Type {
+	case gdq.MultipleChoiceQuestion:
+		p = fmt.Sprintf("Select answers separated by commas (1-%d)", len(question.Answers))
+	case gdq.TextQuestion:
+		p = "Answer"
+	case gdq.NumberQuestion:
+		p = "Enter a number"
+	case gdq.NPSQuestion:
+		p = "Score (0-10)"
+	default:
+		p = fmt.Sprintf("Select an answer (1-%d)", len(question.Answers))
+	}
+	if question.Optional || question.Skippable {
+		p += ", or leave empty to skip"
+	}
+	return p
+}
+
+// parseAnswer parses the input as an answer to the question, depending on its type.
+// The displayed answer choices are converted into their canonical values (see gdq.Question.AnswerIndices).
+func parseAnswer(question gdq.Question, input string) (gdq.Answer, error) {
+	if input == "" {
+		if question.Optional || question.Skippable {
+			return gdq.Skipped(), nil
+		}
+		return gdq.Answer{}, errors.New("an answer is required")
+	}
+
+	switch question.Type {
+	case gdq.TextQuestion:
+		return gdq.Text(input), nil
+	case gdq.NumberQuestion:
+		number, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return gdq.Answer{}, fmt.Errorf("%q is not a number", input)
+		}
+		return gdq.Number(number), nil
+	case gdq.NPSQuestion:
+		score, err := strconv.Atoi(input)
+		if err != nil || score < 0 || score > 10 {
+			return gdq.Answer{}, fmt.Errorf("%q is not a score from 0 to 10", input)
+		}
+		return gdq.Choice(score), nil
+	case gdq.MultipleChoiceQuestion:
+		var choices []int
+		for field := range strings.SplitSeq(input, ",") {
+			choice, err := parseChoice(question, strings.TrimSpace(field))
+			if err != nil {
+				return gdq.Answer{}, err
+			}
+			choices = append(choices, choice)
+		}
+		return gdq.Choices(choices...), nil
+	default:
+		choice, err := parseChoice(question, input)
+		if err != nil {
+			return gdq.Answer{}, err
+		}
+		return gdq.Choice(choice), nil
+	}
+}
+
+// parseChoice parses the input as the number of a displayed answer choice and returns its canonical value.
+func parseChoice(question gdq.Question, input string) (int, error) {
+	displayed, err := strconv.Atoi(input)
+	if err != nil || displayed < 1 || displayed > len(question.Answers) {
+		return 0, fmt.Errorf("%q is not an answer from 1 to %d", input, len(question.Answers))
+	}
+	if question.AnswerIndices != nil {
+		return question.AnswerIndices[displayed-1], nil
+	}
+	return displayed, nil
+}
+
+// printClosingRemarks prints the closing remarks of the completed questionnaire.
+func printClosingRemarks(w io.Writer, remarks []gdq.ClosingRemark) {
+	fmt.Fprintln(w, "\nQuestionnaire completed.")
+	for _, remark := range remarks {
+		fmt.Fprintf(w, "%s\n", remark.Text)
+	}
+}
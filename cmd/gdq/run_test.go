@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("run command", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		original := stdin
+		DeferCleanup(func() { stdin = original })
+	})
+
+	It("should walk through the questionnaire until completed", func() {
+		stdin = strings.NewReader("1\n2\n")
+		code := run([]string{"run", "testdata/valid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal(`
+Progress: 0/2 (0%)
+
+Do you have programming experience?
+  1) Yes
+  2) No
+Select an answer (1-2): 
+Progress: 1/2 (50%)
+
+Which language do you prefer?
+  1) Go
+  2) Python
+Select an answer (1-2): 
+Questionnaire completed.
+Thank you!
+`))
+	})
+
+	It("should ask again for invalid answers", func() {
+		stdin = strings.NewReader("3\nyes\n2\n")
+		code := run([]string{"run", "testdata/valid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitOK))
+		Expect(stdout.String()).To(ContainSubstring(`Invalid answer: "3" is not an answer from 1 to 2`))
+		Expect(stdout.String()).To(ContainSubstring(`Invalid answer: "yes" is not an answer from 1 to 2`))
+		Expect(stdout.String()).To(HaveSuffix("Thank you!\n"))
+	})
+
+	It("should fail when the input ends before completion", func() {
+		stdin = strings.NewReader("1\n")
+		code := run([]string{"run", "testdata/valid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitUsage))
+		Expect(stderr.String()).To(Equal("gdq: unexpected end of input\n"))
+	})
+
+	It("should print the usage without file", func() {
+		Expect(run([]string{"run"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq run"))
+	})
+})
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// simulationReport is the result of the random runs of a questionnaire.
+type simulationReport struct {
+	File              string         `json:"file"`
+	Seed              uint64         `json:"seed"`
+	Runs              int            `json:"runs"`
+	Completed         int            `json:"completed"`                     // Runs reaching the end of the questionnaire, terminated ones included
+	Terminated        int            `json:"terminated"`                    // Runs ended early by the questionnaire
+	Failed            int            `json:"failed"`                        // Runs on which Next failed
+	ErrorRate         float64        `json:"error_rate"`                    // Failed runs, in percent of the runs
+	Errors            map[string]int `json:"errors,omitempty"`              // Failures by validation error type, "error" for the other failures
+	Paths             int            `json:"paths"`                         // Distinct sets of answers completing the questionnaire
+	Questions         int            `json:"questions"`                     // Questions of the questionnaire
+	QuestionsShown    int            `json:"questions_shown"`               // Questions shown on at least one run
+	NeverShown        []string       `json:"never_shown,omitempty"`         // Questions shown on no run, in configuration order
+	Remarks           int            `json:"remarks"`                       // Closing remarks of the questionnaire
+	RemarksShown      int            `json:"remarks_shown"`                 // Closing remarks shown on at least one run
+	RemarksNeverShown []string       `json:"remarks_never_shown,omitempty"` // Closing remarks shown on no run, in configuration order
+}
+
+// runSimulate implements "gdq simulate": it answers a questionnaire randomly many times and reports
+// how the runs ended, the questions and closing remarks they covered, and the errors met,
+// to smoke-test complex logic. Hidden questions are never shown, so they are always reported as such.
+//
+// The exit code is exitOK when every run succeeded, exitInvalid when a run failed,
+// and exitUsage when the file can't be loaded.
+func runSimulate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	runs := flags.Int("n", 1000, "number of random runs")
+	seed := flags.Uint64("seed", 0, "seed of the random answers, to reproduce a simulation (random when 0)")
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq simulate [flags] <file.yaml|file.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 || *runs < 1 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	q, err := gdq.New(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+
+	report := simulate(q, *runs, rand.New(rand.NewPCG(*seed, *seed)))
+	report.File, report.Seed = flags.Arg(0), *seed
+	code := exitOK
+	if report.Failed > 0 {
+		code = exitInvalid
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "gdq: %v\n", err)
+			return exitUsage
+		}
+		return code
+	}
+	printSimulation(stdout, report)
+	return code
+}
+
+// simulate answers the questionnaire randomly on every run, until it is completed or Next fails.
+func simulate(q gdq.Questionnaire, runs int, random *rand.Rand) simulationReport {
+	report := simulationReport{Runs: runs}
+	shown := make(map[string]bool)
+	remarksShown := make(map[string]bool)
+	paths := make(map[string]bool)
+
+	for range runs {
+		answers := map[string]gdq.Answer{}
+		response, err := q.NextAnswers(answers)
+		for err == nil && len(response.Questions) > 0 {
+			for _, question := range response.Questions {
+				shown[question.Id] = true
+				answers[question.Id] = randomAnswer(question, random)
+			}
+			response, err = q.NextAnswers(answers)
+		}
+
+		if err != nil {
+			report.Failed++
+			if report.Errors == nil {
+				report.Errors = make(map[string]int)
+			}
+			if validationErrs := gdq.ValidationErrors(err); len(validationErrs) > 0 {
+				report.Errors[validationErrs[0].Type]++
+			} else {
+				report.Errors["error"]++
+			}
+			continue
+		}
+		report.Completed++
+		if response.Terminated {
+			report.Terminated++
+		}
+		for _, remark := range response.ClosingRemarks {
+			remarksShown[remark.Id] = true
+		}
+		paths[pathKey(answers)] = true
+	}
+
+	report.ErrorRate = float64(report.Failed) * 100 / float64(runs)
+	report.Paths = len(paths)
+	for _, question := range q.Questions() {
+		report.Questions++
+		if shown[question.Id] {
+			report.QuestionsShown++
+		} else {
+			report.NeverShown = append(report.NeverShown, question.Id)
+		}
+	}
+	for _, remark := range q.ClosingRemarks() {
+		report.Remarks++
+		if remarksShown[remark.Id] {
+			report.RemarksShown++
+		} else {
+			report.RemarksNeverShown = append(report.RemarksNeverShown, remark.Id)
+		}
+	}
+	return report
+}
+
+// randomAnswer returns a random valid-looking answer to the question, depending on its type.
+// Optional questions are skipped once in five, and multiple choice questions respect their choice limits
+// and exclusive answers; they are skipped when too few options are displayed to meet min_choices,
+// so that the run fails if the question can't be skipped. The answers of text questions are all the same.
+func randomAnswer(question gdq.Question, random *rand.Rand) gdq.Answer {
+	if (question.Optional || question.Skippable) && random.IntN(5) == 0 {
+		return gdq.Skipped()
+	}
+	canonical := func(displayed int) int {
+		if question.AnswerIndices != nil {
+			return question.AnswerIndices[displayed]
+		}
+		return displayed + 1
+	}
+
+	switch question.Type {
+	case gdq.TextQuestion:
+		return gdq.Text("simulated answer")
+	case gdq.NumberQuestion:
+		return gdq.Number(randomNumber(question, random))
+	case gdq.NPSQuestion:
+		return gdq.Choice(random.IntN(11))
+	case gdq.MultipleChoiceQuestion:
+		// Conditional answer options may leave fewer options displayed than the choice limits
+		maxChoices := len(question.Answers)
+		if question.MaxChoices > 0 {
+			maxChoices = min(question.MaxChoices, maxChoices)
+		}
+		minChoices := max(question.MinChoices, 1)
+		if minChoices > maxChoices {
+			return gdq.Skipped()
+		}
+		count := minChoices + random.IntN(maxChoices-minChoices+1)
+
+		var choices []int
+		for i, displayed := range random.Perm(len(question.Answers)) {
+			if question.Exclusive != nil && question.Exclusive[displayed] {
+				if i == 0 && minChoices == 1 {
+					return gdq.Choices(canonical(displayed))
+				}
+				continue
+			}
+			if choices = append(choices, canonical(displayed)); len(choices) == count {
+				break
+			}
+		}
+		return gdq.Choices(choices...)
+	default:
+		if len(question.Answers) == 0 {
+			return gdq.Skipped()
+		}
+		return gdq.Choice(canonical(random.IntN(len(question.Answers))))
+	}
+}
+
+// randomNumber returns a random whole number within the bounds of a number question: between 0 and 100
+// narrowed to its min and max, or the 100 numbers next to a bound outside this range.
+// A number question whose bounds hold no whole number is answered with its min.
+func randomNumber(question gdq.Question, random *rand.Rand) float64 {
+	low, high := 0.0, 100.0
+	if question.Min != nil {
+		if low = *question.Min; low > high {
+			high = low + 100
+		}
+	}
+	if question.Max != nil {
+		if high = *question.Max; high < low {
+			low = high - 100
+		}
+	}
+	low, high = math.Ceil(low), math.Floor(high)
+	if low > high {
+		return *question.Min
+	}
+	return low + float64(random.Int64N(int64(high-low)+1))
+}
+
+// pathKey identifies the answers completing a run, e.g. "experience=1,language=2".
+func pathKey(answers map[string]gdq.Answer) string {
+	parts := make([]string, 0, len(answers))
+	for _, id := range slices.Sorted(maps.Keys(answers)) {
+		parts = append(parts, fmt.Sprintf("%s=%v", id, answers[id].Value()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// printSimulation prints a simulation report in a human-readable format:
+//
+//	survey.yaml: 1000 runs (seed 42)
+//	  completed: 1000 (120 terminated early)
+//	  failed: 0 (0.0%)
+//	  distinct paths: 6
+//	  questions shown: 5/6
+//	    never shown: legacy
+//	  closing remarks shown: 2/2
+func printSimulation(w io.Writer, report simulationReport) {
+	fmt.Fprintf(w, "%s: %d runs (seed %d)\n", report.File, report.Runs, report.Seed)
+	fmt.Fprintf(w, "  completed: %d (%d terminated early)\n", report.Completed, report.Terminated)
+	fmt.Fprintf(w, "  failed: %d (%.1f%%)\n", report.Failed, report.ErrorRate)
+	for _, errType := range slices.Sorted(maps.Keys(report.Errors)) {
+		fmt.Fprintf(w, "    %s: %d\n", errType, report.Errors[errType])
+	}
+	fmt.Fprintf(w, "  distinct paths: %d\n", report.Paths)
+	fmt.Fprintf(w, "  questions shown: %d/%d\n", report.QuestionsShown, report.Questions)
+	if len(report.NeverShown) > 0 {
+		fmt.Fprintf(w, "    never shown: %s\n", strings.Join(report.NeverShown, ", "))
+	}
+	fmt.Fprintf(w, "  closing remarks shown: %d/%d\n", report.RemarksShown, report.Remarks)
+	if len(report.RemarksNeverShown) > 0 {
+		fmt.Fprintf(w, "    never shown: %s\n", strings.Join(report.RemarksNeverShown, ", "))
+	}
+}
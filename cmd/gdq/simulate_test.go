@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand/v2"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("simulate", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("should report the coverage of the random runs", func() {
+		code := run([]string{"simulate", "-n", "200", "-seed", "42", "testdata/valid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal(`testdata/valid.yaml: 200 runs (seed 42)
+  completed: 200 (0 terminated early)
+  failed: 0 (0.0%)
+  distinct paths: 3
+  questions shown: 2/2
+  closing remarks shown: 1/1
+`))
+	})
+
+	It("should report the unreachable questions and the failed runs", func() {
+		code := run([]string{"simulate", "--n", "100", "--seed", "7", "--format", "json", "testdata/unreachable.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+
+		var report simulationReport
+		Expect(json.Unmarshal(stdout.Bytes(), &report)).To(Succeed())
+		Expect(report.Runs).To(Equal(100))
+		Expect(report.Completed + report.Failed).To(Equal(100))
+		Expect(report.Failed).To(BeNumerically(">", 0))
+		Expect(report.Errors).To(Equal(map[string]int{"rule_violation": report.Failed}))
+		Expect(report.ErrorRate).To(BeNumerically("==", report.Failed))
+		Expect(report.Paths).To(BeNumerically(">", 1))
+		Expect(report.NeverShown).To(Equal([]string{"legacy"}))
+		Expect(report.RemarksNeverShown).To(Equal([]string{"legacy_thanks"}))
+	})
+
+	It("should fail the runs whose conditional options can't meet the choice limits", func() {
+		code := run([]string{"simulate", "-n", "100", "-seed", "5", "-format", "json", "testdata/hidden_options.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+
+		var report simulationReport
+		Expect(json.Unmarshal(stdout.Bytes(), &report)).To(Succeed())
+		Expect(report.Completed + report.Failed).To(Equal(100))
+		Expect(report.Completed).To(BeNumerically(">", 0))
+		Expect(report.Failed).To(BeNumerically(">", 0))
+	})
+
+	It("should draw numbers within the bounds of the questions", func() {
+		bound := func(b float64) *float64 { return &b }
+		random := rand.New(rand.NewPCG(1, 2))
+		for _, c := range []struct {
+			min, max  *float64
+			low, high float64
+		}{
+			{nil, nil, 0, 100},
+			{bound(18), bound(30), 18, 30},
+			{bound(500), nil, 500, 600},
+			{nil, bound(-20), -120, -20},
+			{bound(2.5), bound(2.7), 2.5, 2.7},
+		} {
+			question := gdq.Question{Id: "number", Type: gdq.NumberQuestion, Min: c.min, Max: c.max}
+			for range 100 {
+				Expect(randomNumber(question, random)).To(BeNumerically("~", (c.low+c.high)/2, (c.high-c.low)/2))
+			}
+		}
+	})
+
+	It("should be reproducible with a seed", func() {
+		first := &bytes.Buffer{}
+		run([]string{"simulate", "-seed", "3", "-format", "json", "testdata/unreachable.yaml"}, first, stderr)
+		run([]string{"simulate", "-seed", "3", "-format", "json", "testdata/unreachable.yaml"}, stdout, stderr)
+		Expect(stdout.String()).To(Equal(first.String()))
+	})
+
+	It("should print the usage without file", func() {
+		Expect(run([]string{"simulate"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq simulate"))
+	})
+
+	It("should reject an invalid number of runs", func() {
+		Expect(run([]string{"simulate", "-n", "0", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq simulate"))
+	})
+})
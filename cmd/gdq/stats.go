@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// statsReport is the complexity report of a questionnaire file.
+type statsReport struct {
+	File string `json:"file"`
+	gdq.Stats
+}
+
+// runStats implements "gdq stats": it prints the complexity measures of a questionnaire (see gdq.Stats).
+//
+// The exit code is exitOK when the measures are printed, and exitUsage when the file can't be loaded.
+func runStats(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq stats [flags] <file.yaml|file.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	q, err := gdq.New(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "gdq: %v\n", err)
+		return exitUsage
+	}
+	report := statsReport{File: flags.Arg(0), Stats: q.Stats()}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "gdq: %v\n", err)
+			return exitUsage
+		}
+		return exitOK
+	}
+	printStats(stdout, report)
+	return exitOK
+}
+
+// printStats prints a complexity report in a human-readable format:
+//
+//	survey.yaml:
+//	  questions: 6 (choice: 5, consent: 1)
+//	  conditional questions: 4
+//	  answers: 14
+//	  closing remarks: 2
+//	  depth: 3
+//	  branch factor: 2.00
+//	  terminal outcomes: 5 (1 terminated early)
+//	  longest path: 4 questions
+func printStats(w io.Writer, report statsReport) {
+	types := make([]string, 0, len(report.QuestionsByType))
+	for _, questionType := range slices.Sorted(maps.Keys(report.QuestionsByType)) {
+		types = append(types, fmt.Sprintf("%s: %d", questionType, report.QuestionsByType[questionType]))
+	}
+
+	fmt.Fprintf(w, "%s:\n", report.File)
+	fmt.Fprintf(w, "  questions: %d (%s)\n", report.Questions, strings.Join(types, ", "))
+	if report.HiddenQuestions > 0 {
+		fmt.Fprintf(w, "  hidden questions: %d\n", report.HiddenQuestions)
+	}
+	fmt.Fprintf(w, "  conditional questions: %d\n", report.ConditionalQuestions)
+	fmt.Fprintf(w, "  answers: %d\n", report.Answers)
+	fmt.Fprintf(w, "  closing remarks: %d\n", report.ClosingRemarks)
+	fmt.Fprintf(w, "  depth: %d\n", report.Depth)
+	fmt.Fprintf(w, "  branch factor: %.2f\n", report.BranchFactor)
+	atLeast := ""
+	if report.Truncated {
+		atLeast = "at least "
+	}
+	fmt.Fprintf(w, "  terminal outcomes: %s%d (%d terminated early)\n", atLeast, report.Outcomes, report.TerminatedOutcomes)
+	fmt.Fprintf(w, "  longest path: %s%d questions\n", atLeast, report.LongestPath)
+	if report.Truncated {
+		fmt.Fprintln(w, "  (some answer paths couldn't be explored)")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("stats", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("should print the complexity measures", func() {
+		Expect(run([]string{"stats", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal(`testdata/valid.yaml:
+  questions: 2 (choice: 2)
+  conditional questions: 1
+  answers: 4
+  closing remarks: 1
+  depth: 2
+  branch factor: 1.00
+  terminal outcomes: 2 (0 terminated early)
+  longest path: 2 questions
+`))
+	})
+
+	It("should print the complexity measures as JSON", func() {
+		Expect(run([]string{"stats", "-format", "json", "testdata/valid.yaml"}, stdout, stderr)).To(Equal(exitOK))
+
+		var report statsReport
+		Expect(json.Unmarshal(stdout.Bytes(), &report)).To(Succeed())
+		Expect(report.File).To(Equal("testdata/valid.yaml"))
+		Expect(report.Questions).To(Equal(2))
+		Expect(report.Depth).To(Equal(2))
+		Expect(report.Outcomes).To(Equal(2))
+		Expect(report.LongestPath).To(Equal(2))
+	})
+
+	It("should fail when the file can't be loaded", func() {
+		Expect(run([]string{"stats", "testdata/missing.yaml"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("gdq: "))
+	})
+
+	It("should print the usage without file", func() {
+		Expect(run([]string{"stats"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq stats"))
+	})
+})
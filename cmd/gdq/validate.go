@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// fileReport is the validation result of a single questionnaire file.
+type fileReport struct {
+	File     string                `json:"file"`
+	Error    string                `json:"error,omitempty"` // Set when the file can't be loaded at all
+	Errors   []gdq.ValidationError `json:"errors,omitempty"`
+	Warnings []gdq.Warning         `json:"warnings,omitempty"`
+}
+
+// runValidate implements "gdq validate": it lints every file and prints the problems found.
+//
+// The exit code is exitOK when every file is valid, exitInvalid when a file has errors
+// (or warnings with -fail-on-warnings), and exitUsage when a file can't be loaded.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	strict := flags.Bool("strict", false, "type-check conditions and verify the questions they reference")
+	failOnWarnings := flags.Bool("fail-on-warnings", false, "exit with an error when warnings are found")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gdq validate [flags] <file.yaml|file.json>...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var opts []gdq.Option
+	if *strict {
+		opts = append(opts, gdq.WithStrictValidation())
+	}
+
+	code := exitOK
+	reports := make([]fileReport, 0, flags.NArg())
+	for _, file := range flags.Args() {
+		report := fileReport{File: file}
+		lint, err := gdq.Lint(file, opts...)
+		switch {
+		case err != nil:
+			report.Error = err.Error()
+			code = exitUsage
+		case lint.HasErrors(), *failOnWarnings && len(lint.Warnings) > 0:
+			if code == exitOK {
+				code = exitInvalid
+			}
+		}
+		report.Errors = lint.Errors
+		report.Warnings = lint.Warnings
+		reports = append(reports, report)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "gdq: %v\n", err)
+			return exitUsage
+		}
+		return code
+	}
+
+	for _, report := range reports {
+		printReport(stdout, report)
+	}
+	return code
+}
+
+// printReport prints a file report in a human-readable, grep-friendly format:
+//
+//	survey.yaml: error: empty_answers: question has no answer options
+//	survey.yaml: warning: unreachable_question: question 'q2' can never be shown: ...
+func printReport(w io.Writer, report fileReport) {
+	if report.Error != "" {
+		fmt.Fprintf(w, "%s: error: %s\n", report.File, report.Error)
+		return
+	}
+	for _, err := range report.Errors {
+		fmt.Fprintf(w, "%s: error: %s: %s\n", report.File, err.Type, err.Message)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "%s: warning: %s: %s\n", report.File, warning.Type, warning.Message)
+	}
+	if len(report.Errors) == 0 && len(report.Warnings) == 0 {
+		fmt.Fprintf(w, "%s: ok\n", report.File)
+	}
+}
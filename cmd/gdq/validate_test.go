@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validate", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("should report valid files", func() {
+		code := run([]string{"validate", "testdata/valid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitOK))
+		Expect(stdout.String()).To(Equal("testdata/valid.yaml: ok\n"))
+	})
+
+	It("should report errors with a failing exit code", func() {
+		code := run([]string{"validate", "testdata/valid.yaml", "testdata/invalid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+		Expect(stdout.String()).To(Equal("testdata/valid.yaml: ok\n" +
+			"testdata/invalid.yaml: error: empty_answers: question has no answer options\n"))
+	})
+
+	It("should only fail on warnings when asked to", func() {
+		code := run([]string{"validate", "testdata/warnings.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitOK))
+		Expect(stdout.String()).To(ContainSubstring("testdata/warnings.yaml: warning: unreachable_question: question 'language' can never be shown"))
+
+		code = run([]string{"validate", "-fail-on-warnings", "testdata/warnings.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+	})
+
+	It("should print JSON reports", func() {
+		code := run([]string{"validate", "-format", "json", "testdata/invalid.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitInvalid))
+
+		var reports []map[string]interface{}
+		Expect(json.Unmarshal(stdout.Bytes(), &reports)).To(Succeed())
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0]).To(HaveKeyWithValue("file", "testdata/invalid.yaml"))
+		Expect(reports[0]["errors"]).To(ConsistOf(HaveKeyWithValue("type", "empty_answers")))
+	})
+
+	It("should fail when a file can't be loaded", func() {
+		code := run([]string{"validate", "testdata/missing.yaml"}, stdout, stderr)
+		Expect(code).To(Equal(exitUsage))
+		Expect(stdout.String()).To(HavePrefix("testdata/missing.yaml: error: failed to load config"))
+	})
+
+	It("should print the usage without files", func() {
+		Expect(run([]string{"validate"}, stdout, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(HavePrefix("Usage: gdq validate"))
+	})
+})
+
+var _ = Describe("run", func() {
+	It("should reject unknown commands", func() {
+		stderr := &bytes.Buffer{}
+		Expect(run([]string{"unknown"}, &bytes.Buffer{}, stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(ContainSubstring(`unknown command "unknown"`))
+		Expect(stderr.String()).To(ContainSubstring("validate"))
+	})
+})
@@ -0,0 +1,71 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// compileComputedValues compiles the expressions of the computed block, which derive values from the answers:
+//
+//	computed:
+//	  bmi: 'values["weight"] / (values["height"] * values["height"])'
+//
+// Computed values are exposed to conditions through computed (e.g. computed["bmi"] >= 25)
+// and returned in the completed response. They can't reference other computed values.
+func (q *questionnaire) compileComputedValues() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(q.Computed)) {
+		expression := q.Computed[name]
+		switch {
+		case expression == "":
+			errs = append(errs, invalidConditionError("computed", name, expression, errors.New("computed value has no expression")))
+		case referencesIdentifier(expression, "computed"):
+			errs = append(errs, invalidConditionError("computed", name, expression, errors.New("computed values can't reference computed values")))
+		default:
+			errs = append(errs, q.compileExpression(expression, "computed", name, false))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// computeValues evaluates the computed values with the answers.
+// It returns nil when the questionnaire has no computed value.
+func (q *questionnaire) computeValues(answers map[string]int) (map[string]interface{}, error) {
+	if len(q.Computed) == 0 {
+		return nil, nil
+	}
+
+	computed := make(map[string]interface{}, len(q.Computed))
+	for name, expression := range q.Computed {
+		value, err := q.evaluateExpression(expression, answers, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute value '%s': %w", name, err)
+		}
+		computed[name] = value
+	}
+	return computed, nil
+}
+
+// extractReferences extracts the question IDs referenced by a condition (see extractQuestionIDs),
+// including the questions referenced by the computed values it uses.
+func extractReferences(condition string, computed map[string]string) []string {
+	ids := extractQuestionIDs(condition)
+	if len(computed) == 0 || !referencesIdentifier(condition, "computed") {
+		return ids
+	}
+
+	names := indexedKeys(condition, "computed")
+	if usesWholeMap(condition, "computed") {
+		names = slices.Sorted(maps.Keys(computed))
+	}
+	for _, name := range names {
+		for _, id := range extractQuestionIDs(computed[name]) {
+			if !contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,332 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Change describes a difference between two versions of a questionnaire (see Diff).
+type Change struct {
+	Type       string `json:"type"`                  // The kind of change (e.g. QuestionRemovedChange)
+	QuestionID string `json:"question_id,omitempty"` // The question concerned by the change, if any
+	RemarkID   string `json:"remark_id,omitempty"`   // The closing remark concerned by the change, if any
+	Breaking   bool   `json:"breaking,omitempty"`    // Whether answers stored for the previous version may be rejected or change meaning
+	Message    string `json:"message"`               // Human-readable description of the change
+}
+
+const (
+	// QuestionAddedChange is the Change type of questions only in the current version.
+	QuestionAddedChange = "question_added"
+
+	// QuestionRemovedChange is the Change type of questions only in the previous version.
+	// It is breaking: stored answers to the question are rejected.
+	QuestionRemovedChange = "question_removed"
+
+	// QuestionTypeChange is the Change type of questions expecting another kind of answer.
+	// It is breaking: stored answers may not be valid anymore.
+	QuestionTypeChange = "question_type_changed"
+
+	// TextChange is the Change type of rephrased questions, answers and closing remarks.
+	TextChange = "text_changed"
+
+	// ConditionChange is the Change type of the conditions of questions, answers and closing remarks
+	// that changed, repeating groups, macros and when rules expanded.
+	ConditionChange = "condition_changed"
+
+	// RequirementChange is the Change type of questions becoming required, optional or skippable.
+	// It is breaking when the question doesn't accept the skip answer anymore.
+	RequirementChange = "requirement_changed"
+
+	// ChoiceLimitsChange is the Change type of multiple choice questions whose choice limits changed.
+	// It is breaking when the limits are stricter.
+	ChoiceLimitsChange = "choice_limits_changed"
+
+	// NumberBoundsChange is the Change type of number questions whose min or max changed.
+	// It is breaking when the bounds are stricter.
+	NumberBoundsChange = "number_bounds_changed"
+
+	// AnswerAddedChange is the Change type of answer options only in the current version.
+	AnswerAddedChange = "answer_added"
+
+	// AnswerRemovedChange is the Change type of answer options only in the previous version.
+	// It is breaking: answers being 1-indexed positions, the stored answers are rejected or change meaning.
+	AnswerRemovedChange = "answer_removed"
+
+	// AnswerMovedChange is the Change type of answer options at another position in the current version.
+	// It is breaking: answers being 1-indexed positions, the stored answers change meaning.
+	AnswerMovedChange = "answer_moved"
+
+	// RemarkAddedChange is the Change type of closing remarks only in the current version.
+	RemarkAddedChange = "closing_remark_added"
+
+	// RemarkRemovedChange is the Change type of closing remarks only in the previous version.
+	RemarkRemovedChange = "closing_remark_removed"
+)
+
+// Diff compares two versions of a questionnaire semantically, rather than textually:
+// questions and closing remarks are matched by ID, and answer options by ID, or by text when they have none.
+// Texts are compared in every locale and shown in the default locale of the current version.
+//
+// Changes are returned in the order of the previous version, followed by the additions,
+// and are flagged as breaking when answers stored for the previous version may be rejected
+// or change meaning, e.g. when a question is removed or answer options are reordered:
+//
+//	changes, err := gdq.Diff(previous, current)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, change := range changes {
+//	    if change.Breaking {
+//	        log.Printf("breaking change: %s", change.Message)
+//	    }
+//	}
+//
+// Returns no change when the versions are equivalent, and an error wrapping ErrUnsupportedQuestionnaire
+// when a version is another implementation of Questionnaire than the one returned by New.
+func Diff(previous, current Questionnaire) ([]Change, error) {
+	old, ok := previous.(*questionnaire)
+	if !ok {
+		return nil, fmt.Errorf("%w: previous version is %T", ErrUnsupportedQuestionnaire, previous)
+	}
+	cur, ok := current.(*questionnaire)
+	if !ok {
+		return nil, fmt.Errorf("%w: current version is %T", ErrUnsupportedQuestionnaire, current)
+	}
+	locale := cur.DefaultLocale
+	var changes []Change
+
+	for _, question := range old.QuestionList {
+		if updated := cur.findQuestionByID(question.Id); updated != nil {
+			changes = append(changes, diffQuestion(question, *updated, locale)...)
+			continue
+		}
+		changes = append(changes, Change{
+			Type:       QuestionRemovedChange,
+			QuestionID: question.Id,
+			Breaking:   true,
+			Message:    fmt.Sprintf("question '%s' was removed: stored answers to it are rejected", question.Id),
+		})
+	}
+	for _, question := range cur.QuestionList {
+		if old.findQuestionByID(question.Id) == nil {
+			changes = append(changes, Change{
+				Type:       QuestionAddedChange,
+				QuestionID: question.Id,
+				Message:    fmt.Sprintf("question '%s' was added", question.Id),
+			})
+		}
+	}
+
+	remarks := make(map[string]closingRemark, len(cur.Remarks))
+	for _, remark := range cur.Remarks {
+		remarks[remark.Id] = remark
+	}
+	previousRemarks := make(map[string]bool, len(old.Remarks))
+	for _, remark := range old.Remarks {
+		previousRemarks[remark.Id] = true
+		updated, ok := remarks[remark.Id]
+		if !ok {
+			changes = append(changes, Change{
+				Type:     RemarkRemovedChange,
+				RemarkID: remark.Id,
+				Message:  fmt.Sprintf("closing remark '%s' was removed", remark.Id),
+			})
+			continue
+		}
+		if !maps.Equal(remark.Text, updated.Text) {
+			changes = append(changes, Change{
+				Type:     TextChange,
+				RemarkID: remark.Id,
+				Message: fmt.Sprintf("text of closing remark '%s' changed from %q to %q",
+					remark.Id, remark.Text.resolve(locale, locale), updated.Text.resolve(locale, locale)),
+			})
+		}
+		if remark.Condition != updated.Condition {
+			changes = append(changes, Change{
+				Type:     ConditionChange,
+				RemarkID: remark.Id,
+				Message: fmt.Sprintf("condition of closing remark '%s' changed from %s to %s",
+					remark.Id, describeCondition(remark.Condition), describeCondition(updated.Condition)),
+			})
+		}
+	}
+	for _, remark := range cur.Remarks {
+		if !previousRemarks[remark.Id] {
+			changes = append(changes, Change{
+				Type:     RemarkAddedChange,
+				RemarkID: remark.Id,
+				Message:  fmt.Sprintf("closing remark '%s' was added", remark.Id),
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// diffQuestion compares two versions of a question.
+func diffQuestion(old, cur question, locale string) []Change {
+	var changes []Change
+	change := func(changeType string, breaking bool, format string, args ...any) {
+		changes = append(changes, Change{
+			Type:       changeType,
+			QuestionID: old.Id,
+			Breaking:   breaking,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	if old.kind() != cur.kind() {
+		change(QuestionTypeChange, true, "type of question '%s' changed from %s to %s: stored answers may be rejected",
+			old.Id, old.kind(), cur.kind())
+	}
+	if !maps.Equal(old.Text, cur.Text) {
+		change(TextChange, false, "text of question '%s' changed from %q to %q",
+			old.Id, old.Text.resolve(locale, locale), cur.Text.resolve(locale, locale))
+	}
+	if old.Condition != cur.Condition {
+		change(ConditionChange, false, "condition of question '%s' changed from %s to %s",
+			old.Id, describeCondition(old.Condition), describeCondition(cur.Condition))
+	}
+	if old.TerminateIf != cur.TerminateIf {
+		change(ConditionChange, false, "terminate_if of question '%s' changed from %s to %s",
+			old.Id, describeCondition(old.TerminateIf), describeCondition(cur.TerminateIf))
+	}
+
+	switch {
+	case old.canBeSkipped() && !cur.canBeSkipped():
+		change(RequirementChange, true, "question '%s' can't be skipped anymore: stored skipped answers are rejected", old.Id)
+	case old.isRequired() && !cur.isRequired():
+		change(RequirementChange, false, "question '%s' is now optional", old.Id)
+	case !old.isRequired() && cur.isRequired():
+		change(RequirementChange, false, "question '%s' is now required, but can still be skipped", old.Id)
+	case !old.Skippable && cur.Skippable:
+		change(RequirementChange, false, "question '%s' can now be skipped", old.Id)
+	}
+
+	if old.MinChoices != cur.MinChoices || old.MaxChoices != cur.MaxChoices {
+		stricter := cur.MinChoices > old.MinChoices ||
+			(cur.MaxChoices != 0 && (old.MaxChoices == 0 || cur.MaxChoices < old.MaxChoices))
+		message := "choice limits of question '%s' changed from %s to %s"
+		if stricter {
+			message += ": stored answers may be rejected"
+		}
+		change(ChoiceLimitsChange, stricter, message,
+			old.Id, describeChoiceLimits(old), describeChoiceLimits(cur))
+	}
+
+	if !equalBound(old.Min, cur.Min) || !equalBound(old.Max, cur.Max) {
+		stricter := (cur.Min != nil && (old.Min == nil || *cur.Min > *old.Min)) ||
+			(cur.Max != nil && (old.Max == nil || *cur.Max < *old.Max))
+		message := "bounds of question '%s' changed from %s to %s"
+		if stricter {
+			message += ": stored answers may be rejected"
+		}
+		change(NumberBoundsChange, stricter, message, old.Id, describeBounds(old), describeBounds(cur))
+	}
+
+	return append(changes, diffAnswers(old, cur, locale)...)
+}
+
+// diffAnswers compares the answer options of two versions of a question. Options are matched by ID,
+// or by text when they have none; an option without ID whose text changed while keeping its position is rephrased.
+func diffAnswers(old, cur question, locale string) []Change {
+	var changes []Change
+	change := func(changeType string, breaking bool, format string, args ...any) {
+		changes = append(changes, Change{
+			Type:       changeType,
+			QuestionID: old.Id,
+			Breaking:   breaking,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+	text := func(option answerOption) string {
+		return option.Text.resolve(locale, locale)
+	}
+	positions := func(options []answerOption) map[string]int {
+		keys := make(map[string]int, len(options))
+		for i, option := range options {
+			keys[answerKey(option, locale)] = i
+		}
+		return keys
+	}
+	oldPositions, curPositions := positions(old.Answers), positions(cur.Answers)
+	rephrased := make(map[int]bool)
+	meaning := func(position int) string {
+		if position >= len(cur.Answers) {
+			return "are rejected"
+		}
+		return fmt.Sprintf("now mean %q", text(cur.Answers[position]))
+	}
+
+	for i, option := range old.Answers {
+		j, kept := curPositions[answerKey(option, locale)]
+		if !kept && i < len(cur.Answers) && option.Id == "" && cur.Answers[i].Id == "" {
+			if _, matched := oldPositions[answerKey(cur.Answers[i], locale)]; !matched {
+				j, kept = i, true
+				rephrased[i] = true
+			}
+		}
+
+		switch {
+		case !kept:
+			change(AnswerRemovedChange, true, "answer %d of question '%s' (%q) was removed: stored answers %d %s",
+				i+1, old.Id, text(option), i+1, meaning(i))
+			continue
+		case j != i:
+			change(AnswerMovedChange, true, "answer %d of question '%s' (%q) moved to %d: stored answers %d %s",
+				i+1, old.Id, text(option), j+1, i+1, meaning(i))
+		}
+
+		updated := cur.Answers[j]
+		if !maps.Equal(option.Text, updated.Text) {
+			change(TextChange, false, "text of answer %d of question '%s' changed from %q to %q",
+				j+1, old.Id, text(option), text(updated))
+		}
+		if option.Condition != updated.Condition {
+			change(ConditionChange, false, "condition of answer %d of question '%s' changed from %s to %s",
+				j+1, old.Id, describeCondition(option.Condition), describeCondition(updated.Condition))
+		}
+	}
+	for j, option := range cur.Answers {
+		if _, existed := oldPositions[answerKey(option, locale)]; !existed && !rephrased[j] {
+			change(AnswerAddedChange, false, "answer %d (%q) was added to question '%s'", j+1, text(option), old.Id)
+		}
+	}
+
+	return changes
+}
+
+// answerKey identifies an answer option across versions: its ID, or its text when it has none.
+func answerKey(option answerOption, locale string) string {
+	if option.Id != "" {
+		return "id:" + option.Id
+	}
+	return "text:" + option.Text.resolve(locale, locale)
+}
+
+// describeCondition formats a condition for a change message.
+func describeCondition(condition string) string {
+	if condition == "" {
+		return "none"
+	}
+	return fmt.Sprintf("'%s'", condition)
+}
+
+// equalBound reports whether two versions of a bound of a number question are the same.
+func equalBound(old, cur *float64) bool {
+	return (old == nil) == (cur == nil) && (old == nil || *old == *cur)
+}
+
+// describeBounds formats the bounds of a number question for a change message, e.g. "18-any".
+func describeBounds(q question) string {
+	return describeBound(q.Min) + "-" + describeBound(q.Max)
+}
+
+// describeChoiceLimits formats the choice limits of a multiple choice question for a change message, e.g. "1-3".
+func describeChoiceLimits(q question) string {
+	maxChoices := "any"
+	if q.MaxChoices > 0 {
+		maxChoices = fmt.Sprint(q.MaxChoices)
+	}
+	return fmt.Sprintf("%d-%s", q.MinChoices, maxChoices)
+}
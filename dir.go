@@ -0,0 +1,61 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadDir loads every questionnaire configuration of the directory (.yaml, .yml and .json files, subdirectories excluded)
+// with the options, as New does, and returns them keyed by ID: the top-level id of the configuration when declared,
+// the file name without its extension otherwise. The result can be served as is, e.g. as a gdqhttp.Map:
+//
+//	questionnaires, err := gdq.LoadDir("questionnaires/")
+//	if err != nil {
+//	    return err
+//	}
+//	handler := gdqhttp.Handler(gdqhttp.Map(questionnaires))
+//
+// Every file is loaded, so that all the problems are reported at once: the returned error joins the errors of every
+// invalid file, prefixed with its path, and ValidationErrors extracts their validation errors.
+// Two files with the same ID are an error. No questionnaire is returned when a file fails to load.
+func LoadDir(dir string, opts ...Option) (map[string]Questionnaire, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %q: %w", dir, err)
+	}
+
+	var errs []error
+	questionnaires := make(map[string]Questionnaire)
+	files := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		q, err := New(path, opts...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		id := q.(*questionnaire).Id
+		if id == "" {
+			id = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		if previous, ok := files[id]; ok {
+			errs = append(errs, fmt.Errorf("%s: questionnaire ID '%s' is already used by %s", path, id, previous))
+			continue
+		}
+		questionnaires[id] = q
+		files[id] = path
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return questionnaires, nil
+}
@@ -1,47 +1,166 @@
 package go_dynamic_questionnaire
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
+// ErrEvaluationDepthExceeded is wrapped by the errors returned by Next when a call exceeds
+// the maximum evaluation depth (see WithMaxEvaluationDepth).
+var ErrEvaluationDepthExceeded = errors.New("maximum evaluation depth exceeded")
+
+// ErrAnswerChainBroken is wrapped by the errors returned by VerifyAnswerChain when a link
+// was modified, removed or reordered after the fact.
+var ErrAnswerChainBroken = errors.New("answer chain broken")
+
+// ErrUnsupportedQuestionnaire is wrapped by the errors returned by Diff when a version
+// is another implementation of Questionnaire than the one returned by New.
+var ErrUnsupportedQuestionnaire = errors.New("questionnaire not created by New")
+
 // Error type constants for consistent error identification.
-// These can be used programmatically to handle specific error types.
+// These can be used programmatically to handle specific error types (see ValidationError.Type).
 const (
-	// emptyQuestionIDErrType indicates a question was defined without an ID.
+	// EmptyQuestionIDErrType indicates a question was defined without an ID.
 	// This violates the questionnaire structure requirements.
-	emptyQuestionIDErrType = "empty_question_id"
+	EmptyQuestionIDErrType = "empty_question_id"
 
-	// duplicateQuestionIDErrType indicates multiple questions share the same ID.
+	// DuplicateQuestionIDErrType indicates multiple questions share the same ID.
 	// Question IDs must be unique within a questionnaire.
-	duplicateQuestionIDErrType = "duplicate_question_id"
+	DuplicateQuestionIDErrType = "duplicate_question_id"
 
-	// emptyAnswersErrType indicates a question has no answer options.
+	// EmptyAnswersErrType indicates a question has no answer options.
 	// All questions must have at least one possible answer.
-	emptyAnswersErrType = "empty_answers"
+	EmptyAnswersErrType = "empty_answers"
+
+	// DuplicateAnswerIDErrType indicates multiple answer options of a question share the same ID.
+	// Answer option IDs must be unique within a question.
+	DuplicateAnswerIDErrType = "duplicate_answer_id"
+
+	// InvalidDefaultAnswerErrType indicates a question default is outside the valid range.
+	// Default values must be between 1 and the number of available answers for that question.
+	InvalidDefaultAnswerErrType = "invalid_default_answer"
+
+	// InvalidTimeEstimateErrType indicates a question time estimate is negative.
+	// Time estimates are numbers of seconds.
+	InvalidTimeEstimateErrType = "invalid_time_estimate"
+
+	// InvalidWeightErrType indicates a question progress weight is negative.
+	// Weights are positive; questions without a weight count as 1.
+	InvalidWeightErrType = "invalid_weight"
+
+	// InvalidChoiceLimitsErrType indicates the min_choices or max_choices of a question are invalid.
+	// Limits only apply to multiple choice questions, and must be between 0 and the number of answer options, min below max.
+	InvalidChoiceLimitsErrType = "invalid_choice_limits"
 
-	// invalidQuestionIdErrType indicates an answer was provided for a non-existent question.
+	// InvalidNumberBoundsErrType indicates the min or max of a question are invalid.
+	// Bounds only apply to number questions, min below max.
+	InvalidNumberBoundsErrType = "invalid_number_bounds"
+
+	// InvalidConsentErrType indicates a consent question can't collect consent.
+	// Consent questions must be required, not skippable, without a default answer, and offer at least two answer options.
+	InvalidConsentErrType = "invalid_consent"
+
+	// InvalidQuestionTypeErrType indicates a question declares an unknown type.
+	// Types must be choice (the default), multiple_choice, text, number, nps or consent.
+	InvalidQuestionTypeErrType = "invalid_question_type"
+
+	// InvalidQuestionIDErrType indicates an answer was provided for a non-existent question.
 	// All answer keys must correspond to valid question IDs.
-	invalidQuestionIdErrType = "invalid_question_id"
+	InvalidQuestionIDErrType = "invalid_question_id"
+
+	// InvalidAnswerIDErrType indicates an answer ID doesn't match any answer option of the question.
+	// Answer IDs must correspond to the IDs declared on the answer options.
+	InvalidAnswerIDErrType = "invalid_answer_id"
 
-	// invalidAnswerRangeErrType indicates an answer value is outside the valid range.
+	// InvalidAnswerRangeErrType indicates an answer value is outside the valid range.
 	// Answer values must be between 1 and the number of available answers for that question.
-	invalidAnswerRangeErrType = "invalid_answer_range"
+	InvalidAnswerRangeErrType = "invalid_answer_range"
+
+	// InvalidAnswerTypeErrType indicates an answer doesn't match the type of its question.
+	// For instance, text questions must be answered with text, through NextAnswers.
+	InvalidAnswerTypeErrType = "invalid_answer_type"
+
+	// UnavailableAnswerErrType indicates an answer option was chosen while its condition is not met.
+	// Conditional answer options can only be chosen when they are offered.
+	UnavailableAnswerErrType = "unavailable_answer"
+
+	// UnreachableAnswerErrType indicates a question was answered while it wouldn't be shown given the other answers.
+	// Answer sets checked with ValidateAnswerSet must follow a path through the questionnaire.
+	UnreachableAnswerErrType = "unreachable_answer"
 
-	// invalidDependencyErrType indicates a question depends on a non-existent question.
+	// InvalidChoiceCountErrType indicates too few or too many options were chosen in a multiple choice question.
+	// The number of chosen options must be within the min_choices and max_choices of the question.
+	InvalidChoiceCountErrType = "invalid_choice_count"
+
+	// ExclusiveAnswerErrType indicates an exclusive answer option was chosen along with other options.
+	// Exclusive options (e.g. "None of the above") must be the only option chosen in multiple choice questions.
+	ExclusiveAnswerErrType = "exclusive_answer"
+
+	// InvalidDependencyErrType indicates a question depends on a non-existent question.
 	// All question IDs in depends_on must correspond to valid questions.
-	invalidDependencyErrType = "invalid_dependency"
+	InvalidDependencyErrType = "invalid_dependency"
 
-	// circularDependencyErrType indicates circular dependencies exist in the questionnaire.
+	// CircularDependencyErrType indicates circular dependencies exist in the questionnaire.
 	// Questions cannot depend on themselves directly or indirectly.
-	circularDependencyErrType = "circular_dependency"
+	CircularDependencyErrType = "circular_dependency"
+
+	// InvalidConditionErrType indicates a condition is not a valid expression.
+	// Conditions must be valid expr expressions using the condition environment.
+	InvalidConditionErrType = "invalid_condition"
+
+	// InvalidWhenRuleErrType indicates a structured when rule is incomplete.
+	// Rules must compare a question's answer or combine other rules.
+	InvalidWhenRuleErrType = "invalid_when_rule"
+
+	// InvalidJumpErrType indicates an answer option jumps to a question it can't lead to.
+	// Targets must be existing questions, other than the question itself, jumped to from a single question.
+	InvalidJumpErrType = "invalid_jump"
+
+	// InvalidGroupErrType indicates a repeating group can't be expanded into questions.
+	// Groups must have a unique ID, questions with IDs, a max of at least 1 and repeat for a number question.
+	InvalidGroupErrType = "invalid_group"
+
+	// InvalidQuotaErrType indicates a quota is misconfigured or not declared.
+	// Quotas must have a unique ID, a limit of at least 1 and a remark, and be declared to count answer options toward them.
+	InvalidQuotaErrType = "invalid_quota"
+
+	// InvalidAvailabilityErrType indicates the availability window of the questionnaire is empty.
+	// available_until must be after available_from.
+	InvalidAvailabilityErrType = "invalid_availability"
 
-	// conditionDependencyMismatchErrType indicates condition references don't match depends_on.
+	// NotYetAvailableErrType indicates the questionnaire is answered before its available_from time.
+	// Next can only be called once the questionnaire opens.
+	NotYetAvailableErrType = "not_yet_available"
+
+	// ExpiredQuestionnaireErrType indicates the questionnaire is answered after its available_until time.
+	// Next can't be called anymore once the questionnaire has expired.
+	ExpiredQuestionnaireErrType = "expired_questionnaire"
+
+	// InvalidOptionsSourceErrType indicates a question can't get its answer options from a data provider.
+	// Only choice and multiple_choice questions without answers of their own can declare an options_from source.
+	InvalidOptionsSourceErrType = "invalid_options_source"
+
+	// UnknownQuestionReferenceErrType indicates a condition references a question that doesn't exist.
+	// Only reported in strict validation mode (see WithStrictValidation).
+	UnknownQuestionReferenceErrType = "unknown_question_reference"
+
+	// ConditionEvaluationErrType indicates a condition fails to evaluate for some answers.
+	// Only reported by Lint, which evaluates the conditions along every answer path.
+	ConditionEvaluationErrType = "condition_evaluation"
+
+	// ConditionDependencyMismatchErrType indicates condition references don't match depends_on.
 	// Questions should declare dependencies for all question IDs used in conditions.
-	conditionDependencyMismatchErrType = "condition_dependency_mismatch"
+	ConditionDependencyMismatchErrType = "condition_dependency_mismatch"
+
+	// RuleViolationErrType indicates the answers don't satisfy a consistency rule of the questionnaire.
+	// Its message is the message of the rule in the requested locale, so it has no entry in DefaultMessages.
+	RuleViolationErrType = "rule_violation"
 )
 
-// validationError represents an error that occurs during questionnaire validation.
+// ValidationError represents an error that occurs during questionnaire validation.
 // It provides structured information about validation failures, including
 // error type, descriptive message, and contextual data for debugging.
 //
@@ -50,21 +169,66 @@ const (
 //
 // Example usage:
 //
-//	var validationErr validationError
+//	var validationErr gdq.ValidationError
 //	if errors.As(err, &validationErr) {
 //	    switch validationErr.Type {
-//	    case invalidQuestionIdErrType:
+//	    case gdq.InvalidQuestionIDErrType:
 //	        // Handle invalid question ID specifically
-//	    case invalidAnswerRangeErrType:
+//	    case gdq.InvalidAnswerRangeErrType:
 //	        // Handle out-of-range answer specifically
 //	    }
 //	}
-type validationError struct {
-	Type    string                 // Error type identifier (see constants above)
-	Message string                 // Human-readable error description
-	Context map[string]interface{} // Additional context data for debugging
+type ValidationError struct {
+	Type    string                 `json:"type"`    // Error type identifier (see constants above)
+	Message string                 `json:"message"` // Human-readable error description
+	Context map[string]interface{} `json:"context"` // Additional context data for debugging
 }
 
+// Sentinel errors, one per error type, to be used with errors.Is.
+// A ValidationError matches the sentinel of its type whatever its message and context.
+//
+// Example usage:
+//
+//	if errors.Is(err, gdq.ErrInvalidAnswerRange) {
+//	    w.WriteHeader(http.StatusUnprocessableEntity)
+//	}
+var (
+	ErrEmptyQuestionID             = ValidationError{Type: EmptyQuestionIDErrType, Message: "question with no ID"}
+	ErrDuplicateQuestionID         = ValidationError{Type: DuplicateQuestionIDErrType, Message: "duplicated question ID"}
+	ErrEmptyAnswers                = ValidationError{Type: EmptyAnswersErrType, Message: "question has no answer options"}
+	ErrDuplicateAnswerID           = ValidationError{Type: DuplicateAnswerIDErrType, Message: "duplicated answer ID"}
+	ErrInvalidDefaultAnswer        = ValidationError{Type: InvalidDefaultAnswerErrType, Message: "default answer out of range"}
+	ErrInvalidTimeEstimate         = ValidationError{Type: InvalidTimeEstimateErrType, Message: "negative time estimate"}
+	ErrInvalidWeight               = ValidationError{Type: InvalidWeightErrType, Message: "negative weight"}
+	ErrInvalidChoiceLimits         = ValidationError{Type: InvalidChoiceLimitsErrType, Message: "invalid choice limits"}
+	ErrInvalidNumberBounds         = ValidationError{Type: InvalidNumberBoundsErrType, Message: "invalid number bounds"}
+	ErrInvalidConsent              = ValidationError{Type: InvalidConsentErrType, Message: "invalid consent question"}
+	ErrInvalidQuestionType         = ValidationError{Type: InvalidQuestionTypeErrType, Message: "unknown question type"}
+	ErrInvalidQuestionID           = ValidationError{Type: InvalidQuestionIDErrType, Message: "question does not exist"}
+	ErrInvalidAnswerID             = ValidationError{Type: InvalidAnswerIDErrType, Message: "answer ID does not exist"}
+	ErrInvalidAnswerRange          = ValidationError{Type: InvalidAnswerRangeErrType, Message: "answer out of range"}
+	ErrInvalidAnswerType           = ValidationError{Type: InvalidAnswerTypeErrType, Message: "answer doesn't match the question type"}
+	ErrUnavailableAnswer           = ValidationError{Type: UnavailableAnswerErrType, Message: "answer not available"}
+	ErrUnreachableAnswer           = ValidationError{Type: UnreachableAnswerErrType, Message: "answer to a question off the path"}
+	ErrInvalidChoiceCount          = ValidationError{Type: InvalidChoiceCountErrType, Message: "number of chosen answers out of range"}
+	ErrExclusiveAnswer             = ValidationError{Type: ExclusiveAnswerErrType, Message: "exclusive answer combined with other answers"}
+	ErrInvalidDependency           = ValidationError{Type: InvalidDependencyErrType, Message: "dependency on non-existent question"}
+	ErrCircularDependency          = ValidationError{Type: CircularDependencyErrType, Message: "circular dependency"}
+	ErrInvalidCondition            = ValidationError{Type: InvalidConditionErrType, Message: "invalid condition"}
+	ErrInvalidWhenRule             = ValidationError{Type: InvalidWhenRuleErrType, Message: "invalid when rule"}
+	ErrInvalidJump                 = ValidationError{Type: InvalidJumpErrType, Message: "invalid answer jump"}
+	ErrInvalidGroup                = ValidationError{Type: InvalidGroupErrType, Message: "invalid repeating group"}
+	ErrInvalidQuota                = ValidationError{Type: InvalidQuotaErrType, Message: "invalid quota"}
+	ErrInvalidAvailability         = ValidationError{Type: InvalidAvailabilityErrType, Message: "invalid availability window"}
+	ErrNotYetAvailable             = ValidationError{Type: NotYetAvailableErrType, Message: "questionnaire not available yet"}
+	ErrExpiredQuestionnaire        = ValidationError{Type: ExpiredQuestionnaireErrType, Message: "questionnaire expired"}
+	ErrInvalidOptionsSource        = ValidationError{Type: InvalidOptionsSourceErrType, Message: "invalid options source"}
+	ErrUnknownQuestionReference    = ValidationError{Type: UnknownQuestionReferenceErrType, Message: "condition references non-existent question"}
+	ErrConditionEvaluation         = ValidationError{Type: ConditionEvaluationErrType, Message: "condition evaluation failed"}
+	ErrConditionDependencyMismatch = ValidationError{Type: ConditionDependencyMismatchErrType, Message: "conditions don't match declared dependencies"}
+	ErrRuleViolation               = ValidationError{Type: RuleViolationErrType, Message: "answers violate a consistency rule"}
+)
+
 // Error returns a formatted error message that includes both the error type and message.
 // This implements the standard error interface.
 //
@@ -73,17 +237,73 @@ type validationError struct {
 // Example output:
 //
 //	"validation error (invalid_question_id): question 'xyz' does not exist"
-func (e validationError) Error() string {
+func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error (%s): %s", e.Type, e.Message)
 }
 
+// Is reports whether target is a ValidationError of the same type,
+// which makes errors.Is match the sentinel errors (ErrInvalidAnswerRange...).
+func (e ValidationError) Is(target error) bool {
+	t, ok := target.(ValidationError)
+	return ok && t.Type == e.Type
+}
+
+// MarshalJSON encodes the error as a JSON object with its type, message and context,
+// so that APIs can return machine-readable errors rather than the formatted message:
+//
+//	{"type": "invalid_answer_range", "message": "answer is out of range", "context": {"question_id": "q1", "answer": 5, "valid_range": "1-3"}}
+//
+// The context is always an object, empty when the error has none, and errors it holds are encoded as their message.
+func (e ValidationError) MarshalJSON() ([]byte, error) {
+	context := make(map[string]interface{}, len(e.Context))
+	for key, value := range e.Context {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		context[key] = value
+	}
+	return json.Marshal(struct {
+		Type    string                 `json:"type"`
+		Message string                 `json:"message"`
+		Context map[string]interface{} `json:"context"`
+	}{e.Type, e.Message, context})
+}
+
+// ValidationErrors returns the validation errors wrapped by err, in order.
+// It walks both single wrapping (fmt.Errorf with %w) and aggregated errors (errors.Join),
+// so that every problem reported by New can be inspected (e.g. to build an API response).
+//
+// Example usage:
+//
+//	for _, validationErr := range gdq.ValidationErrors(err) {
+//	    log.Printf("%s: %v", validationErr.Type, validationErr.Context)
+//	}
+func ValidationErrors(err error) []ValidationError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case ValidationError:
+		return []ValidationError{e}
+	case interface{ Unwrap() []error }:
+		var errs []ValidationError
+		for _, wrapped := range e.Unwrap() {
+			errs = append(errs, ValidationErrors(wrapped)...)
+		}
+		return errs
+	case interface{ Unwrap() error }:
+		return ValidationErrors(e.Unwrap())
+	default:
+		return nil
+	}
+}
+
 // emptyQuestionIDError creates a validation error for questions missing an ID.
 // This error occurs during questionnaire loading when a question is defined
 // without a required ID field.
 //
 // Returns:
 //
-//	error: A validationError with type emptyQuestionIDErrType.
+//	error: A ValidationError with type EmptyQuestionIDErrType.
 //
 // Example scenario:
 //
@@ -91,8 +311,8 @@ func (e validationError) Error() string {
 //	  - text: "What's your favorite color?"  # Missing 'id' field
 //	    answers: ["Red", "Blue", "Green"]
 func emptyQuestionIDError() error {
-	return validationError{
-		Type:    emptyQuestionIDErrType,
+	return ValidationError{
+		Type:    EmptyQuestionIDErrType,
 		Message: "questionnaire contains a question with no ID",
 	}
 }
@@ -107,7 +327,7 @@ func emptyQuestionIDError() error {
 //
 // Returns:
 //
-//	error: A validationError with type duplicateQuestionIDErrType and
+//	error: A ValidationError with type DuplicateQuestionIDErrType and
 //	       context containing the conflicting question ID.
 //
 // Example scenario:
@@ -120,8 +340,8 @@ func emptyQuestionIDError() error {
 //	    text: "Another question"
 //	    answers: ["A", "B", "C"]
 func duplicateQuestionIDError(questionID string) error {
-	return validationError{
-		Type:    duplicateQuestionIDErrType,
+	return ValidationError{
+		Type:    DuplicateQuestionIDErrType,
 		Message: "duplicated question ID",
 		Context: map[string]interface{}{"question_id": questionID},
 	}
@@ -137,7 +357,7 @@ func duplicateQuestionIDError(questionID string) error {
 //
 // Returns:
 //
-//	error: A validationError with type emptyAnswersErrType and
+//	error: A ValidationError with type EmptyAnswersErrType and
 //	       context containing the affected question ID.
 //
 // Example scenario:
@@ -147,13 +367,272 @@ func duplicateQuestionIDError(questionID string) error {
 //	    text: "What do you think?"
 //	    answers: []  # Empty answers array
 func emptyAnswersError(questionID string) error {
-	return validationError{
-		Type:    emptyAnswersErrType,
+	return ValidationError{
+		Type:    EmptyAnswersErrType,
 		Message: "question has no answer options",
 		Context: map[string]interface{}{"question_id": questionID},
 	}
 }
 
+// duplicateAnswerIDError creates a validation error for duplicate answer option IDs.
+// This error occurs during questionnaire loading when multiple answer options of
+// the same question share the same ID, which would make answer IDs ambiguous.
+//
+// Parameters:
+//
+//	questionID: The ID of the question declaring the options.
+//	answerID: The answer option ID that appears multiple times.
+//
+// Returns:
+//
+//	error: A ValidationError with type DuplicateAnswerIDErrType and
+//	       context containing both IDs.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "plan"
+//	    answers:
+//	      - id: "pro"
+//	        text: "Pro"
+//	      - id: "pro"  # Duplicate ID
+//	        text: "Enterprise"
+func duplicateAnswerIDError(questionID, answerID string) error {
+	return ValidationError{
+		Type:    DuplicateAnswerIDErrType,
+		Message: "duplicated answer ID",
+		Context: map[string]interface{}{
+			"question_id": questionID,
+			"answer_id":   answerID,
+		},
+	}
+}
+
+// invalidDefaultAnswerError creates a validation error for out-of-range default answers.
+// This error occurs during questionnaire loading when a question declares a default
+// that doesn't correspond to one of its answer options.
+//
+// Parameters:
+//
+//	q: The question declaring the invalid default.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidDefaultAnswerErrType and
+//	       context containing the question ID, the default and the valid range.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "color"
+//	    text: "What's your favorite color?"
+//	    answers: ["Red", "Blue", "Green"]
+//	    default: 4  # Only 1-3 are valid
+func invalidDefaultAnswerError(q *question) error {
+	return ValidationError{
+		Type:    InvalidDefaultAnswerErrType,
+		Message: "default answer is out of range",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"default":     q.Default,
+			"valid_range": fmt.Sprintf("1-%d", len(q.Answers)),
+		},
+	}
+}
+
+// invalidWeightError creates a validation error for negative progress weights.
+// This error occurs during questionnaire loading when a question declares
+// a weight below zero.
+//
+// Parameters:
+//
+//	q: The question declaring the invalid weight.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidWeightErrType and
+//	       context containing the question ID and the weight.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "color"
+//	    text: "What's your favorite color?"
+//	    answers: ["Red", "Blue", "Green"]
+//	    weight: -2  # Must be a positive number
+func invalidWeightError(q *question) error {
+	return ValidationError{
+		Type:    InvalidWeightErrType,
+		Message: "weight must not be negative",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"weight":      q.Weight,
+		},
+	}
+}
+
+// invalidChoiceLimitsError creates a validation error for invalid limits on the number of chosen options.
+// This error occurs during questionnaire loading when a question declares min_choices or max_choices
+// while it isn't a multiple choice question, or limits that can't be met.
+//
+// Parameters:
+//
+//	q: The question declaring the invalid limits.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidChoiceLimitsErrType and
+//	       context containing the question ID, the limits and the number of answer options.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "tools"
+//	    text: "Which tools do you use?"
+//	    type: "multiple_choice"
+//	    answers: ["Go", "Rust", "Python"]
+//	    min_choices: 4  # Only 3 options can be chosen
+func invalidChoiceLimitsError(q *question) error {
+	return ValidationError{
+		Type:    InvalidChoiceLimitsErrType,
+		Message: "choice limits are invalid",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"min_choices": q.MinChoices,
+			"max_choices": q.MaxChoices,
+			"answers":     len(q.Answers),
+		},
+	}
+}
+
+// invalidNumberBoundsError creates a validation error for invalid bounds on the answer of a number question.
+// This error occurs during questionnaire loading when a question declares min or max
+// while it isn't a number question, or a min above its max.
+//
+// Parameters:
+//
+//	q: The question declaring the invalid bounds.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidNumberBoundsErrType and
+//	       context containing the question ID, its type and the bounds.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "age"
+//	    text: "How old are you?"
+//	    type: "number"
+//	    min: 18
+//	    max: 12  # No answer can be both
+func invalidNumberBoundsError(q *question) error {
+	return ValidationError{
+		Type:    InvalidNumberBoundsErrType,
+		Message: "number bounds are invalid",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"type":        q.kind(),
+			"min":         describeBound(q.Min),
+			"max":         describeBound(q.Max),
+		},
+	}
+}
+
+// invalidConsentError creates a validation error for consent questions that can't collect consent.
+// This error occurs during questionnaire loading when a consent question could be left unanswered
+// (optional or skippable), grants consent by default, or has no answer option declining consent.
+//
+// Parameters:
+//
+//	q: The invalid consent question.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidConsentErrType and
+//	       context containing the question ID and the number of answer options.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "consent"
+//	    text: "Do you agree to the processing of your answers?"
+//	    type: "consent"
+//	    answers: ["I agree", "I don't agree"]
+//	    skippable: true  # Consent must be given explicitly
+func invalidConsentError(q *question) error {
+	return ValidationError{
+		Type:    InvalidConsentErrType,
+		Message: "consent question must be required, not skippable, without a default and offer at least 2 answers",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"answers":     len(q.Answers),
+		},
+	}
+}
+
+// invalidTimeEstimateError creates a validation error for negative time estimates.
+// This error occurs during questionnaire loading when a question declares
+// a time_estimate below zero.
+//
+// Parameters:
+//
+//	q: The question declaring the invalid time estimate.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidTimeEstimateErrType and
+//	       context containing the question ID and the time estimate.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "color"
+//	    text: "What's your favorite color?"
+//	    answers: ["Red", "Blue", "Green"]
+//	    time_estimate: -5  # Must be a number of seconds
+func invalidTimeEstimateError(q *question) error {
+	return ValidationError{
+		Type:    InvalidTimeEstimateErrType,
+		Message: "time estimate must not be negative",
+		Context: map[string]interface{}{
+			"question_id":   q.Id,
+			"time_estimate": q.TimeEstimate,
+		},
+	}
+}
+
+// invalidQuestionTypeError creates a validation error for unknown question types.
+// This error occurs during questionnaire loading when a question declares
+// a type that the engine doesn't support.
+//
+// Parameters:
+//
+//	q: The question declaring the unknown type.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidQuestionTypeErrType and
+//	       context containing the question ID and its type.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "color"
+//	    text: "What's your favorite color?"
+//	    type: "dropdown"  # Must be choice, multiple_choice, text, number or nps
+//	    answers: ["Red", "Blue", "Green"]
+func invalidQuestionTypeError(q *question) error {
+	return ValidationError{
+		Type:    InvalidQuestionTypeErrType,
+		Message: "question type is unknown",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"type":        q.Type,
+		},
+	}
+}
+
 // invalidQuestionIDError creates a validation error for non-existent question references.
 // This error occurs during answer processing when a user provides an answer
 // for a question ID that doesn't exist in the questionnaire.
@@ -161,11 +640,11 @@ func emptyAnswersError(questionID string) error {
 // Parameters:
 //
 //	questionID: The invalid question ID that was referenced.
-//	answer: The answer value that was provided (included for context).
+//	answer: The answer value or answer ID that was provided (included for context).
 //
 // Returns:
 //
-//	error: A validationError with type invalidQuestionIdErrType and
+//	error: A ValidationError with type InvalidQuestionIDErrType and
 //	       context containing both the invalid ID and the attempted answer.
 //
 // Example scenario:
@@ -175,9 +654,9 @@ func emptyAnswersError(questionID string) error {
 //	    "q1": 1,
 //	    "q4": 2,  // "q4" doesn't exist
 //	}
-func invalidQuestionIDError(questionID string, answer int) error {
-	return validationError{
-		Type:    invalidQuestionIdErrType,
+func invalidQuestionIDError(questionID string, answer interface{}) error {
+	return ValidationError{
+		Type:    InvalidQuestionIDErrType,
 		Message: "question does not exist",
 		Context: map[string]interface{}{
 			"question_id": questionID,
@@ -186,6 +665,35 @@ func invalidQuestionIDError(questionID string, answer int) error {
 	}
 }
 
+// invalidAnswerIDError creates a validation error for unknown answer IDs.
+// This error occurs when resolving answers expressed with answer option IDs
+// and an ID doesn't match any option of the question.
+//
+// Parameters:
+//
+//	questionID: The ID of the question that was answered.
+//	answerID: The unknown answer ID that was provided.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidAnswerIDErrType and
+//	       context containing both IDs.
+//
+// Example scenario:
+//
+//	// Question "plan" has options with IDs "free" and "pro"
+//	answers := map[string]string{"plan": "enterprise"}  # "enterprise" doesn't exist
+func invalidAnswerIDError(questionID, answerID string) error {
+	return ValidationError{
+		Type:    InvalidAnswerIDErrType,
+		Message: "answer ID does not exist",
+		Context: map[string]interface{}{
+			"question_id": questionID,
+			"answer_id":   answerID,
+		},
+	}
+}
+
 // invalidAnswerRangeError creates a validation error for out-of-range answer values.
 // This error occurs during answer processing when a user provides an answer
 // that is outside the valid range for a specific question.
@@ -196,11 +704,11 @@ func invalidQuestionIDError(questionID string, answer int) error {
 // Parameters:
 //
 //	q: The question for which an invalid answer was provided.
-//	answer: The out-of-range answer value that was provided.
+//	answer: The out-of-range answer value that was provided: an answer choice, or the number answering a number question.
 //
 // Returns:
 //
-//	error: A validationError with type invalidAnswerRangeErrType and
+//	error: A ValidationError with type InvalidAnswerRangeErrType and
 //	       comprehensive context including question details and valid range.
 //
 // Example scenario:
@@ -212,15 +720,193 @@ func invalidQuestionIDError(questionID string, answer int) error {
 //
 //	// User provides answer 5 (out of range)
 //	answers := map[string]int{"color": 5}  # Error: valid range is 1-3
-func invalidAnswerRangeError(q *question, answer int) error {
-	return validationError{
-		Type:    invalidAnswerRangeErrType,
+func invalidAnswerRangeError(q *question, answer interface{}) error {
+	return ValidationError{
+		Type:    InvalidAnswerRangeErrType,
 		Message: "answer is out of range",
 		Context: map[string]interface{}{
 			"question_id":   q.Id,
-			"question_text": q.Text,
+			"question_text": q.Text.String(),
+			"answer":        answer,
+			"valid_range":   q.answerRange(),
+		},
+	}
+}
+
+// invalidAnswerTypeError creates a validation error for answers not matching their question type.
+// This error occurs during answer processing when, for instance, a text question is answered
+// with a number, or a question that isn't single choice is answered through Next.
+//
+// Parameters:
+//
+//	q: The question for which the answer was provided.
+//	answer: The answer value that was provided.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidAnswerTypeErrType and
+//	       context containing the question ID, its type and the answer.
+//
+// Example scenario:
+//
+//	question:
+//	  id: "company"
+//	  text: "What's the name of your company?"
+//	  type: "text"
+//
+//	// User provides a choice rather than a text
+//	answers := map[string]gdq.Answer{"company": gdq.Choice(1)}
+func invalidAnswerTypeError(q *question, answer interface{}) error {
+	return ValidationError{
+		Type:    InvalidAnswerTypeErrType,
+		Message: "answer doesn't match the question type",
+		Context: map[string]interface{}{
+			"question_id":   q.Id,
+			"question_type": q.kind(),
 			"answer":        answer,
-			"valid_range":   fmt.Sprintf("1-%d", len(q.Answers)),
+		},
+	}
+}
+
+// unavailableAnswerError creates a validation error for answer options that are not offered.
+// This error occurs during answer processing when a user chooses a conditional answer option
+// whose condition is not satisfied by the other answers.
+//
+// Parameters:
+//
+//	q: The question for which an unavailable answer was provided.
+//	answer: The answer value that was provided.
+//
+// Returns:
+//
+//	error: A ValidationError with type UnavailableAnswerErrType and
+//	       context containing the question ID and the answer.
+//
+// Example scenario:
+//
+//	question:
+//	  id: "action"
+//	  answers:
+//	    - "Keep my plan"
+//	    - text: "Upgrade plan"
+//	      condition: 'answers["plan"] == 1'
+//
+//	answers := map[string]int{"plan": 2, "action": 2}  # Error: "Upgrade plan" is not offered
+func unavailableAnswerError(q *question, answer int) error {
+	return ValidationError{
+		Type:    UnavailableAnswerErrType,
+		Message: "answer is not available",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"answer":      answer,
+		},
+	}
+}
+
+// unreachableAnswerError creates a validation error for answers to questions that wouldn't be shown.
+// This error occurs when validating a complete answer set with ValidateAnswerSet, when a question is answered
+// while its dependencies aren't answered, its condition doesn't hold or it depends on such a question,
+// typically because the answers were tampered with or edited after the question was answered.
+//
+// Parameters:
+//
+//	q: The question answered off the path.
+//	answer: The answer value that was provided.
+//
+// Returns:
+//
+//	error: A ValidationError with type UnreachableAnswerErrType and
+//	       context containing the question ID and the answer.
+//
+// Example scenario:
+//
+//	question:
+//	  id: "company_size"
+//	  condition: 'answers["employed"] == 1'
+//
+//	answers := map[string]int{"employed": 2, "company_size": 1}  # Error: company_size is only asked to employed respondents
+func unreachableAnswerError(q *question, answer int) error {
+	return ValidationError{
+		Type:    UnreachableAnswerErrType,
+		Message: "question wouldn't be shown given the other answers",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"answer":      answer,
+		},
+	}
+}
+
+// invalidChoiceCountError creates a validation error for too few or too many chosen options.
+// This error occurs during answer processing when the number of options chosen in a multiple choice question
+// is outside the min_choices and max_choices of the question.
+//
+// Parameters:
+//
+//	q: The multiple choice question for which the answer was provided.
+//	answers: The answer options that were chosen.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidChoiceCountErrType and
+//	       context containing the question ID, the chosen answers, their number and the allowed range.
+//
+// Example scenario:
+//
+//	question:
+//	  id: "tools"
+//	  type: "multiple_choice"
+//	  answers: ["Go", "Rust", "Python"]
+//	  max_choices: 2
+//
+//	answers := map[string]gdq.Answer{"tools": gdq.Choices(1, 2, 3)}  # Error: at most 2 options can be chosen
+func invalidChoiceCountError(q *question, answers []int) error {
+	return ValidationError{
+		Type:    InvalidChoiceCountErrType,
+		Message: "number of chosen answers is out of range",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"answers":     answers,
+			"count":       len(answers),
+			"valid_range": fmt.Sprintf("%d-%d", q.MinChoices, q.maxChoices()),
+		},
+	}
+}
+
+// exclusiveAnswerError creates a validation error for exclusive answer options chosen along with other options.
+// This error occurs during answer processing when the answer of a multiple choice question includes
+// an option marked as exclusive and other options.
+//
+// Parameters:
+//
+//	q: The multiple choice question for which the answer was provided.
+//	answer: The exclusive answer option that was chosen.
+//	answers: Every answer option that was chosen.
+//
+// Returns:
+//
+//	error: A ValidationError with type ExclusiveAnswerErrType and
+//	       context containing the question ID, the exclusive answer and the chosen answers.
+//
+// Example scenario:
+//
+//	question:
+//	  id: "allergies"
+//	  type: "multiple_choice"
+//	  answers:
+//	    - "Peanuts"
+//	    - "Gluten"
+//	    - text: "None of the above"
+//	      exclusive: true
+//
+//	answers := map[string]gdq.Answer{"allergies": gdq.Choices(1, 3)}  # Error: "None of the above" excludes "Peanuts"
+func exclusiveAnswerError(q *question, answer int, answers []int) error {
+	return ValidationError{
+		Type:    ExclusiveAnswerErrType,
+		Message: "exclusive answer can't be combined with other answers",
+		Context: map[string]interface{}{
+			"question_id": q.Id,
+			"answer":      answer,
+			"answers":     answers,
 		},
 	}
 }
@@ -236,7 +922,7 @@ func invalidAnswerRangeError(q *question, answer int) error {
 //
 // Returns:
 //
-//	error: A validationError with type invalidDependencyErrType and
+//	error: A ValidationError with type InvalidDependencyErrType and
 //	       context containing both question IDs.
 //
 // Example scenario:
@@ -250,8 +936,8 @@ func invalidAnswerRangeError(q *question, answer int) error {
 //	    answers: ["A", "B", "C"]
 //	    depends_on: ["nonexistent"]  # "nonexistent" doesn't exist
 func invalidDependencyError(questionID, invalidDependencyID string) error {
-	return validationError{
-		Type:    invalidDependencyErrType,
+	return ValidationError{
+		Type:    InvalidDependencyErrType,
 		Message: fmt.Sprintf("question '%s' depends on non-existent question '%s'", questionID, invalidDependencyID),
 		Context: map[string]interface{}{
 			"question_id":           questionID,
@@ -271,7 +957,7 @@ func invalidDependencyError(questionID, invalidDependencyID string) error {
 //
 // Returns:
 //
-//	error: A validationError with type circularDependencyErrType and
+//	error: A ValidationError with type CircularDependencyErrType and
 //	       context containing the full dependency cycle.
 //
 // Example scenario:
@@ -284,8 +970,8 @@ func invalidDependencyError(questionID, invalidDependencyID string) error {
 //	  - id: "q3"
 //	    depends_on: ["q1"]  # Creates cycle: q1 -> q2 -> q3 -> q1
 func circularDependencyError(cycle []string) error {
-	return validationError{
-		Type:    circularDependencyErrType,
+	return ValidationError{
+		Type:    CircularDependencyErrType,
 		Message: fmt.Sprintf("circular dependency detected: %s", strings.Join(cycle, " -> ")),
 		Context: map[string]interface{}{
 			"cycle":        cycle,
@@ -306,7 +992,7 @@ func circularDependencyError(cycle []string) error {
 //
 // Returns:
 //
-//	error: A validationError with type conditionDependencyMismatchErrType and
+//	error: A ValidationError with type ConditionDependencyMismatchErrType and
 //	       context containing both question IDs.
 //
 // Example scenario:
@@ -321,8 +1007,8 @@ func circularDependencyError(cycle []string) error {
 //	    depends_on: ["q1"]
 //	    condition: 'answers["q1"] == 1 && answers["q3"] == 2'  # References q3 but doesn't declare it
 func conditionDependencyMismatchError(questionID string, declared, condition []string) error {
-	return validationError{
-		Type:    conditionDependencyMismatchErrType,
+	return ValidationError{
+		Type:    ConditionDependencyMismatchErrType,
 		Message: fmt.Sprintf("question '%s' conditions don't match the declared dependencies %v", questionID, declared),
 		Context: map[string]interface{}{
 			"question_id":  questionID,
@@ -331,3 +1017,397 @@ func conditionDependencyMismatchError(questionID string, declared, condition []s
 		},
 	}
 }
+
+// invalidConditionError creates a validation error for conditions that fail to compile.
+// This error occurs during questionnaire loading when a question, answer option or
+// closing remark condition has a syntax error or uses unknown variables or functions.
+//
+// Parameters:
+//
+//	ownerKey: The context key identifying the kind of element ("question_id" or "remark_id").
+//	ownerID: The ID of the element declaring the condition.
+//	condition: The invalid condition expression.
+//	err: The compilation error.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidConditionErrType and
+//	       context containing the element ID, the condition and the compilation error.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "q2"
+//	    text: "Second question"
+//	    answers: ["A", "B"]
+//	    depends_on: ["q1"]
+//	    condition: 'answers["q1"] =='  # Incomplete expression
+func invalidConditionError(ownerKey, ownerID, condition string, err error) error {
+	return ValidationError{
+		Type:    InvalidConditionErrType,
+		Message: fmt.Sprintf("condition '%s' of '%s' is not a valid expression: %v", condition, ownerID, err),
+		Context: map[string]interface{}{
+			ownerKey:    ownerID,
+			"condition": condition,
+			"error":     err.Error(),
+		},
+	}
+}
+
+// unknownQuestionReferenceError creates a validation error for conditions referencing unknown questions.
+// This error occurs during questionnaire loading in strict validation mode when a condition
+// refers to a question ID that is not defined in the questionnaire.
+//
+// Parameters:
+//
+//	ownerKey: The context key identifying the kind of element ("question_id" or "remark_id").
+//	ownerID: The ID of the element declaring the condition.
+//	condition: The condition expression.
+//	referenceID: The unknown question ID referenced by the condition.
+//
+// Returns:
+//
+//	error: A ValidationError with type UnknownQuestionReferenceErrType and
+//	       context containing the element ID, the condition and the unknown question ID.
+//
+// Example scenario:
+//
+//	closing_remarks:
+//	  - id: "thanks"
+//	    text: "Thank you!"
+//	    condition: 'answers["nonexistent"] == 1'  # "nonexistent" doesn't exist
+func unknownQuestionReferenceError(ownerKey, ownerID, condition, referenceID string) error {
+	return ValidationError{
+		Type:    UnknownQuestionReferenceErrType,
+		Message: fmt.Sprintf("condition '%s' of '%s' references non-existent question '%s'", condition, ownerID, referenceID),
+		Context: map[string]interface{}{
+			ownerKey:             ownerID,
+			"condition":          condition,
+			"question_reference": referenceID,
+		},
+	}
+}
+
+// invalidWhenRuleError creates a validation error for incomplete when rules.
+// This error occurs during questionnaire loading when a question, answer option or
+// closing remark declares a when rule that can't be translated into a condition.
+//
+// Parameters:
+//
+//	ownerKey: The context key identifying the kind of element ("question_id" or "remark_id").
+//	ownerID: The ID of the element declaring the rule.
+//	err: The reason why the rule is invalid.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidWhenRuleErrType and
+//	       context containing the element ID and the reason.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "q2"
+//	    text: "Second question"
+//	    answers: ["A", "B"]
+//	    depends_on: ["q1"]
+//	    when:
+//	      question: "q1"  # No comparison (equals, in, answered...)
+func invalidWhenRuleError(ownerKey, ownerID string, err error) error {
+	return ValidationError{
+		Type:    InvalidWhenRuleErrType,
+		Message: fmt.Sprintf("when rule of '%s' is invalid: %v", ownerID, err),
+		Context: map[string]interface{}{
+			ownerKey: ownerID,
+			"error":  err.Error(),
+		},
+	}
+}
+
+// invalidJumpError creates a validation error for invalid answer jumps.
+// This error occurs during questionnaire loading when an answer option declares
+// a next question that doesn't exist, is the question itself, or is already
+// the jump target of another question.
+//
+// Parameters:
+//
+//	questionID: The ID of the question whose answer option declares the jump.
+//	target: The ID of the question the option jumps to.
+//	err: The reason why the jump is invalid.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidJumpErrType and
+//	       context containing the question ID, the target and the reason.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "q1"
+//	    text: "First question"
+//	    answers:
+//	      - text: "A"
+//	        next: "q9"  # No question has this ID
+func invalidJumpError(questionID, target string, err error) error {
+	return ValidationError{
+		Type:    InvalidJumpErrType,
+		Message: fmt.Sprintf("jump from question '%s' to '%s' is invalid: %v", questionID, target, err),
+		Context: map[string]interface{}{
+			"question_id": questionID,
+			"next":        target,
+			"error":       err.Error(),
+		},
+	}
+}
+
+// invalidGroupError creates a validation error for repeating groups that can't be expanded.
+// This error occurs during questionnaire loading when a group has no ID or a duplicated one,
+// repeats for a question that doesn't exist or isn't a number question, has no questions,
+// a question without ID, or a max below 1.
+//
+// Parameters:
+//
+//	groupID: The ID of the invalid group.
+//	err: The reason why the group is invalid.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidGroupErrType and
+//	       context containing the group ID and the reason.
+//
+// Example scenario:
+//
+//	groups:
+//	  - id: "child"
+//	    repeat_for: "children"  # No question has this ID
+//	    max: 5
+//	    questions:
+//	      - id: "age"
+//	        text: "How old is child {index}?"
+//	        type: "number"
+func invalidGroupError(groupID string, err error) error {
+	return ValidationError{
+		Type:    InvalidGroupErrType,
+		Message: fmt.Sprintf("group '%s' is invalid: %v", groupID, err),
+		Context: map[string]interface{}{
+			"group_id": groupID,
+			"error":    err.Error(),
+		},
+	}
+}
+
+// invalidQuotaError creates a validation error for misconfigured quotas.
+// This error occurs during questionnaire loading when a quota has no ID or a duplicated one,
+// a limit below 1 or no remark, or when answer options are counted toward an undeclared quota.
+//
+// Parameters:
+//
+//	quotaID: The ID of the invalid quota.
+//	err: The reason why the quota is invalid.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidQuotaErrType and
+//	       context containing the quota ID and the reason.
+//
+// Example scenario:
+//
+//	quotas:
+//	  - id: "young_adults"
+//	    limit: 0  # Must be at least 1
+//	    remark: "Thank you! We already have enough respondents in your age group."
+func invalidQuotaError(quotaID string, err error) error {
+	return ValidationError{
+		Type:    InvalidQuotaErrType,
+		Message: fmt.Sprintf("quota '%s' is invalid: %v", quotaID, err),
+		Context: map[string]interface{}{
+			"quota_id": quotaID,
+			"error":    err.Error(),
+		},
+	}
+}
+
+// invalidAvailabilityError creates a validation error for empty availability windows.
+// This error occurs during questionnaire loading when available_until isn't after available_from.
+//
+// Parameters:
+//
+//	from: The available_from time of the questionnaire.
+//	until: The available_until time of the questionnaire.
+//	err: The reason why the window is invalid.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidAvailabilityErrType and
+//	       context containing both times and the reason.
+//
+// Example scenario:
+//
+//	available_from: 2025-03-31T00:00:00Z
+//	available_until: 2025-03-01T00:00:00Z  # Before available_from
+func invalidAvailabilityError(from, until time.Time, err error) error {
+	return ValidationError{
+		Type:    InvalidAvailabilityErrType,
+		Message: fmt.Sprintf("availability window is invalid: %v", err),
+		Context: map[string]interface{}{
+			"available_from":  from.Format(time.RFC3339),
+			"available_until": until.Format(time.RFC3339),
+			"error":           err.Error(),
+		},
+	}
+}
+
+// notYetAvailableError creates a validation error for questionnaires answered before they open.
+// This error occurs when Next is called before the available_from time of the questionnaire,
+// for instance when a time-limited campaign hasn't started yet.
+//
+// Parameters:
+//
+//	from: The available_from time of the questionnaire.
+//
+// Returns:
+//
+//	error: A ValidationError with type NotYetAvailableErrType and
+//	       context containing the available_from time.
+//
+// Example scenario:
+//
+//	available_from: 2025-03-01T09:00:00Z
+//
+//	q.Next(answers)  # Called on 2025-02-28: the campaign hasn't started
+func notYetAvailableError(from time.Time) error {
+	return ValidationError{
+		Type:    NotYetAvailableErrType,
+		Message: fmt.Sprintf("questionnaire is not available until %s", from.Format(time.RFC3339)),
+		Context: map[string]interface{}{
+			"available_from": from.Format(time.RFC3339),
+		},
+	}
+}
+
+// expiredQuestionnaireError creates a validation error for questionnaires answered after they close.
+// This error occurs when Next is called at or after the available_until time of the questionnaire,
+// for instance when a time-limited campaign is over.
+//
+// Parameters:
+//
+//	until: The available_until time of the questionnaire.
+//
+// Returns:
+//
+//	error: A ValidationError with type ExpiredQuestionnaireErrType and
+//	       context containing the available_until time.
+//
+// Example scenario:
+//
+//	available_until: 2025-03-31T23:59:59Z
+//
+//	q.Next(answers)  # Called on 2025-04-01: the campaign is over
+func expiredQuestionnaireError(until time.Time) error {
+	return ValidationError{
+		Type:    ExpiredQuestionnaireErrType,
+		Message: fmt.Sprintf("questionnaire expired on %s", until.Format(time.RFC3339)),
+		Context: map[string]interface{}{
+			"available_until": until.Format(time.RFC3339),
+		},
+	}
+}
+
+// invalidOptionsSourceError creates a validation error for questions that can't get their options from a data provider.
+// This error occurs during questionnaire loading when a question declares an options_from source
+// while it isn't answered by choosing options, or while declaring its own answers.
+//
+// Parameters:
+//
+//	q: The question declaring the options source.
+//	err: The reason why the source can't be used.
+//
+// Returns:
+//
+//	error: A ValidationError with type InvalidOptionsSourceErrType and
+//	       context containing the question ID, the source and the reason.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "project"
+//	    text: "Which project is this feedback about?"
+//	    type: "text"  # Text questions have no answer options
+//	    options_from: "projects"
+func invalidOptionsSourceError(q *question, err error) error {
+	return ValidationError{
+		Type:    InvalidOptionsSourceErrType,
+		Message: fmt.Sprintf("question '%s' can't get its options from source '%s': %v", q.Id, q.OptionsFrom, err),
+		Context: map[string]interface{}{
+			"question_id":  q.Id,
+			"options_from": q.OptionsFrom,
+			"error":        err.Error(),
+		},
+	}
+}
+
+// conditionEvaluationError creates a validation error for conditions failing to evaluate.
+// This error is reported by Lint when, along an answer path, a condition returns a
+// non-boolean value or fails at runtime: Next would fail for those answers.
+//
+// Parameters:
+//
+//	answers: The answers leading to the failure, formatted as "q1=1, q2=2".
+//	err: The evaluation error.
+//
+// Returns:
+//
+//	error: A ValidationError with type ConditionEvaluationErrType and
+//	       context containing the answers and the evaluation error.
+//
+// Example scenario:
+//
+//	questions:
+//	  - id: "q2"
+//	    text: "Second question"
+//	    answers: ["A", "B"]
+//	    depends_on: ["q1"]
+//	    condition: 'answerText("q1")'  # Returns a string, not a boolean
+func conditionEvaluationError(answers string, err error) error {
+	return ValidationError{
+		Type:    ConditionEvaluationErrType,
+		Message: fmt.Sprintf("condition evaluation failed with answers %s: %v", answers, err),
+		Context: map[string]interface{}{
+			"answers": answers,
+			"error":   err.Error(),
+		},
+	}
+}
+
+// ruleViolationError creates a validation error for answers violating a consistency rule.
+// This error occurs during Next when every question referenced by a rule is answered
+// and the rule condition doesn't hold.
+//
+// Parameters:
+//
+//	rule: The violated rule.
+//	message: The message of the rule in the requested locale, empty when the rule has none.
+//
+// Returns:
+//
+//	error: A ValidationError with type RuleViolationErrType and
+//	       context containing the rule ID and its condition.
+//
+// Example scenario:
+//
+//	rules:
+//	  - id: "years_order"
+//	    condition: 'answers["end_year"] >= answers["start_year"]'
+//	# answers: {"start_year": 3, "end_year": 1}
+func ruleViolationError(rule *consistencyRule, message string) error {
+	if message == "" {
+		message = fmt.Sprintf("answers don't satisfy rule '%s' (%s)", rule.Id, rule.Condition)
+	}
+	return ValidationError{
+		Type:    RuleViolationErrType,
+		Message: message,
+		Context: map[string]interface{}{
+			"rule_id":   rule.Id,
+			"condition": rule.Condition,
+		},
+	}
+}
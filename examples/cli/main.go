@@ -52,13 +52,8 @@ func askQuestions(questionnaire gdq.Questionnaire) map[string]int {
 }
 
 func displayProgress(progress *gdq.Progress) {
-	if progress == nil {
-		return
-	}
-
 	fmt.Printf("\n📊 Progress: %d/%d questions answered\n", progress.Current, progress.Total)
-	percentage := float64(progress.Current) / float64(progress.Total) * 100
-	fmt.Printf("🔄 %.0f%% complete\n", percentage)
+	fmt.Printf("🔄 %d%% complete\n", progress.Percent)
 }
 
 func askQuestion(question gdq.Question) int {
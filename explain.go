@@ -0,0 +1,143 @@
+package go_dynamic_questionnaire
+
+import "fmt"
+
+// Explanation tells why a question was shown or hidden by Next.
+// Explanations are only computed when Next is called with WithExplain.
+//
+// Example JSON representation:
+//
+//	{
+//	  "question_id": "language",
+//	  "shown": false,
+//	  "reason": "condition_not_met",
+//	  "condition": "answers[\"experience\"] == 1",
+//	  "condition_result": false
+//	}
+type Explanation struct {
+	QuestionID          string   `json:"question_id"`                    // The explained question
+	Shown               bool     `json:"shown"`                          // Whether the question is part of Response.Questions
+	Reason              string   `json:"reason"`                         // Why the question is shown or hidden (e.g. ConditionNotMetReason)
+	Condition           string   `json:"condition,omitempty"`            // The condition of the question, as evaluated (including when rules and jumps)
+	ConditionResult     *bool    `json:"condition_result,omitempty"`     // The result of the condition (nil when it wasn't evaluated)
+	MissingDependencies []string `json:"missing_dependencies,omitempty"` // The dependencies that are not answered yet
+}
+
+const (
+	// ShownReason is the Explanation reason of the questions returned by Next.
+	ShownReason = "shown"
+
+	// AnsweredReason is the Explanation reason of the questions already answered.
+	AnsweredReason = "answered"
+
+	// TerminatedReason is the Explanation reason of the questions hidden because the questionnaire
+	// ended early (see complete_when, terminates and terminate_if).
+	TerminatedReason = "terminated"
+
+	// MissingDependenciesReason is the Explanation reason of the questions depending on unanswered questions.
+	MissingDependenciesReason = "missing_dependencies"
+
+	// ConditionNotMetReason is the Explanation reason of the questions whose condition is false.
+	ConditionNotMetReason = "condition_not_met"
+
+	// NoAvailableAnswerReason is the Explanation reason of the questions whose answer options
+	// are all hidden by their condition.
+	NoAvailableAnswerReason = "no_available_answer"
+
+	// CompletedReason is the Explanation reason of the optional questions left out of the page
+	// of a questionnaire completed because every required question is answered.
+	CompletedReason = "completed"
+
+	// HiddenReason is the Explanation reason of the unanswered hidden questions, which are never displayed
+	// (see WithContextAnswers).
+	HiddenReason = "hidden"
+
+	// OutsideTagsReason is the Explanation reason of the eligible questions left out
+	// because they have none of the tags of the call (see WithTags).
+	OutsideTagsReason = "outside_tags"
+
+	// OutsidePageReason is the Explanation reason of the eligible questions left out
+	// by the page size (see WithPageSize).
+	OutsidePageReason = "outside_page"
+)
+
+// explain tells, for every question in configuration order, why it is part of the returned questions or not.
+// Conditions are evaluated again, so explanations are only computed on demand.
+func (q *questionnaire) explain(answers map[string]int, returned []Question, terminated, completed bool) ([]Explanation, error) {
+	shown := make(map[string]bool, len(returned))
+	for _, question := range returned {
+		shown[question.Id] = true
+	}
+
+	explanations := make([]Explanation, 0, len(q.QuestionList))
+	for _, question := range q.QuestionList {
+		explanation, err := q.explainQuestion(question, answers, terminated)
+		if err != nil {
+			return nil, err
+		}
+
+		if explanation.Reason == "" {
+			switch {
+			case question.Hidden:
+				explanation.Reason = HiddenReason
+			case !q.inScope(&question):
+				explanation.Reason = OutsideTagsReason
+			case shown[question.Id]:
+				explanation.Shown = true
+				explanation.Reason = ShownReason
+			case completed:
+				explanation.Reason = CompletedReason
+			default:
+				explanation.Reason = OutsidePageReason
+			}
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// explainQuestion explains why the question is hidden.
+// The reason is left empty when the question is eligible.
+func (q *questionnaire) explainQuestion(question question, answers map[string]int, terminated bool) (Explanation, error) {
+	explanation := Explanation{QuestionID: question.Id, Condition: question.Condition}
+
+	if q.isQuestionAnswered(question, answers) {
+		explanation.Reason = AnsweredReason
+		return explanation, nil
+	}
+	if terminated {
+		explanation.Reason = TerminatedReason
+		return explanation, nil
+	}
+
+	for _, depID := range question.DependsOn {
+		if _, answered := answers[depID]; !answered {
+			explanation.MissingDependencies = append(explanation.MissingDependencies, depID)
+		}
+	}
+	if len(explanation.MissingDependencies) > 0 {
+		explanation.Reason = MissingDependenciesReason
+		return explanation, nil
+	}
+
+	if question.Condition != "" {
+		result, err := q.evaluateCondition(question.Condition, answers)
+		if err != nil {
+			return Explanation{}, fmt.Errorf("failed to explain question '%s': %w", question.Id, err)
+		}
+		explanation.ConditionResult = &result
+		if !result {
+			explanation.Reason = ConditionNotMetReason
+			return explanation, nil
+		}
+	}
+
+	available, err := q.availableAnswers(question, answers)
+	if err != nil {
+		return Explanation{}, fmt.Errorf("failed to explain question '%s': %w", question.Id, err)
+	}
+	if len(available) == 0 && question.hasOptions() {
+		explanation.Reason = NoAvailableAnswerReason
+	}
+	return explanation, nil
+}
@@ -0,0 +1,396 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// questionReferencePrefixes lists the expression fragments that reference a question ID
+// as their first argument. They are used to extract the dependencies of a condition.
+var questionReferencePrefixes = []string{
+	`answers[`,
+	`answered(`,
+	`skipped(`,
+	`answerText(`,
+	`npsCategory(`,
+	`values[`,
+}
+
+// questionListReferencePrefixes lists the expression fragments whose arguments are all question IDs.
+var questionListReferencePrefixes = []string{
+	`anyAnswered(`,
+	`allAnswered(`,
+}
+
+// conditionEnv builds the expression environment used to evaluate conditions.
+// It is made of the built-in environment (see builtinEnv)
+// and the custom functions registered with WithFunctions.
+func (q *questionnaire) conditionEnv(answers map[string]int) map[string]interface{} {
+	env := q.builtinEnv(answers)
+	for name, function := range q.functions {
+		env[name] = function
+	}
+	return env
+}
+
+// builtinEnv builds the built-in part of the expression environment.
+//
+// The environment exposes:
+//   - answers: the map of question ID to answer choice (0 for the questions that aren't single choice)
+//   - values: the map of question ID to the answer of the questions that aren't single choice
+//     (see NextAnswers): the chosen options ([]int), the text (string) or the number (float64)
+//   - answered(id): whether the question was answered (skipped questions count as answered)
+//   - anyAnswered(ids...), allAnswered(ids...): whether any/all of the questions were answered
+//   - countAnswered(): the number of answered questions
+//   - skipped(id): whether the question was answered with SkipAnswer
+//   - anyOf(value, candidates...), noneOf(value, candidates...): whether the value is one/none of the candidates
+//   - score: the sum of the scores of the chosen answers
+//     (computed by evaluateExpression, only for the conditions using it)
+//   - computed: the map of computed value name to its value (see computeValues),
+//     also computed by evaluateExpression, only for the conditions using it
+//   - answerText(id): the text of the chosen answer, in the default locale
+//     (empty when the question is unanswered or skipped)
+//   - npsCategory(id): the category of the answer of an nps question: "promoter", "passive" or "detractor"
+//     (empty when the question isn't an answered nps question)
+func (q *questionnaire) builtinEnv(answers map[string]int) map[string]interface{} {
+	answered := func(questionID string) bool {
+		_, ok := answers[questionID]
+		return ok
+	}
+	anyOf := func(value int, candidates ...int) bool {
+		return slices.Contains(candidates, value)
+	}
+
+	return map[string]interface{}{
+		"answers":  answers,
+		"values":   q.values,
+		"answered": answered,
+		"anyAnswered": func(questionIDs ...string) bool {
+			return slices.ContainsFunc(questionIDs, answered)
+		},
+		"allAnswered": func(questionIDs ...string) bool {
+			for _, questionID := range questionIDs {
+				if !answered(questionID) {
+					return false
+				}
+			}
+			return true
+		},
+		"countAnswered": func() int {
+			return len(answers)
+		},
+		"skipped": func(questionID string) bool {
+			answer, ok := answers[questionID]
+			return ok && answer == SkipAnswer
+		},
+		"anyOf": anyOf,
+		"noneOf": func(value int, candidates ...int) bool {
+			return !anyOf(value, candidates...)
+		},
+		"score":    0.0,
+		"computed": map[string]interface{}(nil),
+		"answerText": func(questionID string) string {
+			return q.answerText(questionID, answers)
+		},
+		"npsCategory": func(questionID string) string {
+			question := q.findQuestionByID(questionID)
+			score, ok := q.values[questionID].(float64)
+			if question == nil || question.kind() != NPSQuestion || !ok {
+				return ""
+			}
+			return npsCategory(score)
+		},
+	}
+}
+
+// validateFunctions checks that the custom functions are functions and don't shadow the built-in environment.
+func (q *questionnaire) validateFunctions() error {
+	builtins := q.builtinEnv(nil)
+	for name, function := range q.functions {
+		if _, exists := builtins[name]; exists {
+			return fmt.Errorf("function %q conflicts with a built-in condition helper", name)
+		}
+		if function == nil || reflect.TypeOf(function).Kind() != reflect.Func {
+			return fmt.Errorf("function %q is not a function: got %T", name, function)
+		}
+	}
+	return nil
+}
+
+// answerText returns the text of the answer chosen for a question, in the default locale (see formatAnswer).
+// It returns an empty string if the question doesn't exist, is unanswered or skipped.
+func (q *questionnaire) answerText(questionID string, answers map[string]int) string {
+	question := q.findQuestionByID(questionID)
+	if question == nil {
+		return ""
+	}
+	return q.formatAnswer(question, answers, q.DefaultLocale)
+}
+
+// formatAnswer returns the text of the answer chosen for the question, in the locale:
+// the texts of the chosen options separated by commas, or the text or number answered.
+// It returns an empty string if the question is unanswered or skipped.
+func (q *questionnaire) formatAnswer(question *question, answers map[string]int, locale string) string {
+	switch value := q.values[question.Id].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	selected := q.selectedChoices(question, answers)
+	texts := make([]string, len(selected))
+	for i, choice := range selected {
+		texts[i] = question.Answers[choice-1].Text.resolve(locale, q.DefaultLocale)
+	}
+	return strings.Join(texts, ", ")
+}
+
+// compileConditions compiles every question, answer option and closing remark condition,
+// so that invalid expressions are reported when the questionnaire is created
+// and Next doesn't recompile the same expressions on every call.
+func (q *questionnaire) compileConditions() error {
+	q.programs = make(map[string]*vm.Program)
+	q.typedEnvironment = q.typedEnv()
+	var errs []error
+
+	for _, question := range q.QuestionList {
+		errs = append(errs, q.compileCondition(question.Condition, "question_id", question.Id))
+		errs = append(errs, q.compileCondition(question.TerminateIf, "question_id", question.Id))
+		errs = append(errs, q.compileExpression(question.Value, "question_id", question.Id, false))
+		for _, option := range question.Answers {
+			errs = append(errs, q.compileCondition(option.Condition, "question_id", question.Id))
+		}
+	}
+	for _, remark := range q.Remarks {
+		errs = append(errs, q.compileCondition(remark.Condition, "remark_id", remark.Id))
+	}
+	for _, rule := range q.Rules {
+		errs = append(errs, q.compileCondition(rule.Condition, "rule_id", rule.Id))
+	}
+	errs = append(errs, q.compileCondition(q.CompleteWhen, "setting", "complete_when"))
+	errs = append(errs, q.compileComputedValues())
+
+	return errors.Join(errs...)
+}
+
+// compileCondition compiles a condition and caches the resulting program.
+// The owner key and ID identify the element declaring the condition in validation errors.
+func (q *questionnaire) compileCondition(condition, ownerKey, ownerID string) error {
+	return q.compileExpression(condition, ownerKey, ownerID, true)
+}
+
+// compileExpression compiles an expression and caches the resulting program.
+// Conditions must return a boolean, which is type-checked in strict validation mode;
+// other expressions (see computed values) can return any value.
+func (q *questionnaire) compileExpression(condition, ownerKey, ownerID string, isCondition bool) error {
+	if condition == "" || q.programs[condition] != nil {
+		return nil
+	}
+
+	if q.maxExpressionLength > 0 && len(condition) > q.maxExpressionLength {
+		err := fmt.Errorf("expression length %d exceeds the maximum of %d", len(condition), q.maxExpressionLength)
+		return invalidConditionError(ownerKey, ownerID, condition, err)
+	}
+
+	program, err := expr.Compile(condition, q.compileOptions(q.conditionEnv(nil), isCondition)...)
+	if err != nil {
+		return invalidConditionError(ownerKey, ownerID, condition, err)
+	}
+
+	if q.strictValidation {
+		for _, id := range extractQuestionIDs(condition) {
+			if q.findQuestionByID(id) == nil {
+				return q.withSuggestedQuestionIDs(unknownQuestionReferenceError(ownerKey, ownerID, condition, id), id)
+			}
+		}
+	}
+	if err := q.typeCheck(condition, ownerKey, ownerID, isCondition); err != nil {
+		return err
+	}
+
+	q.programs[condition] = program
+	return nil
+}
+
+// compileOptions returns the expr options used to compile expressions against the environment,
+// including the configured evaluation limits and, in strict mode, the boolean type check of conditions.
+func (q *questionnaire) compileOptions(env map[string]interface{}, isCondition bool) []expr.Option {
+	options := []expr.Option{expr.Env(env)}
+	if q.strictValidation && isCondition {
+		options = append(options, expr.AsBool())
+	}
+	if q.maxExpressionNodes > 0 {
+		options = append(options, expr.MaxNodes(q.maxExpressionNodes))
+	}
+	for _, name := range q.disallowedBuiltins {
+		options = append(options, expr.DisableBuiltin(name))
+	}
+	return options
+}
+
+// runProgram runs a compiled condition against the environment, within the configured memory budget
+// (see WithEvaluationMemoryBudget). The expr VM can't be interrupted: the memory budget and the maximum
+// number of nodes of the conditions (see WithMaxExpressionNodes) bound the work of each evaluation instead.
+func (q *questionnaire) runProgram(program *vm.Program, env map[string]interface{}) (interface{}, error) {
+	machine := vm.VM{MemoryBudget: q.evaluationMemoryBudget}
+	return machine.Run(program, env)
+}
+
+// checkEvaluationDepth returns an error wrapping ErrEvaluationDepthExceeded when the depth
+// (a number of evaluation rounds or the length of a dependency chain) exceeds the maximum evaluation depth.
+func (q *questionnaire) checkEvaluationDepth(depth int, evaluation string) error {
+	if q.maxEvaluationDepth > 0 && depth > q.maxEvaluationDepth {
+		return fmt.Errorf("%w: %s exceeded the maximum depth of %d", ErrEvaluationDepthExceeded, evaluation, q.maxEvaluationDepth)
+	}
+	return nil
+}
+
+// evaluateCondition runs a condition expression against the provided answers.
+// The condition must evaluate to a boolean.
+// Conditions are normally compiled by compileConditions; others are compiled on the fly.
+func (q *questionnaire) evaluateCondition(condition string, answers map[string]int) (bool, error) {
+	result, err := q.evaluateExpression(condition, answers, true)
+	if err != nil {
+		return false, err
+	}
+	show, ok := result.(bool)
+	if !ok {
+		err := fmt.Errorf("condition '%s' does not return a boolean", condition)
+		q.logEvaluation(condition, false, err)
+		return false, err
+	}
+	q.logEvaluation(condition, show, nil)
+	return show, nil
+}
+
+// evaluateExpression runs an expression against the provided answers and returns its result.
+func (q *questionnaire) evaluateExpression(condition string, answers map[string]int, isCondition bool) (interface{}, error) {
+	env := q.conditionEnv(answers)
+
+	program := q.programs[condition]
+	if program == nil {
+		var err error
+		program, err = expr.Compile(condition, q.compileOptions(env, isCondition)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile condition expression: %w", err)
+		}
+	}
+
+	// Computing the score walks every answer: skip it for the conditions that don't need it
+	if referencesIdentifier(condition, "score") {
+		env["score"] = q.score(answers)
+	}
+	// Computed values never reference computed values, so this doesn't recurse
+	if referencesIdentifier(condition, "computed") {
+		computed, err := q.computeValues(answers)
+		if err != nil {
+			return nil, err
+		}
+		env["computed"] = computed
+	}
+
+	var start time.Time
+	if q.metrics != nil {
+		start = time.Now()
+	}
+	result, err := q.runProgram(program, env)
+	if q.metrics != nil {
+		q.metrics.ConditionEvaluated(time.Since(start))
+	}
+	if err != nil {
+		q.logEvaluation(condition, false, err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// logEvaluation emits a debug log with the outcome of a condition evaluation (see WithLogger),
+// and adds it to the audit trail of the Next call in progress (see WithAuditSink).
+func (q *questionnaire) logEvaluation(condition string, result bool, err error) {
+	q.auditEvaluation(condition, result, err)
+	if q.logger == nil {
+		return
+	}
+	if err != nil {
+		q.logger.Debug("condition evaluation failed", "condition", condition, "error", err)
+		return
+	}
+	q.logger.Debug("condition evaluated", "condition", condition, "result", result)
+}
+
+// extractQuestionIDs extracts question IDs referenced in a condition expression.
+// This is a simple implementation that looks for patterns like answers["question_id"], answers['question_id']
+// or helper calls such as skipped("question_id") and allAnswered("q1", "q2")
+// (see questionReferencePrefixes and questionListReferencePrefixes).
+// It is designed for speed over complexity, assuming conditions are simple and well-formed.
+// It does not handle complex expressions or nested conditions.
+func extractQuestionIDs(condition string) []string {
+	var ids []string
+
+	for i := 0; i < len(condition); i++ {
+		// A reference can't be the end of a longer identifier (e.g. myanswers[)
+		if i > 0 && isIdentifierChar(condition[i-1]) {
+			continue
+		}
+
+		for _, prefix := range questionReferencePrefixes {
+			if strings.HasPrefix(condition[i:], prefix) {
+				ids = appendQuotedStrings(ids, condition, i+len(prefix), `]`)
+			}
+		}
+		for _, prefix := range questionListReferencePrefixes {
+			if strings.HasPrefix(condition[i:], prefix) {
+				ids = appendQuotedStrings(ids, condition, i+len(prefix), `,`)
+			}
+		}
+	}
+
+	return ids
+}
+
+// appendQuotedStrings reads the quoted strings (either " or ' quoted) starting at position start
+// and appends the ones not already present to ids.
+// Strings are read as long as they are followed by the separator, which allows to read
+// comma-separated lists: passing any other separator reads a single string.
+func appendQuotedStrings(ids []string, condition string, start int, separator string) []string {
+	for {
+		start = skipSpaces(condition, start)
+
+		// Find the quote character (either " or ')
+		if start >= len(condition) || (condition[start] != '"' && condition[start] != '\'') {
+			return ids
+		}
+		quote := condition[start]
+		start++ // Skip opening quote
+
+		// Find closing quote
+		end := start
+		for end < len(condition) && condition[end] != quote {
+			end++
+		}
+		if end >= len(condition) {
+			return ids
+		}
+
+		// Extract the question ID
+		questionID := condition[start:end]
+		if questionID != "" && !contains(ids, questionID) {
+			ids = append(ids, questionID)
+		}
+
+		start = skipSpaces(condition, end+1)
+		if separator != "," || start >= len(condition) || condition[start] != ',' {
+			return ids
+		}
+		start++ // Skip separator
+	}
+}
@@ -0,0 +1,69 @@
+package go_dynamic_questionnaire
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expression", func() {
+	Describe("extractQuestionIDs", func() {
+		It("should extract answers references with both quote styles", func() {
+			Expect(extractQuestionIDs(`answers["q1"] == 1 && answers['q2'] == 2`)).To(Equal([]string{"q1", "q2"}))
+		})
+
+		It("should extract helper references", func() {
+			Expect(extractQuestionIDs(`answered("q1") && !skipped("q2") && answerText("q3") == "Go"`)).To(Equal([]string{"q1", "q2", "q3"}))
+		})
+
+		It("should extract every question of list helpers", func() {
+			Expect(extractQuestionIDs(`allAnswered("q1", 'q2') || anyAnswered("q3")`)).To(Equal([]string{"q1", "q2", "q3"}))
+		})
+
+		It("should not extract duplicates", func() {
+			Expect(extractQuestionIDs(`answers["q1"] == 1 || answered("q1")`)).To(Equal([]string{"q1"}))
+		})
+
+		It("should ignore identifiers ending with a helper name", func() {
+			Expect(extractQuestionIDs(`myanswers["q1"] == 1 || countAnswered() > 2`)).To(BeEmpty())
+		})
+
+		It("should ignore malformed references", func() {
+			Expect(extractQuestionIDs(`answers["q1`)).To(BeEmpty())
+			Expect(extractQuestionIDs(`answers[q1]`)).To(BeEmpty())
+			Expect(extractQuestionIDs(`answers[`)).To(BeEmpty())
+		})
+	})
+
+	Describe("builtinEnv", func() {
+		var q *questionnaire
+
+		BeforeEach(func() {
+			q = &questionnaire{}
+		})
+
+		evaluate := func(condition string, answers map[string]int) bool {
+			result, err := q.evaluateCondition(condition, answers)
+			Expect(err).ToNot(HaveOccurred())
+			return result
+		}
+
+		It("should tell whether questions were answered", func() {
+			answers := map[string]int{"q1": 1, "q2": SkipAnswer}
+			Expect(evaluate(`answered("q1")`, answers)).To(BeTrue())
+			Expect(evaluate(`answered("q2")`, answers)).To(BeTrue())
+			Expect(evaluate(`answered("q3")`, answers)).To(BeFalse())
+			Expect(evaluate(`allAnswered("q1", "q2")`, answers)).To(BeTrue())
+			Expect(evaluate(`allAnswered("q1", "q3")`, answers)).To(BeFalse())
+			Expect(evaluate(`anyAnswered("q3", "q1")`, answers)).To(BeTrue())
+			Expect(evaluate(`anyAnswered("q3", "q4")`, answers)).To(BeFalse())
+			Expect(evaluate(`countAnswered() == 2`, answers)).To(BeTrue())
+		})
+
+		It("should compare values to candidates", func() {
+			answers := map[string]int{"q1": 3}
+			Expect(evaluate(`anyOf(answers["q1"], 1, 3)`, answers)).To(BeTrue())
+			Expect(evaluate(`anyOf(answers["q1"], 1, 2)`, answers)).To(BeFalse())
+			Expect(evaluate(`noneOf(answers["q1"], 1, 2)`, answers)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package gdqhttp
+
+import (
+	"context"
+	"net/http"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+type (
+	// IdentityFunc extracts the identity of the respondent from a request (see WithIdentity),
+	// for instance the subject of a verified JWT or the user of a session cookie.
+	// It returns an error when the request isn't authenticated.
+	IdentityFunc func(r *http.Request) (string, error)
+
+	// AccessFunc checks that the request can access the questionnaire with the ID (see WithAccessCheck).
+	// The identity of the respondent is available with RespondentID(r.Context()).
+	// It returns an error when access is denied.
+	AccessFunc func(r *http.Request, questionnaireID string) error
+
+	// respondentKey is the context key of the identity of the respondent.
+	respondentKey struct{}
+
+	// accessibleRegistry is a registry restricted to the questionnaires a request can access.
+	accessibleRegistry struct {
+		Registry
+		r      *http.Request
+		access AccessFunc
+	}
+)
+
+// RespondentID returns the identity of the respondent extracted by the IdentityFunc (see WithIdentity),
+// empty when the request wasn't authenticated.
+func RespondentID(ctx context.Context) string {
+	id, _ := ctx.Value(respondentKey{}).(string)
+	return id
+}
+
+// authenticated serves the requests whose respondent is identified with the handler, the identity stored
+// in their context, and answers the others with 401 Unauthorized.
+func authenticated(handler http.Handler, c *config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := c.identify(r)
+		if err != nil {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), respondentKey{}, id)))
+	})
+}
+
+// accessible returns the registry restricted to the questionnaires the request can access (see WithAccessCheck).
+func (c *config) accessible(r *http.Request, registry Registry) Registry {
+	if c.access == nil {
+		return registry
+	}
+	return accessibleRegistry{Registry: registry, r: r, access: c.access}
+}
+
+// IDs returns the IDs of the questionnaires the request can access.
+func (a accessibleRegistry) IDs() []string {
+	var ids []string
+	for _, id := range a.Registry.IDs() {
+		if a.access(a.r, id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Get returns the questionnaire with the ID, and whether it exists and the request can access it.
+func (a accessibleRegistry) Get(id string) (gdq.Questionnaire, bool) {
+	if a.access(a.r, id) != nil {
+		return nil, false
+	}
+	return a.Registry.Get(id)
+}
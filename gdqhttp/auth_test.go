@@ -0,0 +1,82 @@
+package gdqhttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authentication", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]`))
+		Expect(err).ToNot(HaveOccurred())
+
+		identify := func(r *http.Request) (string, error) {
+			if user := r.Header.Get("X-User"); user != "" {
+				return user, nil
+			}
+			return "", errors.New("missing user")
+		}
+		access := func(r *http.Request, id string) error {
+			if strings.HasPrefix(id, "internal") && gdqhttp.RespondentID(r.Context()) != "alice" {
+				return errors.New("access denied")
+			}
+			return nil
+		}
+		handler = gdqhttp.Handler(gdqhttp.Map{"public": q, "internal": q}, gdqhttp.WithIdentity(identify), gdqhttp.WithAccessCheck(access))
+	})
+
+	serve := func(method, path, user string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, nil)
+		if user != "" {
+			request.Header.Set("X-User", user)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	It("should reject unauthenticated requests", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/public/next", "")
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(recorder.Body.String()).To(ContainSubstring("missing user"))
+	})
+
+	It("should check the access to each questionnaire", func() {
+		Expect(serve(http.MethodPost, "/questionnaires/public/next", "bob").Code).To(Equal(http.StatusOK))
+		Expect(serve(http.MethodPost, "/questionnaires/internal/next", "alice").Code).To(Equal(http.StatusOK))
+
+		recorder := serve(http.MethodPost, "/questionnaires/internal/next", "bob")
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		Expect(recorder.Body.String()).To(ContainSubstring("access denied"))
+	})
+
+	It("should not reveal which denied questionnaires exist", func() {
+		Expect(serve(http.MethodPost, "/questionnaires/internal-hr/next", "bob").Code).To(Equal(http.StatusForbidden))
+		Expect(serve(http.MethodPost, "/questionnaires/internal-hr/next", "alice").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should only list the questionnaires the respondent can access", func() {
+		var list gdqhttp.ListResponse
+		Expect(json.Unmarshal(serve(http.MethodGet, "/questionnaires", "bob").Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Questionnaires).To(Equal([]gdqhttp.QuestionnaireSummary{{ID: "public"}}))
+
+		var document map[string]any
+		Expect(json.Unmarshal(serve(http.MethodGet, "/openapi.json", "bob").Body.Bytes(), &document)).To(Succeed())
+		Expect(document["paths"]).To(HaveKey("/questionnaires/public/next"))
+		Expect(document["paths"]).ToNot(HaveKey("/questionnaires/internal/next"))
+	})
+})
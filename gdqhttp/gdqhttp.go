@@ -0,0 +1,263 @@
+// Package gdqhttp serves questionnaires built with go-dynamic-questionnaire over HTTP,
+// with the standard library only.
+//
+// Handler exposes the questionnaires of a registry through a JSON API:
+//
+//	GET  /openapi.json                 describe the API with an OpenAPI 3 document (see OpenAPI)
+//	GET  /questionnaires               list the available questionnaires
+//	POST /questionnaires/{id}/next     get the next questions given the answers
+//	POST /questionnaires/{id}/answers  convert answers expressed with answer option IDs into answer choices
+//	GET  /questionnaires/{id}/session  stream the questionnaire over a WebSocket, answer by answer
+//
+// For example:
+//
+//	q, err := gdq.New("survey.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	registry := gdqhttp.Map{"survey": q}
+//	http.Handle("/api/", http.StripPrefix("/api", gdqhttp.Handler(registry)))
+//	log.Fatal(http.ListenAndServe(":8080", nil))
+package gdqhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+type (
+	// Registry provides the questionnaires served by Handler.
+	// Implementations must be safe for concurrent use.
+	Registry interface {
+		// IDs returns the IDs of the available questionnaires.
+		IDs() []string
+
+		// Get returns the questionnaire with the ID, and whether it exists.
+		Get(id string) (gdq.Questionnaire, bool)
+	}
+
+	// Map is a Registry of questionnaires keyed by ID.
+	// It must not be modified while being served.
+	Map map[string]gdq.Questionnaire
+
+	// ListResponse is the body of the list endpoint.
+	ListResponse struct {
+		Questionnaires []QuestionnaireSummary `json:"questionnaires"` // Available questionnaires, sorted by ID
+	}
+
+	// QuestionnaireSummary describes an available questionnaire.
+	QuestionnaireSummary struct {
+		ID string `json:"id"` // ID of the questionnaire in the registry
+	}
+
+	// NextRequest is the body of the next endpoint. An empty body starts the questionnaire.
+	NextRequest struct {
+		Answers  map[string]gdq.Answer         `json:"answers,omitempty"`  // Answers given so far: answer choices, arrays of choices, texts or numbers (see gdq.Answer)
+		Locale   string                        `json:"locale,omitempty"`   // Locale of the returned texts and error messages (see gdq.WithLocale)
+		Metadata map[string]gdq.AnswerMetadata `json:"metadata,omitempty"` // Metadata of the answers, returned once completed (see gdq.WithAnswerMetadata)
+	}
+
+	// AnswersRequest is the body of the answers endpoint.
+	AnswersRequest struct {
+		Answers map[string]string `json:"answers"`          // Answers given so far, as answer option IDs
+		Locale  string            `json:"locale,omitempty"` // Locale of the error messages
+	}
+
+	// AnswersResponse is the body returned by the answers endpoint.
+	AnswersResponse struct {
+		Answers map[string]int `json:"answers"` // The answers as 1-indexed answer choices, ready for the next endpoint
+	}
+
+	// ErrorResponse is the body returned when a request fails.
+	ErrorResponse struct {
+		Error  string                `json:"error"`            // Description of the failure, localized when it comes from validation errors
+		Errors []gdq.ValidationError `json:"errors,omitempty"` // Validation errors in the request, if any
+	}
+)
+
+// IDs returns the IDs of the questionnaires, sorted.
+func (m Map) IDs() []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Get returns the questionnaire with the ID, and whether it exists.
+func (m Map) Get(id string) (gdq.Questionnaire, bool) {
+	q, ok := m[id]
+	return q, ok
+}
+
+// Handler returns an http.Handler serving the questionnaires of the registry (see the package documentation).
+//
+// Requests are answered with JSON: invalid bodies with 400 Bad Request, bodies larger than
+// the maximum body size (see WithMaxBodySize) with 413 Request Entity Too Large, unknown questionnaires
+// with 404 Not Found, and invalid answers with 422 Unprocessable Entity and the validation errors.
+// With WithIdentity and WithAccessCheck, unauthenticated requests are answered with 401 Unauthorized,
+// and requests for a questionnaire they can't access with 403 Forbidden.
+// Responses about a questionnaire carry its checksum as ETag (and in the body of the next endpoint);
+// requests with a stale If-Match header are answered with 412 Precondition Failed.
+// Options such as WithSessionStore or WithRateLimiter tune how requests are served.
+func Handler(registry Registry, opts ...Option) http.Handler {
+	c := newConfig(opts)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", openAPIHandler(registry, c))
+	mux.HandleFunc("GET /questionnaires", func(w http.ResponseWriter, r *http.Request) {
+		list := ListResponse{Questionnaires: []QuestionnaireSummary{}}
+		for _, id := range c.accessible(r, registry).IDs() {
+			list.Questionnaires = append(list.Questionnaires, QuestionnaireSummary{ID: id})
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+	mux.HandleFunc("POST /questionnaires/{id}/next", func(w http.ResponseWriter, r *http.Request) {
+		q, ok := lookup(w, r, registry, c)
+		if !ok {
+			return
+		}
+
+		var request NextRequest
+		if !c.decode(w, r, &request) {
+			return
+		}
+		if request.Answers == nil {
+			request.Answers = map[string]gdq.Answer{}
+		}
+
+		opts := []gdq.NextOption{gdq.WithChecksum(), gdq.WithAllAnswerErrors()}
+		if request.Locale != "" {
+			opts = append(opts, gdq.WithLocale(request.Locale))
+		}
+		if request.Metadata != nil {
+			opts = append(opts, gdq.WithAnswerMetadata(request.Metadata))
+		}
+		response, err := q.NextAnswers(request.Answers, opts...)
+		if err != nil {
+			writeError(w, err, request.Locale)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	mux.HandleFunc("POST /questionnaires/{id}/answers", func(w http.ResponseWriter, r *http.Request) {
+		q, ok := lookup(w, r, registry, c)
+		if !ok {
+			return
+		}
+
+		var request AnswersRequest
+		if !c.decode(w, r, &request) {
+			return
+		}
+
+		answers, err := q.ResolveAnswers(request.Answers)
+		if err != nil {
+			writeError(w, err, request.Locale)
+			return
+		}
+		writeJSON(w, http.StatusOK, AnswersResponse{Answers: answers})
+	})
+	mux.HandleFunc("GET /questionnaires/{id}/session", sessionHandler(registry, c))
+
+	// Requests are rate limited before the respondent is identified, so that rejected clients don't reach the identity provider
+	var handler http.Handler = mux
+	if c.identify != nil {
+		handler = authenticated(handler, c)
+	}
+	if c.limiter != nil {
+		handler = rateLimited(handler, c)
+	}
+	return handler
+}
+
+// lookup returns the questionnaire of the request path, or answers 403 Forbidden when the request
+// can't access it (see WithAccessCheck), whether it exists or not, and 404 Not Found when it doesn't exist.
+//
+// The checksum of the questionnaire is returned as ETag. When the request has an If-Match header
+// that doesn't match it, the definition changed since the client started: lookup answers
+// 412 Precondition Failed so that the client can restart gracefully.
+func lookup(w http.ResponseWriter, r *http.Request, registry Registry, c *config) (gdq.Questionnaire, bool) {
+	id := r.PathValue("id")
+	// Access is checked first, so that denied requests can't tell which questionnaires exist
+	if c.access != nil {
+		if err := c.access(r, id); err != nil {
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+			return nil, false
+		}
+	}
+	q, ok := registry.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("questionnaire '%s' not found", id)})
+		return nil, false
+	}
+
+	etag := `"` + q.Checksum() + `"`
+	w.Header().Set("ETag", etag)
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !matchesETag(ifMatch, etag) {
+		writeJSON(w, http.StatusPreconditionFailed, ErrorResponse{Error: fmt.Sprintf("questionnaire '%s' has changed", id)})
+		return nil, false
+	}
+	return q, true
+}
+
+// matchesETag reports whether the If-Match header value lists the entity tag.
+func matchesETag(ifMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// decode reads the JSON body of the request into v, or answers 400 Bad Request when it is invalid,
+// and 413 Request Entity Too Large when it is larger than the maximum body size (see WithMaxBodySize).
+// An empty body leaves v unchanged.
+func (c *config) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body := r.Body
+	if c.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, c.maxBodySize)
+	}
+	err := json.NewDecoder(body).Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return true
+	}
+	if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: fmt.Sprintf("request body exceeds the maximum of %d bytes", tooLarge.Limit),
+		})
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+	return false
+}
+
+// writeError answers with the error (see errorResponse).
+func writeError(w http.ResponseWriter, err error, locale string) {
+	status, body := errorResponse(err, locale)
+	writeJSON(w, status, body)
+}
+
+// errorResponse describes the error with its validation errors (422 Unprocessable Entity),
+// or as 500 Internal Server Error when err is not a validation error.
+func errorResponse(err error, locale string) (int, ErrorResponse) {
+	validationErrs := gdq.ValidationErrors(err)
+	if len(validationErrs) == 0 {
+		return http.StatusInternalServerError, ErrorResponse{Error: err.Error()}
+	}
+	return http.StatusUnprocessableEntity, ErrorResponse{Error: gdq.LocalizeError(err, locale), Errors: validationErrs}
+}
+
+// writeJSON answers with the status and v encoded as JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,13 @@
+package gdqhttp_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGdqhttp(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gdqhttp Suite")
+}
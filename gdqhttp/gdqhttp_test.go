@@ -0,0 +1,180 @@
+package gdqhttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers:
+      - id: "free"
+        text: "Free"
+      - id: "pro"
+        text: "Pro"
+  - id: "seats"
+    text: "How many seats do you need?"
+    answers: ["1-10", "More"]
+    depends_on: ["plan"]
+    condition: 'answers["plan"] == 2'
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`))
+		Expect(err).ToNot(HaveOccurred())
+		handler = gdqhttp.Handler(gdqhttp.Map{"pricing": q, "another": q})
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		return recorder
+	}
+
+	It("should list the questionnaires", func() {
+		recorder := serve(http.MethodGet, "/questionnaires", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var list gdqhttp.ListResponse
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Questionnaires).To(Equal([]gdqhttp.QuestionnaireSummary{{ID: "another"}, {ID: "pricing"}}))
+	})
+
+	It("should start the questionnaire on an empty body", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response gdq.Response
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Questions).To(HaveLen(1))
+		Expect(response.Questions[0].Id).To(Equal("plan"))
+	})
+
+	It("should return the next questions given the answers", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", `{"answers": {"plan": 2, "seats": 1}}`)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response gdq.Response
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Completed).To(BeTrue())
+		Expect(response.ClosingRemarks).To(HaveLen(1))
+	})
+
+	It("should accept answers to text and multiple choice questions", func() {
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "features"
+    text: "Which features do you use?"
+    type: "multiple_choice"
+    answers: ["Reports", "Exports", "API"]
+  - id: "feedback"
+    text: "Anything to add?"
+    type: "text"`))
+		Expect(err).ToNot(HaveOccurred())
+		handler = gdqhttp.Handler(gdqhttp.Map{"feedback": q})
+
+		recorder := serve(http.MethodPost, "/questionnaires/feedback/next", `{"answers": {"features": [1, 3], "feedback": "Great!"}}`)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response gdq.Response
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Completed).To(BeTrue())
+	})
+
+	It("should convert answer option IDs into answer choices", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/answers", `{"answers": {"plan": "pro"}}`)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response gdqhttp.AnswersResponse
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Answers).To(Equal(map[string]int{"plan": 2}))
+	})
+
+	It("should report invalid answers with their validation errors", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", `{"answers": {"plan": 3}, "locale": "fr"}`)
+		Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		var response gdqhttp.ErrorResponse
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Errors).To(HaveLen(1))
+		Expect(response.Errors[0].Type).To(Equal(gdq.InvalidAnswerRangeErrType))
+		Expect(response.Error).To(Equal(gdq.LocalizeError(response.Errors[0], "fr")))
+	})
+
+	It("should report every invalid answer", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", `{"answers": {"plan": 3, "seats": 5}}`)
+		Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		var response gdqhttp.ErrorResponse
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Errors).To(HaveLen(2))
+	})
+
+	It("should reject unknown questionnaires", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/unknown/next", "")
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject invalid bodies", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", `{"answers": [}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject bodies larger than the maximum body size", func() {
+		body := `{"answers": {"plan": 1}}`
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]`))
+		Expect(err).ToNot(HaveOccurred())
+		handler = gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithMaxBodySize(int64(len(body)-1)))
+
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", body)
+		Expect(recorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		Expect(recorder.Body.String()).To(ContainSubstring("request body exceeds the maximum of 23 bytes"))
+
+		handler = gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithMaxBodySize(int64(len(body))))
+		recorder = serve(http.MethodPost, "/questionnaires/pricing/next", body)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should return the checksum of the questionnaire as ETag", func() {
+		recorder := serve(http.MethodPost, "/questionnaires/pricing/next", "")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response gdq.Response
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Checksum).ToNot(BeEmpty())
+		Expect(recorder.Header().Get("ETag")).To(Equal(`"` + response.Checksum + `"`))
+	})
+
+	It("should reject requests made for a previous definition", func() {
+		current := serve(http.MethodPost, "/questionnaires/pricing/next", "").Header().Get("ETag")
+
+		request := httptest.NewRequest(http.MethodPost, "/questionnaires/pricing/next", nil)
+		request.Header.Set("If-Match", current)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		request = httptest.NewRequest(http.MethodPost, "/questionnaires/pricing/next", nil)
+		request.Header.Set("If-Match", `"previous"`)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusPreconditionFailed))
+	})
+})
@@ -0,0 +1,188 @@
+package gdqhttp
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// OpenAPI generates an OpenAPI 3 document describing the API served by Handler for the questionnaires
+// of the registry, so that API consumers can generate typed clients.
+//
+// The next endpoint of each questionnaire is described on its own path, with the answers it accepts
+// (see gdq.Questionnaire.AnswersSchema). Paths are relative to where Handler is mounted.
+// The session endpoint is not described, as OpenAPI doesn't support WebSockets.
+func OpenAPI(registry Registry) map[string]any {
+	components := schemas{}
+	errorResponses := map[string]any{
+		"400": jsonResponse("Invalid request body", components.of(reflect.TypeFor[ErrorResponse]())),
+		"404": jsonResponse("Unknown questionnaire", components.of(reflect.TypeFor[ErrorResponse]())),
+		"422": jsonResponse("Invalid answers", components.of(reflect.TypeFor[ErrorResponse]())),
+	}
+
+	paths := map[string]any{
+		"/questionnaires": map[string]any{
+			"get": map[string]any{
+				"operationId": "listQuestionnaires",
+				"summary":     "List the available questionnaires",
+				"responses": map[string]any{
+					"200": jsonResponse("The available questionnaires", components.of(reflect.TypeFor[ListResponse]())),
+				},
+			},
+		},
+	}
+
+	for _, id := range registry.IDs() {
+		q, ok := registry.Get(id)
+		if !ok {
+			continue
+		}
+
+		name := componentName(id)
+		components[name+"Answers"] = q.AnswersSchema()
+		components[name+"NextRequest"] = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"answers": ref(name + "Answers"),
+				"locale":  map[string]any{"type": "string"},
+			},
+		}
+
+		paths["/questionnaires/"+id+"/next"] = map[string]any{
+			"post": map[string]any{
+				"operationId": "next" + name,
+				"summary":     "Get the next questions of the " + id + " questionnaire given the answers",
+				"requestBody": jsonBody(ref(name + "NextRequest")),
+				"responses": withResponse(errorResponses, "200",
+					jsonResponse("The next questions", components.of(reflect.TypeFor[gdq.Response]()))),
+			},
+		}
+		paths["/questionnaires/"+id+"/answers"] = map[string]any{
+			"post": map[string]any{
+				"operationId": "resolveAnswers" + name,
+				"summary":     "Convert answers of the " + id + " questionnaire expressed with answer option IDs into answer choices",
+				"requestBody": jsonBody(components.of(reflect.TypeFor[AnswersRequest]())),
+				"responses": withResponse(errorResponses, "200",
+					jsonResponse("The answers as answer choices", components.of(reflect.TypeFor[AnswersResponse]()))),
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Questionnaires",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": components},
+	}
+}
+
+// openAPIHandler serves the OpenAPI document of the questionnaires of the registry the request can access.
+func openAPIHandler(registry Registry, c *config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, OpenAPI(c.accessible(r, registry)))
+	}
+}
+
+// schemas holds the schemas of an OpenAPI document, by component name.
+type schemas map[string]any
+
+// of returns the schema of the Go type, as encoded by encoding/json.
+// Named structs are added to the components and referenced.
+func (s schemas) of(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.of(t.Elem())
+	case reflect.Struct:
+		if _, ok := s[t.Name()]; !ok {
+			s[t.Name()] = nil // Placeholder for recursive types
+			s[t.Name()] = s.structSchema(t)
+		}
+		return ref(t.Name())
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the object schema of the struct, from the json tags of its fields.
+// Fields are required unless they are omitted when empty.
+func (s schemas) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.of(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// componentName turns the questionnaire ID into a valid component name.
+func componentName(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// ref references the component schema.
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// jsonBody describes a JSON request body.
+func jsonBody(schema map[string]any) map[string]any {
+	return map[string]any{
+		"content": map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+// jsonResponse describes a JSON response.
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+// withResponse returns a copy of the responses with an additional response for the status.
+func withResponse(responses map[string]any, status string, response map[string]any) map[string]any {
+	result := maps.Clone(responses)
+	result[status] = response
+	return result
+}
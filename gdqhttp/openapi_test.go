@@ -0,0 +1,63 @@
+package gdqhttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var document map[string]any
+
+	BeforeEach(func() {
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]`))
+		Expect(err).ToNot(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		gdqhttp.Handler(gdqhttp.Map{"pricing-2024": q}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+	})
+
+	It("should describe the endpoints of every questionnaire", func() {
+		Expect(document).To(HaveKeyWithValue("openapi", "3.0.3"))
+		Expect(document["paths"]).To(HaveKey("/questionnaires"))
+		Expect(document["paths"]).To(HaveKey("/questionnaires/pricing-2024/next"))
+		Expect(document["paths"]).To(HaveKey("/questionnaires/pricing-2024/answers"))
+
+		next := document["paths"].(map[string]any)["/questionnaires/pricing-2024/next"].(map[string]any)["post"].(map[string]any)
+		Expect(next["responses"]).To(HaveKey("200"))
+		Expect(next["responses"]).To(HaveKey("422"))
+	})
+
+	It("should describe the answers of every questionnaire", func() {
+		schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+		Expect(schemas["pricing_2024NextRequest"]).To(HaveKeyWithValue("properties", HaveKeyWithValue("answers",
+			map[string]any{"$ref": "#/components/schemas/pricing_2024Answers"})))
+		Expect(schemas["pricing_2024Answers"]).To(HaveKeyWithValue("properties", map[string]any{
+			"plan": map[string]any{
+				"type":        "integer",
+				"title":       "Which plan are you on?",
+				"description": "1 = Free, 2 = Pro",
+				"enum":        []any{1.0, 2.0},
+			},
+		}))
+	})
+
+	It("should describe the response schemas from their JSON encoding", func() {
+		schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+		Expect(schemas).To(HaveKey("Question"))
+		Expect(schemas).To(HaveKey("ClosingRemark"))
+		Expect(schemas["Response"]).To(HaveKeyWithValue("required", ConsistOf("questions", "completed")))
+		Expect(schemas["Progress"]).To(HaveKeyWithValue("properties", HaveKeyWithValue("percent", map[string]any{"type": "integer"})))
+	})
+})
@@ -0,0 +1,124 @@
+package gdqhttp
+
+import (
+	"net/http"
+	"time"
+)
+
+type (
+	// Option configures the Handler.
+	Option func(*config)
+
+	// config holds the settings of the Handler.
+	config struct {
+		store       SessionStore               // Store persisting the WebSocket sessions, nil to keep them in memory per connection
+		now         func() time.Time           // Clock used to timestamp sessions
+		limiter     RateLimiter                // Rate limiter of the requests, nil to serve every request
+		limitKey    func(*http.Request) string // Key of the requests counted by the rate limiter
+		identify    IdentityFunc               // Function identifying the respondent of the requests, nil to serve anonymous requests
+		access      AccessFunc                 // Function checking the access to each questionnaire, nil to allow every questionnaire
+		maxBodySize int64                      // Maximum size of the request bodies and session messages in bytes, 0 or less for no limit
+		origins     []string                   // Origins allowed to open sessions besides the origin of the server (see WithAllowedOrigins)
+	}
+)
+
+// DefaultMaxBodySize is the maximum size of the request bodies, in bytes, unless set with WithMaxBodySize.
+const DefaultMaxBodySize = 1 << 20
+
+// newConfig applies the options to the default settings.
+func newConfig(opts []Option) *config {
+	c := &config{now: time.Now, maxBodySize: DefaultMaxBodySize}
+	c.limitKey = c.sessionKey
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithSessionStore persists the WebSocket sessions in the store, so that clients can resume them after
+// a disconnection, a server restart or on another replica, by connecting with the session query parameter.
+// Sessions are deleted once the questionnaire is completed.
+func WithSessionStore(store SessionStore) Option {
+	return func(c *config) {
+		c.store = store
+	}
+}
+
+// WithRateLimiter limits the requests served per client with the limiter, to protect public-facing endpoints:
+// the requests it rejects are answered with 429 Too Many Requests.
+// Requests are counted by the key, which defaults when nil to the session being resumed, if it exists
+// in the session store (see WithSessionStore), or to the IP address of the client (see ClientKey).
+// Every message of a session counts as a request, under the key of the request opening the session,
+// and the messages rejected by the limiter are answered with an error message.
+//
+// Example usage:
+//
+//	handler := gdqhttp.Handler(registry, gdqhttp.WithRateLimiter(gdqhttp.NewMemoryRateLimiter(60, time.Minute), nil))
+func WithRateLimiter(limiter RateLimiter, key func(r *http.Request) string) Option {
+	return func(c *config) {
+		c.limiter = limiter
+		if key != nil {
+			c.limitKey = key
+		}
+	}
+}
+
+// WithMaxBodySize limits the size of the request bodies and session messages, in bytes, so that a client can't
+// exhaust the memory of the server with a huge body: larger bodies are answered with 413 Request Entity Too Large,
+// and larger session messages with an error message.
+// Bodies are limited to DefaultMaxBodySize unless set; a size of 0 or less disables the limit.
+func WithMaxBodySize(size int64) Option {
+	return func(c *config) {
+		c.maxBodySize = size
+	}
+}
+
+// WithAllowedOrigins allows the web pages of the origins (e.g. "https://survey.example.com") to open sessions,
+// besides the pages served from the origin of the server itself. Session handshakes from other origins
+// are answered with 403 Forbidden, so that other sites can't use the cookies of the respondents.
+//
+// Example usage:
+//
+//	handler := gdqhttp.Handler(registry, gdqhttp.WithAllowedOrigins("https://survey.example.com"))
+func WithAllowedOrigins(origins ...string) Option {
+	return func(c *config) {
+		c.origins = append(c.origins, origins...)
+	}
+}
+
+// WithIdentity identifies the respondent of every request with the function, before it is served:
+// requests it fails to identify are answered with 401 Unauthorized. The identity is then available
+// to the access check and to wrapping handlers with RespondentID.
+//
+// Example usage:
+//
+//	handler := gdqhttp.Handler(registry, gdqhttp.WithIdentity(func(r *http.Request) (string, error) {
+//	    claims, err := verifier.Verify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+//	    if err != nil {
+//	        return "", fmt.Errorf("invalid token: %w", err)
+//	    }
+//	    return claims.Subject, nil
+//	}))
+func WithIdentity(identify IdentityFunc) Option {
+	return func(c *config) {
+		c.identify = identify
+	}
+}
+
+// WithAccessCheck checks the access to the questionnaire of every request with the function, so that internal
+// questionnaires are only served to the allowed respondents: requests denied access are answered with 403 Forbidden,
+// and the questionnaires they can't access are left out of the list and the OpenAPI document.
+//
+// Example usage:
+//
+//	handler := gdqhttp.Handler(registry, gdqhttp.WithIdentity(identify), gdqhttp.WithAccessCheck(func(r *http.Request, id string) error {
+//	    if !acl.Allowed(gdqhttp.RespondentID(r.Context()), id) {
+//	        return errors.New("access denied")
+//	    }
+//	    return nil
+//	}))
+func WithAccessCheck(access AccessFunc) Option {
+	return func(c *config) {
+		c.access = access
+	}
+}
@@ -0,0 +1,117 @@
+package gdqhttp
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimiter decides whether the requests of a client can be served (see WithRateLimiter),
+	// for instance with a distributed limiter shared by every replica.
+	// Implementations must be safe for concurrent use.
+	RateLimiter interface {
+		// Allow reports whether a request with the key can be served, and counts it.
+		Allow(key string) bool
+	}
+
+	// RateLimiterFunc is a function used as a RateLimiter.
+	//
+	// Example usage:
+	//   limiter := gdqhttp.RateLimiterFunc(func(key string) bool {
+	//       return limiters.Get(key).Allow() // e.g. a golang.org/x/time/rate.Limiter per key
+	//   })
+	RateLimiterFunc func(key string) bool
+
+	// MemoryRateLimiter is a RateLimiter allowing a number of requests per key in every fixed window of time,
+	// counted in memory, for single-instance servers.
+	MemoryRateLimiter struct {
+		limit  int
+		window time.Duration
+		now    func() time.Time
+
+		mu        sync.Mutex
+		windows   map[string]*rateWindow
+		lastSweep time.Time
+	}
+
+	// rateWindow counts the requests of a key since the window started.
+	rateWindow struct {
+		start time.Time
+		count int
+	}
+)
+
+// Allow calls f(key).
+func (f RateLimiterFunc) Allow(key string) bool {
+	return f(key)
+}
+
+// NewMemoryRateLimiter creates a MemoryRateLimiter allowing limit requests per key in every window,
+// e.g. 60 requests per minute.
+func NewMemoryRateLimiter(limit int, window time.Duration) *MemoryRateLimiter {
+	return &MemoryRateLimiter{limit: limit, window: window, now: time.Now, windows: map[string]*rateWindow{}}
+}
+
+// Allow reports whether the key has made fewer than limit requests in its current window, and counts the request.
+func (l *MemoryRateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// The windows of the keys that stopped making requests are removed once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// ClientKey keys the requests by the IP address of the client, e.g. "client:192.0.2.1" (see WithRateLimiter).
+// Behind a reverse proxy, the address is the proxy's: pass a key reading the forwarded address instead.
+func ClientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "client:" + host
+}
+
+// sessionKey is the default key of the rate limiter: the session query parameter when the request resumes
+// a session saved in the session store, e.g. "session:abc", and ClientKey otherwise.
+// Unknown sessions are keyed by client, so that clients can't get around the limits by sending random session IDs.
+func (c *config) sessionKey(r *http.Request) string {
+	if id := r.URL.Query().Get("session"); id != "" && c.store != nil {
+		if _, err := c.store.Get(r.Context(), id); err == nil {
+			return "session:" + id
+		}
+	}
+	return ClientKey(r)
+}
+
+// rateLimited serves the requests allowed by the rate limiter with the handler,
+// and answers the others with 429 Too Many Requests.
+func rateLimited(handler http.Handler, c *config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.limiter.Allow(c.limitKey(r)) {
+			writeJSON(w, http.StatusTooManyRequests, ErrorResponse{Error: "too many requests"})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
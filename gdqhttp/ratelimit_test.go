@@ -0,0 +1,81 @@
+package gdqhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rate Limiting", func() {
+	var q gdq.Questionnaire
+
+	BeforeEach(func() {
+		var err error
+		q, err = gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]`))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	serve := func(handler http.Handler, remoteAddr, path string) int {
+		request := httptest.NewRequest(http.MethodPost, path, nil)
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	It("should reject the requests of a client over the limit", func() {
+		handler := gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithRateLimiter(gdqhttp.NewMemoryRateLimiter(2, time.Hour), nil))
+
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.1:5678", "/questionnaires/pricing/next")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next")).To(Equal(http.StatusTooManyRequests))
+
+		Expect(serve(handler, "192.0.2.2:1234", "/questionnaires/pricing/next")).To(Equal(http.StatusOK))
+	})
+
+	It("should count the requests by the key", func() {
+		limiter := gdqhttp.NewMemoryRateLimiter(1, time.Hour)
+		handler := gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithRateLimiter(limiter, func(r *http.Request) string {
+			return r.URL.Query().Get("tenant")
+		}))
+
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?tenant=acme")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?tenant=globex")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.2:1234", "/questionnaires/pricing/next?tenant=acme")).To(Equal(http.StatusTooManyRequests))
+	})
+
+	It("should key requests by client address", func() {
+		request := httptest.NewRequest(http.MethodGet, "/questionnaires/pricing/session?session=abc", nil)
+		request.RemoteAddr = "[2001:db8::1]:443"
+		Expect(gdqhttp.ClientKey(request)).To(Equal("client:2001:db8::1"))
+	})
+
+	It("should only key requests by session when the session exists", func() {
+		store := gdqhttp.NewMemoryStore()
+		Expect(store.Save(context.Background(), gdqhttp.Session{ID: "saved", QuestionnaireID: "pricing"})).To(Succeed())
+		handler := gdqhttp.Handler(gdqhttp.Map{"pricing": q},
+			gdqhttp.WithSessionStore(store), gdqhttp.WithRateLimiter(gdqhttp.NewMemoryRateLimiter(1, time.Hour), nil))
+
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?session=random1")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?session=random2")).To(Equal(http.StatusTooManyRequests))
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?session=saved")).To(Equal(http.StatusOK))
+		Expect(serve(handler, "192.0.2.1:1234", "/questionnaires/pricing/next?session=saved")).To(Equal(http.StatusTooManyRequests))
+	})
+
+	It("should allow requests again in the next window", func() {
+		limiter := gdqhttp.NewMemoryRateLimiter(1, 10*time.Millisecond)
+		Expect(limiter.Allow("client")).To(BeTrue())
+		Expect(limiter.Allow("client")).To(BeFalse())
+		Eventually(func() bool { return limiter.Allow("client") }).WithPolling(5 * time.Millisecond).Should(BeTrue())
+	})
+})
@@ -0,0 +1,213 @@
+package gdqhttp
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"golang.org/x/net/websocket"
+)
+
+// errForeignSession is returned when resuming a session started by another respondent.
+var errForeignSession = errors.New("session belongs to another respondent")
+
+// SessionMessage is a message sent by the server over a session WebSocket (see sessionHandler).
+// Exactly one of Response and Error is set.
+type SessionMessage struct {
+	SessionID string         `json:"session_id,omitempty"` // ID to resume the session with (only with WithSessionStore)
+	Response  *gdq.Response  `json:"response,omitempty"`   // The next questions given the answers of the session
+	Error     *ErrorResponse `json:"error,omitempty"`      // Why the last message of the client was rejected
+}
+
+// sessionHandler upgrades the request to a WebSocket streaming the questionnaire of the request path.
+//
+// The server keeps the answers of the session: clients send NextRequest messages with their new answers only,
+// and receive a SessionMessage with the next questions after each of them, starting as soon as they connect.
+// Rejected messages get a SessionMessage with the error and leave the session answers unchanged.
+// The connection is closed once the questionnaire is completed and its optional questions left are answered or skipped.
+//
+// Messages larger than the maximum body size (see WithMaxBodySize) are rejected, and connections are only
+// accepted from the origin of the server or the allowed origins (see WithAllowedOrigins).
+//
+// The locale can be set when connecting with the locale query parameter, and changed by any message.
+// With a session store, the session query parameter resumes a saved session; a new session is started
+// when it is unknown. With WithIdentity, sessions can only be resumed by the respondent who started them:
+// resuming the session of another respondent is answered with 403 Forbidden.
+func sessionHandler(registry Registry, c *config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, ok := lookup(w, r, registry, c)
+		if !ok {
+			return
+		}
+
+		s := &session{config: c, q: q, locale: r.URL.Query().Get("locale")}
+		s.state = Session{QuestionnaireID: r.PathValue("id"), RespondentID: RespondentID(r.Context()), Answers: map[string]gdq.Answer{}}
+		if c.store != nil {
+			if err := s.resume(r, r.URL.Query().Get("session")); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, errForeignSession) {
+					status = http.StatusForbidden
+				}
+				writeJSON(w, status, ErrorResponse{Error: err.Error()})
+				return
+			}
+		}
+
+		websocket.Server{Handshake: c.checkOrigin, Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			if c.maxBodySize > 0 {
+				conn.MaxPayloadBytes = int(c.maxBodySize)
+			}
+			s.serve(conn)
+		}}.ServeHTTP(w, r)
+	}
+}
+
+// checkOrigin accepts the WebSocket handshakes from the origin of the server, from the allowed origins
+// (see WithAllowedOrigins) and without Origin header, which browsers always send: this prevents other sites
+// from opening sessions with the cookies of the respondent (cross-site WebSocket hijacking).
+// Rejected handshakes are answered with 403 Forbidden.
+func (c *config) checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	config.Origin = origin
+	if origin == nil || origin.Host == r.Host || slices.Contains(c.origins, origin.Scheme+"://"+origin.Host) {
+		return nil
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+// session is a WebSocket session in progress.
+type session struct {
+	*config
+	q      gdq.Questionnaire
+	locale string
+	state  Session
+}
+
+// resume loads the saved session with the ID, or starts a new session when it is unknown
+// or belongs to another questionnaire. It fails with errForeignSession when the session
+// was started by another respondent.
+func (s *session) resume(r *http.Request, id string) error {
+	if id != "" {
+		saved, err := s.store.Get(r.Context(), id)
+		switch {
+		case err == nil && saved.QuestionnaireID == s.state.QuestionnaireID && saved.RespondentID != s.state.RespondentID:
+			return errForeignSession
+		case err == nil && saved.QuestionnaireID == s.state.QuestionnaireID:
+			if saved.Answers == nil {
+				saved.Answers = map[string]gdq.Answer{}
+			}
+			s.state = saved
+			return nil
+		case err != nil && !errors.Is(err, ErrSessionNotFound):
+			return err
+		}
+	}
+	s.state.ID = rand.Text()
+	return nil
+}
+
+// serve answers the messages of the connection until the session is over
+// or the connection is closed. With a rate limiter, every message counts as a request,
+// under the key of the upgrade request: the messages it rejects are answered with an error.
+func (s *session) serve(conn *websocket.Conn) {
+	var key string
+	if s.limiter != nil {
+		key = s.limitKey(conn.Request())
+	}
+	request := NextRequest{}
+	for {
+		if request.Locale != "" {
+			s.locale = request.Locale
+		}
+		message, completed := s.next(conn.Request(), request.Answers, request.Metadata)
+		if websocket.JSON.Send(conn, message) != nil || completed {
+			return
+		}
+
+		request = NextRequest{}
+		for {
+			err := websocket.JSON.Receive(conn, &request)
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			var body ErrorResponse
+			switch {
+			case errors.Is(err, websocket.ErrFrameTooLarge):
+				// The oversized message is discarded by the next Receive
+				body.Error = fmt.Sprintf("message exceeds the maximum of %d bytes", s.maxBodySize)
+			case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+				body.Error = "invalid message: " + err.Error()
+			case err != nil:
+				return
+			}
+			// Rejected messages count as well, so that they can't be sent without limit either
+			if s.limiter != nil && !s.limiter.Allow(key) {
+				body.Error = "too many requests"
+			} else if err == nil {
+				break
+			}
+			if websocket.JSON.Send(conn, SessionMessage{SessionID: s.state.ID, Error: &body}) != nil {
+				return
+			}
+			request = NextRequest{}
+		}
+	}
+}
+
+// next adds the new answers and their metadata to the session and returns the message with the next questions,
+// and whether the session is over: the questionnaire is completed and no optional question is left.
+// The session is saved in the store, if any.
+func (s *session) next(r *http.Request, answers map[string]gdq.Answer, metadata map[string]gdq.AnswerMetadata) (SessionMessage, bool) {
+	merged := maps.Clone(s.state.Answers)
+	maps.Copy(merged, answers)
+	mergedMetadata := maps.Clone(s.state.Metadata)
+	if mergedMetadata == nil && len(metadata) > 0 {
+		mergedMetadata = make(map[string]gdq.AnswerMetadata, len(metadata))
+	}
+	maps.Copy(mergedMetadata, metadata)
+
+	opts := []gdq.NextOption{gdq.WithChecksum(), gdq.WithAllAnswerErrors(), gdq.WithAnswerMetadata(mergedMetadata)}
+	if s.locale != "" {
+		opts = append(opts, gdq.WithLocale(s.locale))
+	}
+	response, err := s.q.NextAnswers(merged, opts...)
+	// The session is only saved when created, changed or over
+	changed := len(answers) > 0 || len(metadata) > 0 || s.state.UpdatedAt.IsZero()
+	over := err == nil && response.Completed && len(response.Questions) == 0
+	if err == nil && s.store != nil && (changed || over) {
+		err = s.save(r, merged, mergedMetadata, over)
+	}
+	if err != nil {
+		_, body := errorResponse(err, s.locale)
+		return SessionMessage{SessionID: s.state.ID, Error: &body}, false
+	}
+
+	s.state.Answers = merged
+	s.state.Metadata = mergedMetadata
+	return SessionMessage{SessionID: s.state.ID, Response: response}, over
+}
+
+// save saves the session with the answers, or deletes it once the session is over.
+func (s *session) save(r *http.Request, answers map[string]gdq.Answer, metadata map[string]gdq.AnswerMetadata, over bool) error {
+	if over {
+		return s.store.Delete(r.Context(), s.state.ID)
+	}
+
+	state := s.state
+	state.Answers = answers
+	state.Metadata = metadata
+	state.UpdatedAt = s.now()
+	if err := s.store.Save(r.Context(), state); err != nil {
+		return err
+	}
+	s.state.UpdatedAt = state.UpdatedAt
+	return nil
+}
@@ -0,0 +1,286 @@
+package gdqhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/websocket"
+)
+
+var _ = Describe("Session", func() {
+	var server *httptest.Server
+	var conn *websocket.Conn
+
+	BeforeEach(func() {
+		q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats do you need?"
+    answers: ["1-10", "More"]
+    depends_on: ["plan"]
+    condition: 'answers["plan"] == 2'
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`))
+		Expect(err).ToNot(HaveOccurred())
+		server = httptest.NewServer(gdqhttp.Handler(gdqhttp.Map{"pricing": q}))
+		DeferCleanup(server.Close)
+
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/questionnaires/pricing/session"
+		conn, err = websocket.Dial(url, "", server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(conn.Close)
+	})
+
+	receive := func() gdqhttp.SessionMessage {
+		var message gdqhttp.SessionMessage
+		Expect(websocket.JSON.Receive(conn, &message)).To(Succeed())
+		return message
+	}
+
+	It("should stream the questions as answers are received", func() {
+		message := receive()
+		Expect(message.Error).To(BeNil())
+		Expect(message.Response.Questions[0].Id).To(Equal("plan"))
+
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+		message = receive()
+		Expect(message.Response.Questions[0].Id).To(Equal("seats"))
+
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"seats": gdq.Choice(1)}})).To(Succeed())
+		message = receive()
+		Expect(message.Response.Completed).To(BeTrue())
+		Expect(message.Response.ClosingRemarks).To(HaveLen(1))
+
+		var closed gdqhttp.SessionMessage
+		Expect(websocket.JSON.Receive(conn, &closed)).ToNot(Succeed())
+	})
+
+	It("should return the metadata of every answer once completed", func() {
+		receive()
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{
+			Answers:  map[string]gdq.Answer{"plan": gdq.Choice(2)},
+			Metadata: map[string]gdq.AnswerMetadata{"plan": {TimeSpentMs: 1500}},
+		})).To(Succeed())
+		Expect(receive().Response.Metadata).To(BeNil())
+
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{
+			Answers:  map[string]gdq.Answer{"seats": gdq.Choice(1)},
+			Metadata: map[string]gdq.AnswerMetadata{"seats": {TimeSpentMs: 800}},
+		})).To(Succeed())
+		message := receive()
+		Expect(message.Response.Completed).To(BeTrue())
+		Expect(message.Response.Metadata).To(Equal(map[string]gdq.AnswerMetadata{
+			"plan":  {TimeSpentMs: 1500},
+			"seats": {TimeSpentMs: 800},
+		}))
+	})
+
+	It("should reject invalid answers and keep the session answers", func() {
+		receive()
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+		receive()
+
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"seats": gdq.Choice(3)}})).To(Succeed())
+		message := receive()
+		Expect(message.Response).To(BeNil())
+		Expect(message.Error.Errors[0].Type).To(Equal(gdq.InvalidAnswerRangeErrType))
+
+		Expect(websocket.Message.Send(conn, `{"answers": [`)).To(Succeed())
+		message = receive()
+		Expect(message.Error.Error).To(HavePrefix("invalid message"))
+
+		Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{})).To(Succeed())
+		message = receive()
+		Expect(message.Response.Questions[0].Id).To(Equal("seats"))
+	})
+
+	Context("with limits", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats do you need?"
+    answers: ["1-10", "More"]
+    depends_on: ["plan"]
+    condition: 'answers["plan"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		// dial connects to a server serving the handler, from the origin of the server when origin is empty
+		dial := func(handler http.Handler, origin string) (*websocket.Conn, error) {
+			server = httptest.NewServer(handler)
+			DeferCleanup(server.Close)
+			if origin == "" {
+				origin = server.URL
+			}
+			conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/questionnaires/pricing/session", "", origin)
+			if err == nil {
+				DeferCleanup(func() { _ = conn.Close() })
+			}
+			return conn, err
+		}
+
+		It("should reject messages larger than the maximum body size", func() {
+			var err error
+			conn, err = dial(gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithMaxBodySize(64)), "")
+			Expect(err).ToNot(HaveOccurred())
+			receive()
+
+			Expect(websocket.Message.Send(conn, `{"answers": {"plan": 2}, "locale": "`+strings.Repeat("x", 64)+`"}`)).To(Succeed())
+			message := receive()
+			Expect(message.Response).To(BeNil())
+			Expect(message.Error.Error).To(Equal("message exceeds the maximum of 64 bytes"))
+
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+			Expect(receive().Response.Questions[0].Id).To(Equal("seats"))
+		})
+
+		It("should count every message against the rate limit", func() {
+			limiter := gdqhttp.NewMemoryRateLimiter(3, time.Hour)
+			var err error
+			conn, err = dial(gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithRateLimiter(limiter, nil)), "")
+			Expect(err).ToNot(HaveOccurred())
+			receive()
+
+			Expect(websocket.Message.Send(conn, `{"answers": [`)).To(Succeed())
+			Expect(receive().Error.Error).To(HavePrefix("invalid message"))
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+			Expect(receive().Response.Questions[0].Id).To(Equal("seats"))
+
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"seats": gdq.Choice(1)}})).To(Succeed())
+			message := receive()
+			Expect(message.Response).To(BeNil())
+			Expect(message.Error.Error).To(Equal("too many requests"))
+		})
+
+		It("should only accept connections from the origin of the server and the allowed origins", func() {
+			handler := gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithAllowedOrigins("https://survey.example.com"))
+
+			_, err := dial(handler, "https://evil.example.com")
+			Expect(err).To(MatchError(ContainSubstring("bad status")))
+
+			_, err = dial(handler, "https://survey.example.com")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = dial(handler, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("with a session store", func() {
+		var store *gdqhttp.MemoryStore
+
+		BeforeEach(func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats do you need?"
+    answers: ["1-10", "More"]
+    depends_on: ["plan"]
+    condition: 'answers["plan"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+			store = gdqhttp.NewMemoryStore()
+			server = httptest.NewServer(gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithSessionStore(store)))
+			DeferCleanup(server.Close)
+		})
+
+		connect := func(sessionID string) *websocket.Conn {
+			url := "ws" + strings.TrimPrefix(server.URL, "http") + "/questionnaires/pricing/session?session=" + sessionID
+			conn, err := websocket.Dial(url, "", server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(func() { _ = conn.Close() })
+			return conn
+		}
+
+		It("should resume saved sessions", func() {
+			conn = connect("")
+			message := receive()
+			sessionID := message.SessionID
+			Expect(sessionID).ToNot(BeEmpty())
+
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+			receive()
+			saved, err := store.Get(context.Background(), sessionID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(saved.QuestionnaireID).To(Equal("pricing"))
+			Expect(saved.Answers).To(Equal(map[string]gdq.Answer{"plan": gdq.Choice(2)}))
+			conn.Close()
+
+			conn = connect(sessionID)
+			message = receive()
+			Expect(message.SessionID).To(Equal(sessionID))
+			Expect(message.Response.Questions[0].Id).To(Equal("seats"))
+
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"seats": gdq.Choice(1)}})).To(Succeed())
+			Expect(receive().Response.Completed).To(BeTrue())
+			_, err = store.Get(context.Background(), sessionID)
+			Expect(err).To(MatchError(gdqhttp.ErrSessionNotFound))
+		})
+
+		It("should start a new session when the session is unknown", func() {
+			conn = connect("unknown")
+			message := receive()
+			Expect(message.SessionID).ToNot(Equal("unknown"))
+			Expect(message.Response.Questions[0].Id).To(Equal("plan"))
+		})
+
+		It("should only resume sessions started by the same respondent", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats do you need?"
+    answers: ["1-10", "More"]`))
+			Expect(err).ToNot(HaveOccurred())
+			identify := func(r *http.Request) (string, error) { return r.Header.Get("X-User"), nil }
+			server = httptest.NewServer(gdqhttp.Handler(gdqhttp.Map{"pricing": q}, gdqhttp.WithSessionStore(store), gdqhttp.WithIdentity(identify)))
+			DeferCleanup(server.Close)
+			dial := func(user, sessionID string) (*websocket.Conn, error) {
+				config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/questionnaires/pricing/session?session="+sessionID, server.URL)
+				Expect(err).ToNot(HaveOccurred())
+				config.Header.Set("X-User", user)
+				return websocket.DialConfig(config)
+			}
+
+			conn, err = dial("alice", "")
+			Expect(err).ToNot(HaveOccurred())
+			sessionID := receive().SessionID
+			Expect(websocket.JSON.Send(conn, gdqhttp.NextRequest{Answers: map[string]gdq.Answer{"plan": gdq.Choice(2)}})).To(Succeed())
+			receive()
+			conn.Close()
+			saved, err := store.Get(context.Background(), sessionID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(saved.RespondentID).To(Equal("alice"))
+
+			_, err = dial("bob", sessionID)
+			Expect(err).To(HaveOccurred())
+
+			conn, err = dial("alice", sessionID)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(func() { _ = conn.Close() })
+			Expect(receive().SessionID).To(Equal(sessionID))
+		})
+	})
+})
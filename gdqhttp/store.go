@@ -0,0 +1,84 @@
+package gdqhttp
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// ErrSessionNotFound is returned by SessionStore.Get when no session has the ID.
+var ErrSessionNotFound = errors.New("session not found")
+
+type (
+	// Session is the state of a questionnaire session kept on the server (see WithSessionStore).
+	Session struct {
+		ID              string                        `json:"id"`                      // Identifier of the session, chosen by the server
+		QuestionnaireID string                        `json:"questionnaire_id"`        // ID of the questionnaire in the registry
+		RespondentID    string                        `json:"respondent_id,omitempty"` // Identity of the respondent who started the session (see WithIdentity), empty when anonymous
+		Answers         map[string]gdq.Answer         `json:"answers"`                 // Answers given so far
+		Metadata        map[string]gdq.AnswerMetadata `json:"metadata,omitempty"`      // Metadata of the answers given so far
+		UpdatedAt       time.Time                     `json:"updated_at"`              // When the answers were last saved
+	}
+
+	// SessionStore persists sessions, so that they survive server restarts and can be resumed by any replica.
+	// Implementations must be safe for concurrent use.
+	SessionStore interface {
+		// Get returns the session with the ID, or ErrSessionNotFound.
+		Get(ctx context.Context, id string) (Session, error)
+
+		// Save creates or replaces the session.
+		Save(ctx context.Context, session Session) error
+
+		// Delete removes the session. Deleting an unknown session is not an error.
+		Delete(ctx context.Context, id string) error
+	}
+
+	// MemoryStore is a SessionStore keeping sessions in memory, for single-instance servers and tests.
+	MemoryStore struct {
+		mu       sync.RWMutex
+		sessions map[string]Session
+	}
+)
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]Session{}}
+}
+
+// Get returns the session with the ID, or ErrSessionNotFound.
+func (s *MemoryStore) Get(_ context.Context, id string) (Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+	session.Answers = maps.Clone(session.Answers)
+	session.Metadata = maps.Clone(session.Metadata)
+	return session, nil
+}
+
+// Save creates or replaces the session.
+func (s *MemoryStore) Save(_ context.Context, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.Answers = maps.Clone(session.Answers)
+	session.Metadata = maps.Clone(session.Metadata)
+	s.sessions[session.ID] = session
+	return nil
+}
+
+// Delete removes the session.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
@@ -0,0 +1,88 @@
+package gdqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// RedisClient is the subset of a Redis client used by RedisStore.
+	// A missing key must be reported by Get with found set to false rather than an error.
+	//
+	// With github.com/redis/go-redis, it is implemented by:
+	//
+	//	type goRedis struct{ *redis.Client }
+	//
+	//	func (c goRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	//	    value, err := c.Client.Get(ctx, key).Result()
+	//	    if errors.Is(err, redis.Nil) {
+	//	        return "", false, nil
+	//	    }
+	//	    return value, err == nil, err
+	//	}
+	//
+	//	func (c goRedis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	//	    return c.Client.Set(ctx, key, value, ttl).Err()
+	//	}
+	//
+	//	func (c goRedis) Del(ctx context.Context, key string) error {
+	//	    return c.Client.Del(ctx, key).Err()
+	//	}
+	RedisClient interface {
+		Get(ctx context.Context, key string) (value string, found bool, err error)
+		Set(ctx context.Context, key, value string, ttl time.Duration) error
+		Del(ctx context.Context, key string) error
+	}
+
+	// RedisStore is a SessionStore keeping sessions in Redis, encoded as JSON.
+	RedisStore struct {
+		client RedisClient
+		prefix string
+		ttl    time.Duration
+	}
+)
+
+// NewRedisStore creates a RedisStore saving each session under the prefix followed by its ID.
+// Sessions expire after the TTL without being saved, 0 to keep them until they are deleted.
+func NewRedisStore(client RedisClient, prefix string, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Get returns the session with the ID, or ErrSessionNotFound.
+func (s *RedisStore) Get(ctx context.Context, id string) (Session, error) {
+	value, found, err := s.client.Get(ctx, s.prefix+id)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to get session '%s': %w", id, err)
+	}
+	if !found {
+		return Session{}, ErrSessionNotFound
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return Session{}, fmt.Errorf("failed to decode session '%s': %w", id, err)
+	}
+	return session, nil
+}
+
+// Save creates or replaces the session, resetting its TTL.
+func (s *RedisStore) Save(ctx context.Context, session Session) error {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session '%s': %w", session.ID, err)
+	}
+	if err := s.client.Set(ctx, s.prefix+session.ID, string(value), s.ttl); err != nil {
+		return fmt.Errorf("failed to save session '%s': %w", session.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the session.
+func (s *RedisStore) Delete(ctx context.Context, id string) error {
+	if err := s.client.Del(ctx, s.prefix+id); err != nil {
+		return fmt.Errorf("failed to delete session '%s': %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package gdqhttp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// SQLStore is a SessionStore keeping sessions in a SQL table through database/sql.
+	// The table must have the following columns (adapt the types to the database):
+	//
+	//	CREATE TABLE sessions (
+	//	    id               VARCHAR(255) PRIMARY KEY,
+	//	    questionnaire_id VARCHAR(255) NOT NULL,
+	//	    respondent_id    VARCHAR(255) NOT NULL,
+	//	    answers          TEXT NOT NULL,
+	//	    metadata         TEXT NOT NULL,
+	//	    updated_at       TIMESTAMP NOT NULL
+	//	);
+	//
+	// Answers and their metadata are stored encoded as JSON.
+	SQLStore struct {
+		db          *sql.DB
+		table       string
+		placeholder Placeholder
+		upsert      func(columns []string) string
+	}
+
+	// Dialect is the SQL syntax of a database that differs between databases (see NewSQLStore).
+	Dialect struct {
+		Placeholder Placeholder                   // Bind parameters of the queries
+		Upsert      func(columns []string) string // Clause added to an INSERT to update the columns of the row having the same id instead
+	}
+
+	// Placeholder returns the bind parameter of the n-th argument of a query (1-indexed),
+	// as expected by the database driver.
+	Placeholder func(n int) string
+)
+
+var (
+	// QuestionPlaceholder is the Placeholder of MySQL and SQLite: "?".
+	QuestionPlaceholder Placeholder = func(int) string { return "?" }
+
+	// DollarPlaceholder is the Placeholder of PostgreSQL: "$1", "$2"...
+	DollarPlaceholder Placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+
+	// PostgreSQLDialect is the Dialect of PostgreSQL: "$1" placeholders and "ON CONFLICT (id) DO UPDATE" upserts.
+	PostgreSQLDialect = Dialect{Placeholder: DollarPlaceholder, Upsert: onConflictUpsert}
+
+	// SQLiteDialect is the Dialect of SQLite (3.24 or later): "?" placeholders and "ON CONFLICT (id) DO UPDATE" upserts.
+	SQLiteDialect = Dialect{Placeholder: QuestionPlaceholder, Upsert: onConflictUpsert}
+
+	// MySQLDialect is the Dialect of MySQL and MariaDB: "?" placeholders and "ON DUPLICATE KEY UPDATE" upserts.
+	MySQLDialect = Dialect{Placeholder: QuestionPlaceholder, Upsert: onDuplicateKeyUpsert}
+)
+
+// NewSQLStore creates a SQLStore saving sessions in the table, with the SQL dialect of the database.
+// The table name is inserted as is in the queries and must not come from user input.
+func NewSQLStore(db *sql.DB, table string, dialect Dialect) *SQLStore {
+	return &SQLStore{db: db, table: table, placeholder: dialect.Placeholder, upsert: dialect.Upsert}
+}
+
+// onConflictUpsert is the upsert clause of PostgreSQL and SQLite.
+func onConflictUpsert(columns []string) string {
+	updates := make([]string, len(columns))
+	for i, column := range columns {
+		updates[i] = column + " = excluded." + column
+	}
+	return "ON CONFLICT (id) DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// onDuplicateKeyUpsert is the upsert clause of MySQL and MariaDB.
+func onDuplicateKeyUpsert(columns []string) string {
+	updates := make([]string, len(columns))
+	for i, column := range columns {
+		updates[i] = column + " = VALUES(" + column + ")"
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
+
+// Get returns the session with the ID, or ErrSessionNotFound.
+func (s *SQLStore) Get(ctx context.Context, id string) (Session, error) {
+	query := fmt.Sprintf("SELECT questionnaire_id, respondent_id, answers, metadata, updated_at FROM %s WHERE id = %s", s.table, s.placeholder(1))
+
+	session := Session{ID: id}
+	var answers, metadata string
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&session.QuestionnaireID, &session.RespondentID, &answers, &metadata, &session.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to get session '%s': %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(answers), &session.Answers); err != nil {
+		return Session{}, fmt.Errorf("failed to decode session '%s': %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(metadata), &session.Metadata); err != nil {
+		return Session{}, fmt.Errorf("failed to decode session '%s': %w", id, err)
+	}
+	return session, nil
+}
+
+// Save creates or replaces the session, with a single upsert query so that replicas saving the same session
+// concurrently don't conflict.
+func (s *SQLStore) Save(ctx context.Context, session Session) error {
+	answers, err := json.Marshal(session.Answers)
+	if err != nil {
+		return fmt.Errorf("failed to encode session '%s': %w", session.ID, err)
+	}
+	metadata, err := json.Marshal(session.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode session '%s': %w", session.ID, err)
+	}
+
+	columns := []string{"questionnaire_id", "respondent_id", "answers", "metadata", "updated_at"}
+	query := fmt.Sprintf("INSERT INTO %s (id, %s) VALUES (%s, %s, %s, %s, %s, %s) %s",
+		s.table, strings.Join(columns, ", "),
+		s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), s.placeholder(6),
+		s.upsert(columns))
+	if _, err := s.db.ExecContext(ctx, query,
+		session.ID, session.QuestionnaireID, session.RespondentID, string(answers), string(metadata), session.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save session '%s': %w", session.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the session.
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = %s", s.table, s.placeholder(1))
+	if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete session '%s': %w", id, err)
+	}
+	return nil
+}
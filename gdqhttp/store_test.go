@@ -0,0 +1,234 @@
+package gdqhttp_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqhttp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Stores", func() {
+	session := gdqhttp.Session{
+		ID:              "abc",
+		QuestionnaireID: "pricing",
+		RespondentID:    "alice",
+		Answers:         map[string]gdq.Answer{"plan": gdq.Choice(2)},
+		Metadata:        map[string]gdq.AnswerMetadata{"plan": {TimeSpentMs: 1200, Client: map[string]string{"platform": "web"}}},
+		UpdatedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	behavesLikeAStore := func(newStore func() gdqhttp.SessionStore) {
+		It("should save, get and delete sessions", func() {
+			ctx := context.Background()
+			store := newStore()
+
+			_, err := store.Get(ctx, "abc")
+			Expect(err).To(MatchError(gdqhttp.ErrSessionNotFound))
+
+			Expect(store.Save(ctx, session)).To(Succeed())
+			saved, err := store.Get(ctx, "abc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(saved).To(Equal(session))
+
+			updated := session
+			updated.Answers = map[string]gdq.Answer{"plan": gdq.Choice(2), "seats": gdq.Choice(1)}
+			Expect(store.Save(ctx, updated)).To(Succeed())
+			saved, err = store.Get(ctx, "abc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(saved.Answers).To(Equal(updated.Answers))
+
+			Expect(store.Delete(ctx, "abc")).To(Succeed())
+			_, err = store.Get(ctx, "abc")
+			Expect(err).To(MatchError(gdqhttp.ErrSessionNotFound))
+			Expect(store.Delete(ctx, "abc")).To(Succeed())
+		})
+	}
+
+	Describe("MemoryStore", func() {
+		behavesLikeAStore(func() gdqhttp.SessionStore { return gdqhttp.NewMemoryStore() })
+
+		It("should not share the maps of the saved sessions", func() {
+			ctx := context.Background()
+			store := gdqhttp.NewMemoryStore()
+			saved := session
+			saved.Answers = map[string]gdq.Answer{"plan": gdq.Choice(2)}
+			saved.Metadata = map[string]gdq.AnswerMetadata{"plan": {TimeSpentMs: 1200}}
+			Expect(store.Save(ctx, saved)).To(Succeed())
+
+			saved.Answers["seats"] = gdq.Choice(1)
+			saved.Metadata["seats"] = gdq.AnswerMetadata{TimeSpentMs: 800}
+			stored, err := store.Get(ctx, "abc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Answers).To(HaveLen(1))
+			Expect(stored.Metadata).To(HaveLen(1))
+		})
+	})
+
+	Describe("RedisStore", func() {
+		var client *fakeRedis
+
+		behavesLikeAStore(func() gdqhttp.SessionStore {
+			client = &fakeRedis{values: map[string]string{}}
+			return gdqhttp.NewRedisStore(client, "gdq:", time.Hour)
+		})
+
+		It("should save sessions under the prefix with the TTL", func() {
+			client = &fakeRedis{values: map[string]string{}}
+			store := gdqhttp.NewRedisStore(client, "gdq:", time.Hour)
+			Expect(store.Save(context.Background(), session)).To(Succeed())
+			Expect(client.values).To(HaveKey("gdq:abc"))
+			Expect(client.ttl).To(Equal(time.Hour))
+		})
+	})
+
+	Describe("SQLStore", func() {
+		var database *fakeDatabase
+
+		behavesLikeAStore(func() gdqhttp.SessionStore {
+			database = &fakeDatabase{rows: map[string][]driver.Value{}}
+			return gdqhttp.NewSQLStore(sql.OpenDB(database), "sessions", gdqhttp.PostgreSQLDialect)
+		})
+
+		It("should use the placeholders of the driver", func() {
+			database = &fakeDatabase{rows: map[string][]driver.Value{}}
+			store := gdqhttp.NewSQLStore(sql.OpenDB(database), "sessions", gdqhttp.SQLiteDialect)
+			Expect(store.Delete(context.Background(), "abc")).To(Succeed())
+			Expect(database.queries).To(Equal([]string{"DELETE FROM sessions WHERE id = ?"}))
+		})
+
+		It("should save sessions with a single upsert", func() {
+			database = &fakeDatabase{rows: map[string][]driver.Value{}}
+			store := gdqhttp.NewSQLStore(sql.OpenDB(database), "sessions", gdqhttp.PostgreSQLDialect)
+			Expect(store.Save(context.Background(), session)).To(Succeed())
+			Expect(database.queries).To(Equal([]string{"INSERT INTO sessions (id, questionnaire_id, respondent_id, answers, metadata, updated_at) " +
+				"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (id) DO UPDATE SET questionnaire_id = excluded.questionnaire_id, " +
+				"respondent_id = excluded.respondent_id, answers = excluded.answers, metadata = excluded.metadata, updated_at = excluded.updated_at"}))
+		})
+
+		It("should upsert with the syntax of MySQL", func() {
+			database = &fakeDatabase{rows: map[string][]driver.Value{}}
+			store := gdqhttp.NewSQLStore(sql.OpenDB(database), "sessions", gdqhttp.MySQLDialect)
+			Expect(store.Save(context.Background(), session)).To(Succeed())
+			Expect(store.Save(context.Background(), session)).To(Succeed())
+			Expect(database.queries[1]).To(Equal("INSERT INTO sessions (id, questionnaire_id, respondent_id, answers, metadata, updated_at) " +
+				"VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE questionnaire_id = VALUES(questionnaire_id), " +
+				"respondent_id = VALUES(respondent_id), answers = VALUES(answers), metadata = VALUES(metadata), updated_at = VALUES(updated_at)"))
+		})
+	})
+})
+
+// fakeRedis is a gdqhttp.RedisClient backed by a map.
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttl    time.Duration
+}
+
+func (r *fakeRedis) Get(_ context.Context, key string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.values[key]
+	return value, ok, nil
+}
+
+func (r *fakeRedis) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key], r.ttl = value, ttl
+	return nil
+}
+
+func (r *fakeRedis) Del(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.values, key)
+	return nil
+}
+
+// fakeDatabase is a database/sql connector understanding the queries of gdqhttp.SQLStore,
+// keeping the rows of the sessions table in memory.
+type fakeDatabase struct {
+	mu      sync.Mutex
+	rows    map[string][]driver.Value // questionnaire_id, respondent_id, answers, metadata and updated_at, by id
+	queries []string
+}
+
+func (d *fakeDatabase) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDatabase) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ database *fakeDatabase }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.database, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	database *fakeDatabase
+	query    string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.database
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		// Inserts are upserts: the row having the same id is replaced
+		d.rows[args[0].(string)] = args[1:]
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "DELETE"):
+		delete(d.rows, args[0].(string))
+		return driver.RowsAffected(1), nil
+	default:
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.database
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	row, ok := d.rows[args[0].(string)]
+	if !ok {
+		return &fakeRows{}, nil
+	}
+	return &fakeRows{rows: [][]driver.Value{row}}, nil
+}
+
+type fakeRows struct{ rows [][]driver.Value }
+
+func (r *fakeRows) Columns() []string {
+	return []string{"questionnaire_id", "respondent_id", "answers", "metadata", "updated_at"}
+}
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,51 @@
+// Package gdqmsgpack encodes the values of go-dynamic-questionnaire (Response, Question, ValidationError...)
+// as MessagePack, a compact binary alternative to JSON suited to low-bandwidth clients.
+//
+// Values are encoded with the same field names as their JSON representation, and fields omitted
+// from the JSON representation when empty are omitted as well, so clients can use either format:
+//
+//	response, err := q.Next(answers)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	data, err := gdqmsgpack.Marshal(response)
+//	if err != nil {
+//	    return err
+//	}
+//	w.Header().Set("Content-Type", gdqmsgpack.ContentType)
+//	w.Write(data)
+package gdqmsgpack
+
+import (
+	"io"
+
+	"github.com/ugorji/go/codec"
+)
+
+// ContentType is the media type of MessagePack payloads, to set in the Content-Type header of HTTP responses.
+const ContentType = "application/msgpack"
+
+// handle configures the MessagePack encoding, with the msgpack spec extensions (str8, bin and timestamp types).
+// It is configured once and safe for concurrent use.
+var handle = &codec.MsgpackHandle{WriteExt: true}
+
+// Marshal returns the MessagePack encoding of v.
+func Marshal(v any) ([]byte, error) {
+	var data []byte
+	if err := codec.NewEncoderBytes(&data, handle).Encode(v); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Unmarshal decodes the MessagePack data into v, which must be a pointer.
+// Clients written in Go can decode responses with it, e.g. into a gdq.Response.
+func Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, handle).Decode(v)
+}
+
+// Encode writes the MessagePack encoding of v to w.
+func Encode(w io.Writer, v any) error {
+	return codec.NewEncoder(w, handle).Encode(v)
+}
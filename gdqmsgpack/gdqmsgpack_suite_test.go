@@ -0,0 +1,13 @@
+package gdqmsgpack_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGdqmsgpack(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gdqmsgpack Suite")
+}
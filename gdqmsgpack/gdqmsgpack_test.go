@@ -0,0 +1,71 @@
+package gdqmsgpack_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqmsgpack"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessagePack", func() {
+	config := []byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats?"
+    answers: ["1-10", "More"]
+    depends_on: ["plan"]
+    condition: 'answers["plan"] == 2'`)
+
+	It("should encode responses with the field names of their JSON representation", func() {
+		q, err := gdq.New(config)
+		Expect(err).ToNot(HaveOccurred())
+		response, err := q.Next(map[string]int{"plan": 2})
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := gdqmsgpack.Marshal(response)
+		Expect(err).ToNot(HaveOccurred())
+		var decoded map[string]interface{}
+		Expect(gdqmsgpack.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("completed", false))
+		Expect(decoded).To(HaveKey("questions"))
+		Expect(decoded).To(HaveKey("progress"))
+		Expect(decoded).ToNot(HaveKey("closing_remarks"))
+
+		encoded, err := json.Marshal(response)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(data)).To(BeNumerically("<", len(encoded)))
+	})
+
+	It("should decode responses", func() {
+		q, err := gdq.New(config)
+		Expect(err).ToNot(HaveOccurred())
+		response, err := q.Next(map[string]int{"plan": 2})
+		Expect(err).ToNot(HaveOccurred())
+
+		var buffer bytes.Buffer
+		Expect(gdqmsgpack.Encode(&buffer, response)).To(Succeed())
+		var decoded gdq.Response
+		Expect(gdqmsgpack.Unmarshal(buffer.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(*response))
+	})
+
+	It("should encode validation errors", func() {
+		q, err := gdq.New(config)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = q.Next(map[string]int{"plan": 3})
+
+		data, err := gdqmsgpack.Marshal(gdq.ValidationErrors(err)[0])
+		Expect(err).ToNot(HaveOccurred())
+		var decoded map[string]interface{}
+		Expect(gdqmsgpack.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("type", gdq.InvalidAnswerRangeErrType))
+		Expect(decoded).To(HaveKeyWithValue("message", "answer is out of range"))
+		Expect(decoded).To(HaveKey("context"))
+	})
+})
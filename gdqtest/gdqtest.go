@@ -0,0 +1,206 @@
+// Package gdqtest provides utilities to test questionnaires built with go-dynamic-questionnaire.
+//
+// It drives a questionnaire from the first question to completion with scripted or random
+// answers, records the resulting flow and offers assertions on it:
+//
+//	func TestDeveloperPath(t *testing.T) {
+//	    q, err := gdq.New("survey.yaml")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//
+//	    flow := gdqtest.MustRun(t, q, gdqtest.Scripted(map[string]int{"experience": 1, "language": 1}))
+//	    flow.AssertAsked(t, "experience", "language")
+//	    flow.AssertCompleted(t)
+//	    flow.AssertClosingRemarks(t, "gopher")
+//	}
+package gdqtest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+type (
+	// TestingT is the subset of testing.TB used by the assertions.
+	// It is satisfied by *testing.T, *testing.B and Ginkgo's GinkgoT().
+	TestingT interface {
+		Helper()
+		Errorf(format string, args ...interface{})
+		Fatalf(format string, args ...interface{})
+	}
+
+	// Strategy chooses the answer given to a question while running a questionnaire.
+	// It returns the 1-indexed answer expected by Questionnaire.Next, or gdq.SkipAnswer.
+	Strategy func(question gdq.Question) (int, error)
+
+	// Step is a single call to Questionnaire.Next made while running a questionnaire.
+	Step struct {
+		Answers  map[string]int `json:"answers"`  // Answers submitted to Next
+		Response *gdq.Response  `json:"response"` // Response returned by Next
+	}
+
+	// Flow is the recording of a questionnaire run, from the first question to completion.
+	Flow struct {
+		Steps   []Step         `json:"steps"`   // Every call to Next, in order
+		Answers map[string]int `json:"answers"` // Final answers
+	}
+)
+
+// maxSteps guards against questionnaires that never complete.
+const maxSteps = 10000
+
+// Run drives the questionnaire to completion, answering every question returned by Next
+// with the strategy, including the optional questions left once completed, and returns the recorded flow.
+// The options are passed to every call to Next.
+func Run(q gdq.Questionnaire, strategy Strategy, opts ...gdq.NextOption) (*Flow, error) {
+	flow := &Flow{Answers: map[string]int{}}
+
+	for len(flow.Steps) < maxSteps {
+		submitted := maps.Clone(flow.Answers)
+		response, err := q.Next(submitted, opts...)
+		if err != nil {
+			return flow, fmt.Errorf("step %d: %w", len(flow.Steps)+1, err)
+		}
+		flow.Steps = append(flow.Steps, Step{Answers: submitted, Response: response})
+		if len(response.Questions) == 0 {
+			return flow, nil
+		}
+
+		for _, question := range response.Questions {
+			answer, err := strategy(question)
+			if err != nil {
+				return flow, fmt.Errorf("step %d: question '%s': %w", len(flow.Steps), question.Id, err)
+			}
+			flow.Answers[question.Id] = answer
+		}
+	}
+
+	return flow, fmt.Errorf("questionnaire not completed after %d steps", maxSteps)
+}
+
+// MustRun is like Run but fails the test immediately when the run fails.
+func MustRun(t TestingT, q gdq.Questionnaire, strategy Strategy, opts ...gdq.NextOption) *Flow {
+	t.Helper()
+	flow, err := Run(q, strategy, opts...)
+	if err != nil {
+		t.Fatalf("failed to run questionnaire: %v", err)
+	}
+	return flow
+}
+
+// Scripted answers the questions with the given answers, by question ID.
+// Optional questions without a scripted answer are skipped;
+// the run fails when a required question has no scripted answer.
+func Scripted(answers map[string]int) Strategy {
+	return func(question gdq.Question) (int, error) {
+		if answer, ok := answers[question.Id]; ok {
+			return answer, nil
+		}
+		if question.Optional {
+			return gdq.SkipAnswer, nil
+		}
+		return 0, errors.New("no scripted answer")
+	}
+}
+
+// First answers every question with its first displayed answer.
+func First() Strategy {
+	return func(question gdq.Question) (int, error) {
+		return answerIndices(question)[0], nil
+	}
+}
+
+// Random answers every question with one of its available answers, chosen at random.
+// The same seed always produces the same answers.
+func Random(seed uint64) Strategy {
+	r := rand.New(rand.NewPCG(seed, seed))
+	return func(question gdq.Question) (int, error) {
+		indices := answerIndices(question)
+		return indices[r.IntN(len(indices))], nil
+	}
+}
+
+// answerIndices returns the 1-indexed answers of the displayed answer options.
+func answerIndices(question gdq.Question) []int {
+	if question.AnswerIndices != nil {
+		return question.AnswerIndices
+	}
+	indices := make([]int, len(question.Answers))
+	for i := range indices {
+		indices[i] = i + 1
+	}
+	return indices
+}
+
+// Asked returns the IDs of the questions shown during the run, in the order they were shown.
+func (f *Flow) Asked() []string {
+	var asked []string
+	for _, step := range f.Steps {
+		for _, question := range step.Response.Questions {
+			if !slices.Contains(asked, question.Id) {
+				asked = append(asked, question.Id)
+			}
+		}
+	}
+	return asked
+}
+
+// Last returns the last response of the run, nil if the run has no step.
+func (f *Flow) Last() *gdq.Response {
+	if len(f.Steps) == 0 {
+		return nil
+	}
+	return f.Steps[len(f.Steps)-1].Response
+}
+
+// ClosingRemarks returns the IDs of the closing remarks of the last response.
+func (f *Flow) ClosingRemarks() []string {
+	var ids []string
+	if last := f.Last(); last != nil {
+		for _, remark := range last.ClosingRemarks {
+			ids = append(ids, remark.Id)
+		}
+	}
+	return ids
+}
+
+// AssertAsked checks that exactly these questions were shown, in this order.
+func (f *Flow) AssertAsked(t TestingT, ids ...string) {
+	t.Helper()
+	if asked := f.Asked(); !slices.Equal(asked, ids) {
+		t.Errorf("asked questions %v, want %v", asked, ids)
+	}
+}
+
+// AssertNotAsked checks that none of these questions were shown.
+func (f *Flow) AssertNotAsked(t TestingT, ids ...string) {
+	t.Helper()
+	asked := f.Asked()
+	for _, id := range ids {
+		if slices.Contains(asked, id) {
+			t.Errorf("question '%s' was asked, want not asked", id)
+		}
+	}
+}
+
+// AssertCompleted checks that the run ended with a completed response.
+func (f *Flow) AssertCompleted(t TestingT) {
+	t.Helper()
+	if last := f.Last(); last == nil || !last.Completed {
+		t.Errorf("questionnaire not completed")
+	}
+}
+
+// AssertClosingRemarks checks that the run ended with exactly these closing remarks, in this order.
+func (f *Flow) AssertClosingRemarks(t TestingT, ids ...string) {
+	t.Helper()
+	if remarks := f.ClosingRemarks(); !slices.Equal(remarks, ids) {
+		t.Errorf("closing remarks %v, want %v", remarks, ids)
+	}
+}
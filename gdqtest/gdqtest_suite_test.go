@@ -0,0 +1,13 @@
+package gdqtest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGdqtest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gdqtest Suite")
+}
@@ -0,0 +1,117 @@
+package gdqtest_test
+
+import (
+	"fmt"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// recorder is a gdqtest.TestingT collecting failures instead of failing the test.
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+var _ = Describe("gdqtest", func() {
+	var q gdq.Questionnaire
+
+	BeforeEach(func() {
+		var err error
+		q, err = gdq.New([]byte(`
+questions:
+  - id: "experience"
+    text: "Do you have programming experience?"
+    answers: ["Yes", "No"]
+  - id: "language"
+    text: "Which language do you prefer?"
+    answers: ["Go", "Python"]
+    depends_on: ["experience"]
+    condition: 'answers["experience"] == 1'
+  - id: "newsletter"
+    text: "Subscribe to our newsletter?"
+    answers: ["Yes", "No"]
+    required: false
+closing_remarks:
+  - id: "gopher"
+    text: "Welcome, gopher!"
+    condition: 'answers["language"] == 1'
+  - id: "thanks"
+    text: "Thank you!"`))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("Run", func() {
+		It("should drive the questionnaire with scripted answers", func() {
+			flow, err := gdqtest.Run(q, gdqtest.Scripted(map[string]int{"experience": 1, "language": 1}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flow.Asked()).To(Equal([]string{"experience", "newsletter", "language"}))
+			Expect(flow.Answers).To(Equal(map[string]int{"experience": 1, "language": 1, "newsletter": gdq.SkipAnswer}))
+			Expect(flow.Steps).To(HaveLen(3))
+			Expect(flow.Steps[0].Answers).To(BeEmpty())
+			Expect(flow.Last().Completed).To(BeTrue())
+			Expect(flow.ClosingRemarks()).To(Equal([]string{"gopher", "thanks"}))
+		})
+
+		It("should fail when a required question has no scripted answer", func() {
+			_, err := gdqtest.Run(q, gdqtest.Scripted(map[string]int{"experience": 1}))
+			Expect(err).To(MatchError("step 2: question 'language': no scripted answer"))
+		})
+
+		It("should report errors returned by Next", func() {
+			_, err := gdqtest.Run(q, gdqtest.Scripted(map[string]int{"experience": 3}))
+			Expect(err).To(MatchError(ContainSubstring("step 2: invalid answers provided")))
+		})
+
+		It("should answer with the first answers", func() {
+			flow, err := gdqtest.Run(q, gdqtest.First())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flow.Answers).To(Equal(map[string]int{"experience": 1, "language": 1, "newsletter": 1}))
+		})
+
+		It("should answer randomly and reproducibly", func() {
+			first, err := gdqtest.Run(q, gdqtest.Random(42))
+			Expect(err).ToNot(HaveOccurred())
+			first.AssertCompleted(GinkgoT())
+
+			second, err := gdqtest.Run(q, gdqtest.Random(42))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Answers).To(Equal(first.Answers))
+		})
+	})
+
+	Describe("assertions", func() {
+		It("should pass when the flow matches", func() {
+			flow := gdqtest.MustRun(GinkgoT(), q, gdqtest.Scripted(map[string]int{"experience": 2}))
+			flow.AssertAsked(GinkgoT(), "experience", "newsletter")
+			flow.AssertNotAsked(GinkgoT(), "language")
+			flow.AssertCompleted(GinkgoT())
+			flow.AssertClosingRemarks(GinkgoT(), "thanks")
+		})
+
+		It("should report mismatches", func() {
+			flow := gdqtest.MustRun(GinkgoT(), q, gdqtest.Scripted(map[string]int{"experience": 2}))
+
+			t := &recorder{}
+			flow.AssertAsked(t, "experience", "language")
+			flow.AssertNotAsked(t, "newsletter")
+			flow.AssertClosingRemarks(t, "gopher")
+			Expect(t.failures).To(Equal([]string{
+				"asked questions [experience newsletter], want [experience language]",
+				"question 'newsletter' was asked, want not asked",
+				"closing remarks [thanks], want [gopher]",
+			}))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package gdqtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden rewrite
+// golden files instead of comparing against them: GDQ_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "GDQ_UPDATE_GOLDEN"
+
+// Record writes the flow to a JSON golden file, creating its directory if needed.
+func Record(path string, flow *Flow) error {
+	data, err := json.MarshalIndent(flow, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode flow: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create golden directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a flow from a JSON golden file written by Record.
+func Load(path string) (*Flow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden file: %w", err)
+	}
+	var flow Flow
+	if err := json.Unmarshal(data, &flow); err != nil {
+		return nil, fmt.Errorf("failed to decode golden file: %w", err)
+	}
+	return &flow, nil
+}
+
+// Replay submits the answers of every step of the golden file to the questionnaire and
+// returns an error describing the first response that differs from the recorded one.
+//
+// Questionnaires shuffling questions or answers must be replayed with the seed used
+// for the recording (see gdq.WithSeed).
+func Replay(q gdq.Questionnaire, path string, opts ...gdq.NextOption) error {
+	golden, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	for i, step := range golden.Steps {
+		response, err := q.Next(step.Answers, opts...)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		want, err := json.MarshalIndent(step.Response, "", "  ")
+		if err != nil {
+			return fmt.Errorf("step %d: failed to encode recorded response: %w", i+1, err)
+		}
+		got, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return fmt.Errorf("step %d: failed to encode response: %w", i+1, err)
+		}
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("step %d: response differs from golden file\nwant: %s\ngot:  %s", i+1, want, got)
+		}
+	}
+	return nil
+}
+
+// AssertGolden runs the questionnaire with the strategy and compares the flow with the golden file.
+//
+// The golden file is recorded when it doesn't exist yet, or when the UpdateGoldenEnv
+// environment variable is set; review and commit it along with the questionnaire.
+func AssertGolden(t TestingT, q gdq.Questionnaire, path string, strategy Strategy, opts ...gdq.NextOption) {
+	t.Helper()
+
+	_, err := os.Stat(path)
+	if os.Getenv(UpdateGoldenEnv) != "" || errors.Is(err, fs.ErrNotExist) {
+		flow := MustRun(t, q, strategy, opts...)
+		if err := Record(path, flow); err != nil {
+			t.Fatalf("failed to record golden flow: %v", err)
+		}
+		return
+	}
+
+	if err := Replay(q, path, opts...); err != nil {
+		t.Errorf("golden flow %s: %v", path, err)
+	}
+}
@@ -0,0 +1,79 @@
+package gdqtest_test
+
+import (
+	"os"
+	"path/filepath"
+
+	gdq "github.com/antfroger/go-dynamic-questionnaire"
+	"github.com/antfroger/go-dynamic-questionnaire/gdqtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("golden flows", func() {
+	const config = `
+questions:
+  - id: "experience"
+    text: "Do you have programming experience?"
+    answers: ["Yes", "No"]
+  - id: "language"
+    text: "Which language do you prefer?"
+    answers: ["Go", "Python"]
+    depends_on: ["experience"]
+    condition: 'answers["experience"] == 1'
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`
+
+	var (
+		q    gdq.Questionnaire
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		q, err = gdq.New([]byte(config))
+		Expect(err).ToNot(HaveOccurred())
+		path = filepath.Join(GinkgoT().TempDir(), "flows", "developer.json")
+	})
+
+	It("should record and replay a flow", func() {
+		flow, err := gdqtest.Run(q, gdqtest.First())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gdqtest.Record(path, flow)).To(Succeed())
+
+		loaded, err := gdqtest.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded.Answers).To(Equal(flow.Answers))
+		Expect(loaded.Steps).To(HaveLen(3))
+
+		Expect(gdqtest.Replay(q, path)).To(Succeed())
+	})
+
+	It("should fail when the questionnaire behavior changes", func() {
+		flow, err := gdqtest.Run(q, gdqtest.First())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gdqtest.Record(path, flow)).To(Succeed())
+
+		changed, err := gdq.New([]byte(config + `
+  - id: "gopher"
+    text: "Welcome, gopher!"
+    condition: 'answers["language"] == 1'`))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(gdqtest.Replay(changed, path)).To(MatchError(HavePrefix("step 3: response differs from golden file")))
+	})
+
+	It("should record missing golden files then compare against them", func() {
+		gdqtest.AssertGolden(GinkgoT(), q, path, gdqtest.First())
+		Expect(path).To(BeAnExistingFile())
+
+		t := &recorder{}
+		gdqtest.AssertGolden(t, q, path, gdqtest.First())
+		Expect(t.failures).To(BeEmpty())
+
+		Expect(os.WriteFile(path, []byte(`{"steps": [{"answers": {}, "response": {"completed": true}}]}`), 0o644)).To(Succeed())
+		gdqtest.AssertGolden(t, q, path, gdqtest.First())
+		Expect(t.failures).To(ConsistOf(HavePrefix("golden flow " + path + ": step 1: response differs from golden file")))
+	})
+})
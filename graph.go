@@ -0,0 +1,165 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// flowGraph is the graph of a questionnaire flow, rendered by the diagram exporters.
+	// Nodes are the questions and closing remarks, edges the dependencies between them.
+	flowGraph struct {
+		nodes []flowNode
+		edges []flowEdge
+	}
+
+	// flowNode is a node of the flow graph.
+	flowNode struct {
+		id    string   // Identifier of the node in the diagram, safe for every output format
+		kind  nodeKind // Kind of element represented by the node
+		label string   // Text displayed in the node
+	}
+
+	// flowEdge is an edge of the flow graph.
+	flowEdge struct {
+		from   string // Identifier of the source node
+		to     string // Identifier of the target node
+		label  string // Condition displayed on the edge, empty if unconditional
+		remark bool   // Whether the edge leads to a closing remark rather than a question
+	}
+
+	// nodeKind is the kind of element represented by a flow graph node.
+	nodeKind int
+)
+
+const (
+	startNode    nodeKind = iota // Entry point of the questionnaire
+	questionNode                 // A question
+	remarkNode                   // A closing remark
+)
+
+// startNodeID is the identifier of the entry point node.
+const startNodeID = "start"
+
+// flowGraph builds the flow graph of the questionnaire:
+//   - the start node leads to the questions without dependencies
+//   - each dependency leads to the dependent question, labeled with its condition
+//   - the questions referenced by a closing remark condition lead to the remark
+//
+// Texts are rendered in the default locale.
+func (q *questionnaire) flowGraph() flowGraph {
+	graph := flowGraph{nodes: []flowNode{{id: startNodeID, kind: startNode, label: "Start"}}}
+
+	nodeIDs := make(map[string]string, len(q.QuestionList))
+	for i, question := range q.QuestionList {
+		id := fmt.Sprintf("q%d", i+1)
+		nodeIDs[question.Id] = id
+		graph.nodes = append(graph.nodes, flowNode{
+			id:    id,
+			kind:  questionNode,
+			label: question.Id + ": " + question.Text.resolve(q.DefaultLocale, q.DefaultLocale),
+		})
+	}
+
+	for _, question := range q.QuestionList {
+		if len(question.DependsOn) == 0 {
+			graph.edges = append(graph.edges, flowEdge{from: startNodeID, to: nodeIDs[question.Id], label: question.Condition})
+			continue
+		}
+		for _, depID := range question.DependsOn {
+			graph.edges = append(graph.edges, flowEdge{from: nodeIDs[depID], to: nodeIDs[question.Id], label: question.Condition})
+		}
+	}
+
+	for i, remark := range q.Remarks {
+		id := fmt.Sprintf("r%d", i+1)
+		graph.nodes = append(graph.nodes, flowNode{
+			id:    id,
+			kind:  remarkNode,
+			label: remark.Id + ": " + remark.Text.resolve(q.DefaultLocale, q.DefaultLocale),
+		})
+		for _, questionID := range extractQuestionIDs(remark.Condition) {
+			if from, ok := nodeIDs[questionID]; ok {
+				graph.edges = append(graph.edges, flowEdge{from: from, to: id, label: remark.Condition, remark: true})
+			}
+		}
+	}
+
+	return graph
+}
+
+// ExportMermaid renders the questionnaire flow as a Mermaid flowchart.
+func (q *questionnaire) ExportMermaid() string {
+	graph := q.flowGraph()
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, node := range graph.nodes {
+		label := mermaidEscape(node.label)
+		switch node.kind {
+		case startNode:
+			fmt.Fprintf(&b, "    %s((\"%s\"))\n", node.id, label)
+		case questionNode:
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", node.id, label)
+		case remarkNode:
+			fmt.Fprintf(&b, "    %s([\"%s\"])\n", node.id, label)
+		}
+	}
+	for _, edge := range graph.edges {
+		arrow := "-->"
+		if edge.remark {
+			arrow = "-.->"
+		}
+		if edge.label == "" {
+			fmt.Fprintf(&b, "    %s %s %s\n", edge.from, arrow, edge.to)
+		} else {
+			fmt.Fprintf(&b, "    %s %s|\"%s\"| %s\n", edge.from, arrow, mermaidEscape(edge.label), edge.to)
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscape escapes the characters that can't appear in a quoted Mermaid label.
+func mermaidEscape(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(text)
+}
+
+// ExportDOT renders the questionnaire flow as a Graphviz DOT digraph.
+func (q *questionnaire) ExportDOT() string {
+	graph := q.flowGraph()
+
+	var b strings.Builder
+	b.WriteString("digraph questionnaire {\n")
+	b.WriteString("    node [shape=box];\n")
+	for _, node := range graph.nodes {
+		switch node.kind {
+		case startNode:
+			fmt.Fprintf(&b, "    %s [label=\"%s\", shape=circle];\n", node.id, dotEscape(node.label))
+		case questionNode:
+			fmt.Fprintf(&b, "    %s [label=\"%s\"];\n", node.id, dotEscape(node.label))
+		case remarkNode:
+			fmt.Fprintf(&b, "    %s [label=\"%s\", style=rounded];\n", node.id, dotEscape(node.label))
+		}
+	}
+	for _, edge := range graph.edges {
+		var attributes []string
+		if edge.label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=\"%s\"", dotEscape(edge.label)))
+		}
+		if edge.remark {
+			attributes = append(attributes, "style=dashed")
+		}
+		if len(attributes) == 0 {
+			fmt.Fprintf(&b, "    %s -> %s;\n", edge.from, edge.to)
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", edge.from, edge.to, strings.Join(attributes, ", "))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotEscape escapes the characters that can't appear in a quoted DOT string.
+func dotEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,178 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// groupIndexPlaceholder is replaced with the 1-indexed iteration in the IDs, texts and expressions of the group questions.
+const groupIndexPlaceholder = "{index}"
+
+// questionGroup represents questions asked once for every item counted by the answer of a number question.
+//
+// Example usage in YAML:
+//
+//	groups:
+//	  - id: "child"
+//	    repeat_for: "children"
+//	    max: 5
+//	    questions:
+//	      - id: "age"
+//	        text: "How old is child {index}?"
+//	        type: "number"
+type questionGroup struct {
+	Id        string     `yaml:"id" json:"id"`                 // Unique identifier for the group, prefixing the IDs of its questions
+	RepeatFor string     `yaml:"repeat_for" json:"repeat_for"` // ID of the number question whose answer is the number of iterations
+	Max       int        `yaml:"max" json:"max"`               // Maximum number of iterations
+	Questions []question `yaml:"questions" json:"questions"`   // Questions asked at every iteration
+}
+
+// groupQuestionID returns the ID of the question of a group at an iteration, e.g. "child[2].age".
+func groupQuestionID(groupID string, iteration int, questionID string) string {
+	return fmt.Sprintf("%s[%d].%s", groupID, iteration, questionID)
+}
+
+// expandGroups translates the repeating groups into regular questions, so that they are validated,
+// evaluated and counted in the progress like any other question:
+//
+//	groups:
+//	  - id: "child"
+//	    repeat_for: "children"
+//	    max: 5
+//	    questions:
+//	      - id: "age"
+//	        text: "How old is child {index}?"
+//	        type: "number"
+//	      - id: "school"
+//	        text: "Which school does child {index} go to?"
+//	        type: "text"
+//	        condition: 'values["child[{index}].age"] >= 3'
+//
+// Every question of the group is declared max times, right after the repeat_for question, with {index} replaced
+// by the iteration in its ID, texts and expressions: "child[2].age" asks "How old is child 2?".
+// The questions of an iteration are only shown when the repeat_for answer is at least the iteration,
+// so answering 2 children asks the questions of "child[1]" and "child[2]".
+func (q *questionnaire) expandGroups() error {
+	var errs []error
+	groupIDs := make(map[string]bool)
+	instances := make(map[string][]question)
+
+	for _, group := range q.Groups {
+		switch counter := q.findQuestionByID(group.RepeatFor); {
+		case group.Id == "":
+			errs = append(errs, invalidGroupError(group.Id, errors.New("the group has no ID")))
+		case groupIDs[group.Id]:
+			errs = append(errs, invalidGroupError(group.Id, errors.New("the group ID is used more than once")))
+		case counter == nil:
+			errs = append(errs, invalidGroupError(group.Id, fmt.Errorf("repeat_for question '%s' does not exist", group.RepeatFor)))
+		case counter.kind() != NumberQuestion:
+			errs = append(errs, invalidGroupError(group.Id, fmt.Errorf("repeat_for question '%s' is not a number question", group.RepeatFor)))
+		case group.Max < 1:
+			errs = append(errs, invalidGroupError(group.Id, errors.New("max must be at least 1")))
+		case len(group.Questions) == 0:
+			errs = append(errs, invalidGroupError(group.Id, errors.New("the group has no questions")))
+		default:
+			expanded, err := group.expand()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			instances[group.RepeatFor] = append(instances[group.RepeatFor], expanded...)
+		}
+		groupIDs[group.Id] = true
+	}
+	if len(instances) == 0 {
+		return errors.Join(errs...)
+	}
+
+	questions := make([]question, 0, len(q.QuestionList))
+	for _, question := range q.QuestionList {
+		questions = append(questions, question)
+		questions = append(questions, instances[question.Id]...)
+		delete(instances, question.Id) // Only expanded once when an ID is duplicated
+	}
+	q.QuestionList = questions
+	return errors.Join(errs...)
+}
+
+// expand returns the questions of every iteration of the group, iteration after iteration.
+func (g questionGroup) expand() ([]question, error) {
+	var errs []error
+	members := make([]question, len(g.Questions))
+	for i, member := range g.Questions {
+		if member.Id == "" {
+			errs = append(errs, invalidGroupError(g.Id, errors.New("a question of the group has no ID")))
+			continue
+		}
+
+		// When rules are translated first, so that {index} can be replaced in the question IDs they reference
+		condition, err := combineCondition(member.Condition, member.When)
+		if err != nil {
+			errs = append(errs, invalidWhenRuleError("question_id", groupQuestionID(g.Id, 1, member.Id), err))
+		}
+		member.Condition, member.When = condition, nil
+		member.Answers = append([]answerOption(nil), member.Answers...)
+		for j := range member.Answers {
+			option := &member.Answers[j]
+			condition, err := combineCondition(option.Condition, option.When)
+			if err != nil {
+				errs = append(errs, invalidWhenRuleError("question_id", groupQuestionID(g.Id, 1, member.Id), err))
+			}
+			option.Condition, option.When = condition, nil
+		}
+		members[i] = member
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	questions := make([]question, 0, g.Max*len(members))
+	for iteration := 1; iteration <= g.Max; iteration++ {
+		for _, member := range members {
+			questions = append(questions, g.instance(member, iteration))
+		}
+	}
+	return questions, nil
+}
+
+// instance returns the question of the group at the iteration: {index} is replaced in its ID, texts and expressions,
+// and it is only shown when the answer of the repeat_for question is at least the iteration.
+func (g questionGroup) instance(member question, iteration int) question {
+	index := strconv.Itoa(iteration)
+	replace := func(s string) string {
+		return strings.ReplaceAll(s, groupIndexPlaceholder, index)
+	}
+
+	instance := member
+	instance.Id = groupQuestionID(g.Id, iteration, member.Id)
+	instance.Text = member.Text.replace(groupIndexPlaceholder, index)
+	instance.Description = member.Description.replace(groupIndexPlaceholder, index)
+	instance.Help = member.Help.replace(groupIndexPlaceholder, index)
+	instance.TerminateIf = replace(member.TerminateIf)
+	instance.Value = replace(member.Value)
+	instance.group = g.Id
+	instance.iteration = iteration
+
+	instance.Answers = make([]answerOption, len(member.Answers))
+	for i, option := range member.Answers {
+		option.Text = option.Text.replace(groupIndexPlaceholder, index)
+		option.Condition = replace(option.Condition)
+		option.Next = replace(option.Next)
+		instance.Answers[i] = option
+	}
+
+	// A repeat_for question left unanswered or skipped counts no iteration
+	repeated := fmt.Sprintf("(values[%s] ?? 0) >= %d", strconv.Quote(g.RepeatFor), iteration)
+	instance.Condition = andConditions(replace(member.Condition), repeated)
+	if member.DependsOn != nil {
+		instance.DependsOn = make([]string, 0, len(member.DependsOn)+1)
+		for _, id := range member.DependsOn {
+			instance.DependsOn = append(instance.DependsOn, replace(id))
+		}
+		if !contains(instance.DependsOn, g.RepeatFor) {
+			instance.DependsOn = append(instance.DependsOn, g.RepeatFor)
+		}
+	}
+	return instance
+}
@@ -0,0 +1,140 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// validateHiddenQuestions checks that only hidden questions declare a value expression.
+// Value expressions are compiled along with the conditions of the questionnaire.
+func (q *questionnaire) validateHiddenQuestions() error {
+	var errs []error
+	for _, question := range q.QuestionList {
+		if question.Value != "" && !question.Hidden {
+			err := errors.New("only hidden questions can declare a value")
+			errs = append(errs, invalidConditionError("question_id", question.Id, question.Value, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fillHiddenAnswers answers the hidden questions, which are never returned by Next:
+//
+//	questions:
+//	  - id: "segment"
+//	    text: "Customer segment"
+//	    hidden: true
+//	    answers: ["Consumer", "Business"]
+//	    value: 'answers["company"] == 1 ? 2 : 1'
+//
+// A hidden question is answered from the context answers (see WithContextAnswers) or,
+// when the context doesn't answer it, with the result of its value expression.
+// Like other questions, it is only answered once its dependencies are answered and its condition holds;
+// the value expression is only evaluated once every question it references is answered.
+//
+// Hidden questions are answered again on every call, in configuration order, so the answers provided for them are ignored.
+// The provided answers map is not modified: a new map is returned, and the returned questionnaire is a copy
+// holding the values of the hidden questions that aren't single choice.
+func (q *questionnaire) fillHiddenAnswers(answers map[string]int, options *nextOptions) (*questionnaire, map[string]int, error) {
+	if len(q.hidden) == 0 {
+		return q, answers, nil
+	}
+
+	scoped := *q
+	scoped.values = maps.Clone(q.values)
+	if scoped.values == nil {
+		scoped.values = make(map[string]interface{})
+	}
+	filled := maps.Clone(answers)
+	if filled == nil {
+		filled = make(map[string]int, len(q.hidden))
+	}
+
+	for _, position := range q.hidden {
+		question := &q.QuestionList[position]
+		delete(filled, question.Id)
+		delete(scoped.values, question.Id)
+		if !scoped.areDependenciesSatisfied(*question, filled) {
+			continue
+		}
+		if question.Condition != "" {
+			show, err := scoped.evaluateCondition(question.Condition, filled)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to evaluate condition of hidden question '%s': %w", question.Id, err)
+			}
+			if !show {
+				continue
+			}
+		}
+
+		answer, ok := options.context[question.Id]
+		if !ok && question.Value != "" && scoped.isAnswered(extractReferences(question.Value, q.Computed), filled) {
+			result, err := scoped.evaluateExpression(question.Value, filled, false)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to compute the answer of hidden question '%s': %w", question.Id, err)
+			}
+			if answer, err = answerOf(result); err != nil {
+				return nil, nil, fmt.Errorf("failed to compute the answer of hidden question '%s': %w", question.Id, err)
+			}
+			ok = answer.kind != noAnswer
+		}
+		if !ok {
+			continue
+		}
+
+		choice, value, err := scoped.splitAnswer(question.Id, answer)
+		if err != nil {
+			return nil, nil, err
+		}
+		filled[question.Id] = choice
+		if value != nil {
+			scoped.values[question.Id] = value
+		}
+	}
+
+	if len(scoped.values) == 0 {
+		scoped.values = nil
+	}
+	return &scoped, filled, nil
+}
+
+// isAnswered reports whether every question is answered and not skipped.
+func (q *questionnaire) isAnswered(questionIDs []string, answers map[string]int) bool {
+	for _, id := range questionIDs {
+		if answer, answered := answers[id]; !answered || answer == SkipAnswer {
+			return false
+		}
+	}
+	return true
+}
+
+// answerOf converts the result of a value expression into an answer: an int is a Choice, a float64 a Number,
+// a string a Text and a list of ints Choices. It returns the zero Answer when the result is nil,
+// which leaves the question unanswered.
+func answerOf(result interface{}) (Answer, error) {
+	switch value := result.(type) {
+	case nil:
+		return Answer{}, nil
+	case int:
+		return Choice(value), nil
+	case float64:
+		return Number(value), nil
+	case string:
+		return Text(value), nil
+	case []int:
+		return Choices(value...), nil
+	case []interface{}:
+		choices := make([]int, 0, len(value))
+		for _, item := range value {
+			choice, ok := item.(int)
+			if !ok {
+				return Answer{}, fmt.Errorf("value must be a list of integers, got %v", result)
+			}
+			choices = append(choices, choice)
+		}
+		return Choices(choices...), nil
+	default:
+		return Answer{}, fmt.Errorf("value must be a number, a string or a list of integers, got %T", result)
+	}
+}
@@ -0,0 +1,104 @@
+package go_dynamic_questionnaire
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// localizedText is a text that can be declared either as a plain string
+// or as a map of locale to translation:
+//
+//	text: "How satisfied are you?"
+//	text:
+//	  en: "How satisfied are you?"
+//	  fr: "Êtes-vous satisfait ?"
+//
+// A plain string is stored under the empty locale.
+type localizedText map[string]string
+
+// newLocalizedText creates a localizedText holding a single untranslated text.
+func newLocalizedText(text string) localizedText {
+	return localizedText{"": text}
+}
+
+// String returns the text in the default language (untranslated text, or first locale in alphabetical order).
+func (t localizedText) String() string {
+	return t.resolve("", "")
+}
+
+// resolve returns the text for the requested locale, with the following fallbacks:
+//   - the base language of the locale (e.g. "fr" for "fr-CA")
+//   - the fallback locale (usually the questionnaire default_locale)
+//   - the untranslated text
+//   - the first locale in alphabetical order
+func (t localizedText) resolve(locale, fallback string) string {
+	if len(t) == 0 {
+		return ""
+	}
+
+	for _, candidate := range []string{locale, baseLanguage(locale), fallback, baseLanguage(fallback), ""} {
+		if text, ok := t[candidate]; ok {
+			return text
+		}
+	}
+
+	locales := make([]string, 0, len(t))
+	for l := range t {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return t[locales[0]]
+}
+
+// replace returns a copy of the text with every occurrence of old replaced by new in each translation.
+func (t localizedText) replace(old, new string) localizedText {
+	if t == nil {
+		return nil
+	}
+	replaced := make(localizedText, len(t))
+	for locale, text := range t {
+		replaced[locale] = strings.ReplaceAll(text, old, new)
+	}
+	return replaced
+}
+
+// baseLanguage returns the language part of a locale ("fr" for "fr-CA" or "fr_CA").
+func baseLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// UnmarshalYAML allows texts to be declared either as a plain string or as a map of translations.
+func (t *localizedText) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err == nil {
+		*t = newLocalizedText(text)
+		return nil
+	}
+
+	var translations map[string]string
+	if err := unmarshal(&translations); err != nil {
+		return err
+	}
+	*t = translations
+	return nil
+}
+
+// UnmarshalJSON allows texts to be declared either as a plain string or as a map of translations.
+func (t *localizedText) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = newLocalizedText(text)
+		return nil
+	}
+
+	var translations map[string]string
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return err
+	}
+	*t = translations
+	return nil
+}
@@ -0,0 +1,45 @@
+package go_dynamic_questionnaire
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("I18n", func() {
+	Describe("localizedText.resolve", func() {
+		text := localizedText{"en": "Hello", "fr": "Bonjour", "de": "Hallo"}
+
+		It("should return the requested locale", func() {
+			Expect(text.resolve("fr", "en")).To(Equal("Bonjour"))
+		})
+
+		It("should fall back to the base language", func() {
+			Expect(text.resolve("fr-CA", "en")).To(Equal("Bonjour"))
+			Expect(text.resolve("fr_BE", "en")).To(Equal("Bonjour"))
+		})
+
+		It("should fall back to the default locale", func() {
+			Expect(text.resolve("es", "en")).To(Equal("Hello"))
+		})
+
+		It("should fall back to the untranslated text", func() {
+			Expect(localizedText{"": "Hi", "fr": "Salut"}.resolve("es", "")).To(Equal("Hi"))
+		})
+
+		It("should fall back to the first locale in alphabetical order", func() {
+			Expect(text.resolve("es", "it")).To(Equal("Hallo"))
+		})
+
+		It("should return an empty string for empty texts", func() {
+			Expect(localizedText(nil).resolve("en", "fr")).To(BeEmpty())
+		})
+	})
+
+	Describe("baseLanguage", func() {
+		It("should strip the region", func() {
+			Expect(baseLanguage("pt-BR")).To(Equal("pt"))
+			Expect(baseLanguage("pt_BR")).To(Equal("pt"))
+			Expect(baseLanguage("pt")).To(Equal("pt"))
+		})
+	})
+})
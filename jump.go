@@ -0,0 +1,70 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// jump records the answer options of a question leading to a jump target.
+type jump struct {
+	source string // ID of the question declaring the jump
+	values []int  // Canonical values (1-indexed) of the options jumping to the target
+}
+
+// expandJumps translates the next targets of answer options into the regular evaluation model,
+// giving a decision-tree authoring style:
+//
+//	questions:
+//	  - id: "smoker"
+//	    text: "Do you smoke?"
+//	    answers:
+//	      - text: "Yes"
+//	        next: "cigarettes"
+//	      - "No"
+//
+// A question targeted by the options of another question depends on that question,
+// and is only shown when one of these options is chosen:
+// "cigarettes" gets depends_on: ["smoker"] and condition: 'answers["smoker"] in [1]'.
+// Its own dependencies and condition, if any, still apply.
+func (q *questionnaire) expandJumps() error {
+	var errs []error
+	jumps := make(map[string]*jump)
+	var targets []string
+
+	for _, question := range q.QuestionList {
+		for i, option := range question.Answers {
+			target := option.Next
+			if target == "" {
+				continue
+			}
+
+			switch j := jumps[target]; {
+			case target == question.Id:
+				errs = append(errs, invalidJumpError(question.Id, target, errors.New("a question can't jump to itself")))
+			case q.findQuestionByID(target) == nil:
+				errs = append(errs, invalidJumpError(question.Id, target, errors.New("the question does not exist")))
+			case j == nil:
+				jumps[target] = &jump{source: question.Id, values: []int{i + 1}}
+				targets = append(targets, target)
+			case j.source != question.Id:
+				err := fmt.Errorf("the question is already the jump target of question '%s'", j.source)
+				errs = append(errs, invalidJumpError(question.Id, target, err))
+			default:
+				j.values = append(j.values, i+1)
+			}
+		}
+	}
+
+	for _, target := range targets {
+		j := jumps[target]
+		question := q.findQuestionByID(target)
+		condition := fmt.Sprintf("answers[%s] in %s", strconv.Quote(j.source), intList(j.values))
+		question.Condition = andConditions(question.Condition, condition)
+		if !contains(question.DependsOn, j.source) {
+			question.DependsOn = append(question.DependsOn, j.source)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"slices"
+)
+
+// LintReport is the result of linting a questionnaire.
+// Errors make the questionnaire unusable (New or Next would fail), whereas
+// warnings point at likely mistakes that don't prevent it from being used.
+type LintReport struct {
+	Errors   []ValidationError `json:"errors,omitempty"`   // Problems making the questionnaire unusable
+	Warnings []Warning         `json:"warnings,omitempty"` // Likely mistakes (unreachable questions, dead ends...)
+}
+
+// HasErrors reports whether the report contains at least one error.
+// CI pipelines typically fail on errors and only print warnings.
+func (r LintReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// Lint loads the questionnaire and reports all the problems found in it.
+//
+// Unlike New, Lint doesn't fail when the questionnaire is invalid: the validation
+// errors are returned in the report. The returned error is only set when the
+// configuration can't be loaded at all (unreadable file, syntax error...).
+//
+// Example usage:
+//
+//	report, err := gdq.Lint("questionnaire.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, warning := range report.Warnings {
+//	    log.Printf("warning: %s", warning.Message)
+//	}
+//	if report.HasErrors() {
+//	    os.Exit(1)
+//	}
+func Lint[T config](config T, opts ...Option) (LintReport, error) {
+	q, err := New(config, opts...)
+	if err == nil {
+		return q.Lint(), nil
+	}
+
+	validationErrs := ValidationErrors(err)
+	if len(validationErrs) == 0 {
+		return LintReport{}, err
+	}
+	return LintReport{Errors: validationErrs}, nil
+}
+
+// Lint reports the problems found in the questionnaire: the warnings detected by New,
+// the ones found by Analyze, and condition evaluation errors met along the answer paths.
+func (q *questionnaire) Lint() LintReport {
+	explorer := q.explorePaths()
+
+	return LintReport{
+		Errors:   ValidationErrors(errors.Join(explorer.failures...)),
+		Warnings: append(slices.Clone(q.warnings), q.analysisWarnings(explorer)...),
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/goccy/go-yaml"
@@ -122,10 +123,10 @@ func loadWithUnmarshaler(data interface{}, q *questionnaire, unmarshal unmarshal
 
 	switch v := data.(type) {
 	case string:
-		// Load from file
-		content, err = os.ReadFile(v)
+		// Load from file, or from an object store
+		content, err = q.readConfig(v)
 		if err != nil {
-			return fmt.Errorf("failed to read file %q: %w", v, err)
+			return err
 		}
 	case []byte:
 		// Load from byte array
@@ -134,10 +135,27 @@ func loadWithUnmarshaler(data interface{}, q *questionnaire, unmarshal unmarshal
 		return fmt.Errorf("unsupported data type for loader: %T", data)
 	}
 
+	// The content is verified as published, before anything else reads it
+	if err := q.verifySignature(content); err != nil {
+		return err
+	}
+	for _, transform := range q.transforms {
+		if content, err = transform(content); err != nil {
+			return fmt.Errorf("failed to transform content: %w", err)
+		}
+	}
+	content, err = q.expandEnvVars(content)
+	if err != nil {
+		return err
+	}
+
 	// Unmarshal directly into the questionnaire struct
 	if err := unmarshal(content, q); err != nil {
 		return fmt.Errorf("failed to parse content: %w", err)
 	}
+	if err := q.renderParams(); err != nil {
+		return err
+	}
 
 	// Basic validation to ensure data structure is valid
 	if err := validateLoadedQuestionnaire(q); err != nil {
@@ -147,13 +165,41 @@ func loadWithUnmarshaler(data interface{}, q *questionnaire, unmarshal unmarshal
 	return nil
 }
 
+// envReference matches the ${VAR} and ${VAR:-default} references of a configuration.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnvVars replaces the ${VAR} references of the configuration with the environment variables (see WithEnvExpansion),
+// or returns it untouched when the expansion isn't enabled.
+func (q *questionnaire) expandEnvVars(content []byte) ([]byte, error) {
+	if !q.expandEnv {
+		return content, nil
+	}
+
+	var missing []string
+	expanded := envReference.ReplaceAllFunc(content, func(reference []byte) []byte {
+		match := envReference.FindSubmatch(reference)
+		if value, ok := os.LookupEnv(string(match[1])); ok {
+			return []byte(value)
+		}
+		if match[2] != nil {
+			return match[3]
+		}
+		missing = append(missing, string(match[1]))
+		return reference
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
 // validateLoadedQuestionnaire performs basic structural validation on the loaded questionnaire data.
 // This is called by each loader after parsing to ensure the data structure is valid.
 // Business logic validation (duplicate IDs, dependencies, etc.) is handled by the main validation.
 func validateLoadedQuestionnaire(q *questionnaire) error {
 	// Ensure slices are initialized (not nil)
-	if q.Questions == nil {
-		q.Questions = []question{}
+	if q.QuestionList == nil {
+		q.QuestionList = []question{}
 	}
 	if q.Remarks == nil {
 		q.Remarks = []closingRemark{}
@@ -161,3 +207,27 @@ func validateLoadedQuestionnaire(q *questionnaire) error {
 
 	return nil
 }
+
+// UnmarshalYAML allows answer options to be declared either as a plain string or as an object.
+func (o *answerOption) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err == nil {
+		*o = answerOption{Text: newLocalizedText(text)}
+		return nil
+	}
+
+	type plain answerOption
+	return unmarshal((*plain)(o))
+}
+
+// UnmarshalJSON allows answer options to be declared either as a plain string or as an object.
+func (o *answerOption) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*o = answerOption{Text: newLocalizedText(text)}
+		return nil
+	}
+
+	type plain answerOption
+	return json.Unmarshal(data, (*plain)(o))
+}
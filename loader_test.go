@@ -130,10 +130,10 @@ questions:
 				q := &questionnaire{}
 				err := loader.Load(yamlContent, q)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(q.Questions).ToNot(BeNil())
-				Expect(len(q.Questions)).To(Equal(2))
-				Expect(q.Questions[0].Id).To(Equal("test1"))
-				Expect(q.Questions[1].Id).To(Equal("test2"))
+				Expect(q.QuestionList).ToNot(BeNil())
+				Expect(len(q.QuestionList)).To(Equal(2))
+				Expect(q.QuestionList[0].Id).To(Equal("test1"))
+				Expect(q.QuestionList[1].Id).To(Equal("test2"))
 			})
 
 			It("should return error for invalid YAML", func() {
@@ -221,10 +221,10 @@ questions:
 				q := &questionnaire{}
 				err := loader.Load(jsonContent, q)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(q.Questions).ToNot(BeNil())
-				Expect(len(q.Questions)).To(Equal(2))
-				Expect(q.Questions[0].Id).To(Equal("test1"))
-				Expect(q.Questions[1].Id).To(Equal("test2"))
+				Expect(q.QuestionList).ToNot(BeNil())
+				Expect(len(q.QuestionList)).To(Equal(2))
+				Expect(q.QuestionList[0].Id).To(Equal("test1"))
+				Expect(q.QuestionList[1].Id).To(Equal("test2"))
 			})
 
 			It("should return error for invalid JSON", func() {
@@ -249,21 +249,48 @@ questions:
 			q := &questionnaire{}
 			err := validateLoadedQuestionnaire(q)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(q.Questions).ToNot(BeNil())
+			Expect(q.QuestionList).ToNot(BeNil())
 			Expect(q.Remarks).ToNot(BeNil())
-			Expect(q.Questions).To(HaveLen(0))
+			Expect(q.QuestionList).To(HaveLen(0))
 			Expect(q.Remarks).To(HaveLen(0))
 		})
 
 		It("should not modify existing slices", func() {
 			q := &questionnaire{
-				Questions: []question{{Id: "test", Text: "Test", Answers: []string{"Yes"}}},
-				Remarks:   []closingRemark{{Id: "remark", Text: "Test remark"}},
+				QuestionList: []question{{Id: "test", Text: newLocalizedText("Test"), Answers: []answerOption{{Text: newLocalizedText("Yes")}}}},
+				Remarks:      []closingRemark{{Id: "remark", Text: newLocalizedText("Test remark")}},
 			}
 			err := validateLoadedQuestionnaire(q)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(len(q.Questions)).To(Equal(1))
+			Expect(len(q.QuestionList)).To(Equal(1))
 			Expect(len(q.Remarks)).To(Equal(1))
 		})
 	})
+
+	Describe("expandEnvVars", func() {
+		BeforeEach(func() {
+			Expect(os.Setenv("GDQ_TEST_THRESHOLD", "7")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "GDQ_TEST_THRESHOLD")
+		})
+
+		It("should replace references with environment variables or their default", func() {
+			q := &questionnaire{expandEnv: true}
+			content, err := q.expandEnvVars([]byte(`complete_when: 'score >= ${GDQ_TEST_THRESHOLD}' # ${GDQ_TEST_UNSET:-none} $macro`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal(`complete_when: 'score >= 7' # none $macro`))
+		})
+
+		It("should fail when a variable without default isn't set", func() {
+			q := &questionnaire{expandEnv: true}
+			_, err := q.expandEnvVars([]byte(`complete_when: 'score >= ${GDQ_TEST_UNSET}'`))
+			Expect(err).To(MatchError("environment variables not set: GDQ_TEST_UNSET"))
+		})
+
+		It("should keep the content untouched when the expansion isn't enabled", func() {
+			q := &questionnaire{}
+			content, err := q.expandEnvVars([]byte(`complete_when: 'score >= ${GDQ_TEST_THRESHOLD}'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal(`complete_when: 'score >= ${GDQ_TEST_THRESHOLD}'`))
+		})
+	})
 })
@@ -0,0 +1,128 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// expandMacros replaces the macro references of every expression of the questionnaire
+// with the expressions of the macros, so that a condition can be written once and reused:
+//
+//	macros:
+//	  is_enterprise_customer: 'answers["company_size"] >= 3 && answers["plan"] == 2'
+//	questions:
+//	  - id: "account_manager"
+//	    text: "Would you like to talk to an account manager?"
+//	    answers: ["Yes", "No"]
+//	    condition: '$is_enterprise_customer'
+//
+// A reference ($ followed by the macro name) is replaced by the macro expression in parentheses,
+// so it can be combined with other expressions (e.g. '$is_enterprise_customer && answers["q1"] == 1').
+// References inside string literals are left untouched. Macros can't reference other macros.
+func (q *questionnaire) expandMacros() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(q.Macros)) {
+		macro := q.Macros[name]
+		switch {
+		case !isIdentifier(name) || name == "env":
+			errs = append(errs, invalidConditionError("macro", name, macro, errors.New("macro name must be an identifier other than env")))
+		case macro == "":
+			errs = append(errs, invalidConditionError("macro", name, macro, errors.New("macro has no expression")))
+		case len(macroReferences(macro)) > 0:
+			errs = append(errs, invalidConditionError("macro", name, macro, errors.New("macros can't reference macros")))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	expand := func(expression *string, ownerKey, ownerID string) {
+		expanded, err := q.expandMacroReferences(*expression)
+		if err != nil {
+			errs = append(errs, invalidConditionError(ownerKey, ownerID, *expression, err))
+			return
+		}
+		*expression = expanded
+	}
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		expand(&question.Condition, "question_id", question.Id)
+		expand(&question.TerminateIf, "question_id", question.Id)
+		expand(&question.Value, "question_id", question.Id)
+		for j := range question.Answers {
+			expand(&question.Answers[j].Condition, "question_id", question.Id)
+		}
+	}
+	for i := range q.Remarks {
+		expand(&q.Remarks[i].Condition, "remark_id", q.Remarks[i].Id)
+	}
+	for i := range q.Rules {
+		expand(&q.Rules[i].Condition, "rule_id", q.Rules[i].Id)
+	}
+	for _, name := range slices.Sorted(maps.Keys(q.Computed)) {
+		expression := q.Computed[name]
+		expand(&expression, "computed", name)
+		q.Computed[name] = expression
+	}
+	expand(&q.CompleteWhen, "setting", "complete_when")
+	return errors.Join(errs...)
+}
+
+// expandMacroReferences replaces the macro references of the expression with the macro expressions.
+func (q *questionnaire) expandMacroReferences(expression string) (string, error) {
+	references := macroReferences(expression)
+	if len(references) == 0 {
+		return expression, nil
+	}
+
+	var expanded []byte
+	last := 0
+	for _, ref := range references {
+		macro, ok := q.Macros[ref.name]
+		if !ok {
+			return "", fmt.Errorf("macro '%s' is not defined", ref.name)
+		}
+		expanded = append(expanded, expression[last:ref.start]...)
+		expanded = append(expanded, '(')
+		expanded = append(expanded, macro...)
+		expanded = append(expanded, ')')
+		last = ref.end
+	}
+	expanded = append(expanded, expression[last:]...)
+	return string(expanded), nil
+}
+
+// macroReference is the position of a macro reference in an expression.
+type macroReference struct {
+	name       string // Name of the referenced macro
+	start, end int    // Position of the reference, $ included
+}
+
+// macroReferences returns the macro references of the expression, outside of string literals.
+// $env, the expr variable holding the whole environment, isn't a macro reference.
+func macroReferences(expression string) []macroReference {
+	var references []macroReference
+	for i := 0; i < len(expression); i++ {
+		switch c := expression[i]; c {
+		case '"', '\'', '`':
+			// Skip the string literal, escaped quotes included
+			for i++; i < len(expression) && expression[i] != c; i++ {
+				if expression[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '$':
+			end := i + 1
+			for end < len(expression) && isIdentifierChar(expression[end]) {
+				end++
+			}
+			if name := expression[i+1 : end]; name != "" && name != "env" {
+				references = append(references, macroReference{name: name, start: i, end: end})
+			}
+			i = end - 1
+		}
+	}
+	return references
+}
@@ -0,0 +1,148 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageCatalog maps a locale to the end-user message of each validation error type.
+//
+// Messages can reference the error context with {key} placeholders, for example
+// "answer {answer} is out of range (valid: {valid_range})".
+//
+// Example usage:
+//
+//	catalog := gdq.MessageCatalog{
+//	    "de": {"invalid_answer_range": "Antwort {answer} ist ungültig (gültig: {valid_range})"},
+//	}
+//	message := catalog.Localize(err, "de")
+type MessageCatalog map[string]map[string]string
+
+// DefaultMessages is the built-in message catalog used by LocalizeError.
+// Applications can add locales or override messages before serving requests.
+var DefaultMessages = MessageCatalog{
+	"en": {
+		EmptyQuestionIDErrType:             "a question has no ID",
+		DuplicateQuestionIDErrType:         "question ID '{question_id}' is used more than once",
+		EmptyAnswersErrType:                "question '{question_id}' has no answer options",
+		DuplicateAnswerIDErrType:           "answer ID '{answer_id}' is used more than once in question '{question_id}'",
+		InvalidDefaultAnswerErrType:        "default answer {default} of question '{question_id}' is out of range (valid: {valid_range})",
+		InvalidTimeEstimateErrType:         "time estimate {time_estimate} of question '{question_id}' must not be negative",
+		InvalidWeightErrType:               "weight {weight} of question '{question_id}' must not be negative",
+		InvalidChoiceLimitsErrType:         "choice limits {min_choices}-{max_choices} of question '{question_id}' are invalid for its {answers} answers",
+		InvalidNumberBoundsErrType:         "bounds {min}-{max} of {type} question '{question_id}' are invalid",
+		InvalidConsentErrType:              "consent question '{question_id}' must be required, not skippable, without a default and offer at least 2 answers",
+		InvalidQuestionTypeErrType:         "question '{question_id}' has unknown type '{type}'",
+		InvalidQuestionIDErrType:           "question '{question_id}' does not exist",
+		InvalidAnswerIDErrType:             "answer '{answer_id}' does not exist for question '{question_id}'",
+		InvalidAnswerRangeErrType:          "answer {answer} is out of range for question '{question_id}' (valid: {valid_range})",
+		InvalidAnswerTypeErrType:           "answer {answer} doesn't match the type of question '{question_id}' ({question_type})",
+		UnavailableAnswerErrType:           "answer {answer} is not available for question '{question_id}'",
+		UnreachableAnswerErrType:           "question '{question_id}' wouldn't be shown given the other answers",
+		InvalidChoiceCountErrType:          "{count} answers chosen for question '{question_id}' (valid: {valid_range})",
+		ExclusiveAnswerErrType:             "answer {answer} of question '{question_id}' can't be combined with other answers",
+		InvalidDependencyErrType:           "question '{question_id}' depends on non-existent question '{invalid_dependency_id}'",
+		CircularDependencyErrType:          "circular dependency detected between questions {cycle}",
+		ConditionDependencyMismatchErrType: "question '{question_id}' conditions don't match its declared dependencies",
+		InvalidConditionErrType:            "condition '{condition}' is not a valid expression",
+		UnknownQuestionReferenceErrType:    "condition '{condition}' references non-existent question '{question_reference}'",
+		InvalidWhenRuleErrType:             "when rule is invalid: {error}",
+		InvalidJumpErrType:                 "jump from question '{question_id}' to '{next}' is invalid: {error}",
+		InvalidGroupErrType:                "group '{group_id}' is invalid: {error}",
+		InvalidQuotaErrType:                "quota '{quota_id}' is invalid: {error}",
+		InvalidAvailabilityErrType:         "availability window is invalid: {error}",
+		NotYetAvailableErrType:             "questionnaire is not available until {available_from}",
+		ExpiredQuestionnaireErrType:        "questionnaire expired on {available_until}",
+		InvalidOptionsSourceErrType:        "question '{question_id}' can't get its options from source '{options_from}': {error}",
+		ConditionEvaluationErrType:         "condition evaluation failed with answers {answers}",
+	},
+	"fr": {
+		EmptyQuestionIDErrType:             "une question n'a pas d'identifiant",
+		DuplicateQuestionIDErrType:         "l'identifiant de question '{question_id}' est utilisé plusieurs fois",
+		EmptyAnswersErrType:                "la question '{question_id}' n'a aucune réponse possible",
+		DuplicateAnswerIDErrType:           "l'identifiant de réponse '{answer_id}' est utilisé plusieurs fois dans la question '{question_id}'",
+		InvalidDefaultAnswerErrType:        "la réponse par défaut {default} de la question '{question_id}' est hors limites (valide : {valid_range})",
+		InvalidTimeEstimateErrType:         "l'estimation de durée {time_estimate} de la question '{question_id}' ne doit pas être négative",
+		InvalidWeightErrType:               "le poids {weight} de la question '{question_id}' ne doit pas être négatif",
+		InvalidChoiceLimitsErrType:         "les limites de choix {min_choices}-{max_choices} de la question '{question_id}' sont invalides pour ses {answers} réponses",
+		InvalidNumberBoundsErrType:         "les bornes {min}-{max} de la question {type} '{question_id}' sont invalides",
+		InvalidConsentErrType:              "la question de consentement '{question_id}' doit être obligatoire, non passable, sans réponse par défaut et proposer au moins 2 réponses",
+		InvalidQuestionTypeErrType:         "la question '{question_id}' a le type inconnu '{type}'",
+		InvalidQuestionIDErrType:           "la question '{question_id}' n'existe pas",
+		InvalidAnswerIDErrType:             "la réponse '{answer_id}' n'existe pas pour la question '{question_id}'",
+		InvalidAnswerRangeErrType:          "la réponse {answer} est hors limites pour la question '{question_id}' (valide : {valid_range})",
+		InvalidAnswerTypeErrType:           "la réponse {answer} ne correspond pas au type de la question '{question_id}' ({question_type})",
+		UnavailableAnswerErrType:           "la réponse {answer} n'est pas disponible pour la question '{question_id}'",
+		UnreachableAnswerErrType:           "la question '{question_id}' ne serait pas affichée compte tenu des autres réponses",
+		InvalidChoiceCountErrType:          "{count} réponses choisies pour la question '{question_id}' (valide : {valid_range})",
+		ExclusiveAnswerErrType:             "la réponse {answer} de la question '{question_id}' ne peut pas être combinée avec d'autres réponses",
+		InvalidDependencyErrType:           "la question '{question_id}' dépend de la question inexistante '{invalid_dependency_id}'",
+		CircularDependencyErrType:          "dépendance circulaire détectée entre les questions {cycle}",
+		ConditionDependencyMismatchErrType: "les conditions de la question '{question_id}' ne correspondent pas à ses dépendances déclarées",
+		InvalidConditionErrType:            "la condition '{condition}' n'est pas une expression valide",
+		UnknownQuestionReferenceErrType:    "la condition '{condition}' fait référence à la question inexistante '{question_reference}'",
+		InvalidWhenRuleErrType:             "la règle when est invalide : {error}",
+		InvalidJumpErrType:                 "le saut de la question '{question_id}' vers '{next}' est invalide : {error}",
+		InvalidGroupErrType:                "le groupe '{group_id}' est invalide : {error}",
+		InvalidQuotaErrType:                "le quota '{quota_id}' est invalide : {error}",
+		InvalidAvailabilityErrType:         "la période de disponibilité est invalide : {error}",
+		NotYetAvailableErrType:             "le questionnaire n'est pas disponible avant {available_from}",
+		ExpiredQuestionnaireErrType:        "le questionnaire a expiré le {available_until}",
+		InvalidOptionsSourceErrType:        "la question '{question_id}' ne peut pas obtenir ses options de la source '{options_from}' : {error}",
+		ConditionEvaluationErrType:         "l'évaluation d'une condition a échoué avec les réponses {answers}",
+	},
+}
+
+// LocalizeError renders a validation error in the requested locale using DefaultMessages.
+// See MessageCatalog.Localize for details.
+func LocalizeError(err error, locale string) string {
+	return DefaultMessages.Localize(err, locale)
+}
+
+// Localize renders a validation error in the requested locale.
+//
+// The message is looked up for the requested locale, then its base language
+// (e.g. "fr" for "fr-CA"), then English. Placeholders are replaced with the error context.
+// When err aggregates several validation errors (as returned by New), each of them is
+// rendered on its own line.
+// When err does not wrap a validation error, or no message exists for its type,
+// the original error message is returned.
+func (c MessageCatalog) Localize(err error, locale string) string {
+	validationErrs := ValidationErrors(err)
+	if len(validationErrs) == 0 {
+		return err.Error()
+	}
+
+	messages := make([]string, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		messages[i] = c.localize(validationErr, locale)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// localize renders a single validation error in the requested locale.
+func (c MessageCatalog) localize(validationErr ValidationError, locale string) string {
+	translations := localizedText{}
+	for l, messages := range c {
+		if message, ok := messages[validationErr.Type]; ok {
+			translations[l] = message
+		}
+	}
+	if len(translations) == 0 {
+		return validationErr.Message
+	}
+
+	message := translations.resolve(locale, "en")
+	for key, value := range validationErr.Context {
+		message = strings.ReplaceAll(message, "{"+key+"}", formatContextValue(value))
+	}
+	return message
+}
+
+// formatContextValue formats an error context value for end-user messages.
+func formatContextValue(value interface{}) string {
+	if values, ok := value.([]string); ok {
+		return strings.Join(values, " -> ")
+	}
+	return fmt.Sprint(value)
+}
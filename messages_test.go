@@ -0,0 +1,56 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Messages", func() {
+	Describe("MessageCatalog.Localize", func() {
+		q := &question{Id: "color", Answers: []answerOption{{}, {}, {}}}
+
+		It("should render the message in the requested locale", func() {
+			err := invalidAnswerRangeError(q, 5)
+			Expect(LocalizeError(err, "en")).To(Equal("answer 5 is out of range for question 'color' (valid: 1-3)"))
+			Expect(LocalizeError(err, "fr")).To(Equal("la réponse 5 est hors limites pour la question 'color' (valide : 1-3)"))
+		})
+
+		It("should fall back to the base language then to English", func() {
+			err := invalidQuestionIDError("q4", 1)
+			Expect(LocalizeError(err, "fr-CA")).To(Equal("la question 'q4' n'existe pas"))
+			Expect(LocalizeError(err, "ja")).To(Equal("question 'q4' does not exist"))
+		})
+
+		It("should find validation errors in wrapped errors", func() {
+			err := fmt.Errorf("invalid answers provided: %w", invalidQuestionIDError("q4", 1))
+			Expect(LocalizeError(err, "en")).To(Equal("question 'q4' does not exist"))
+		})
+
+		It("should render each aggregated validation error", func() {
+			err := fmt.Errorf("questionnaire validation failed: %w", errors.Join(invalidQuestionIDError("q4", 1), emptyAnswersError("q5")))
+			Expect(LocalizeError(err, "fr")).To(Equal("la question 'q4' n'existe pas\nla question 'q5' n'a aucune réponse possible"))
+		})
+
+		It("should format list context values", func() {
+			err := circularDependencyError([]string{"q1", "q2", "q1"})
+			Expect(LocalizeError(err, "en")).To(Equal("circular dependency detected between questions q1 -> q2 -> q1"))
+		})
+
+		It("should use custom catalogs", func() {
+			catalog := MessageCatalog{"de": {InvalidQuestionIDErrType: "Frage '{question_id}' existiert nicht"}}
+			Expect(catalog.Localize(invalidQuestionIDError("q4", 1), "de")).To(Equal("Frage 'q4' existiert nicht"))
+		})
+
+		It("should return the original message for unknown error types", func() {
+			err := ValidationError{Type: "unknown", Message: "something went wrong"}
+			Expect(LocalizeError(err, "fr")).To(Equal("something went wrong"))
+		})
+
+		It("should return the error message for other errors", func() {
+			Expect(LocalizeError(errors.New("boom"), "fr")).To(Equal("boom"))
+		})
+	})
+})
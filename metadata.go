@@ -0,0 +1,64 @@
+package go_dynamic_questionnaire
+
+import (
+	"maps"
+	"time"
+)
+
+type (
+	// AnswerMetadata describes how an answer was given (see WithAnswerMetadata).
+	// Metadata is carried along with the answers, into the completion hook and the completed response,
+	// but never affects the questionnaire logic: conditions can't access it.
+	//
+	// Example JSON representation:
+	//
+	//	{
+	//	  "answered_at": "2025-03-01T10:15:00Z",
+	//	  "time_spent_ms": 4200,
+	//	  "client": {"platform": "ios", "version": "2.3.0"}
+	//	}
+	AnswerMetadata struct {
+		AnsweredAt  time.Time         `json:"answered_at,omitzero"`    // When the answer was given
+		TimeSpentMs int64             `json:"time_spent_ms,omitempty"` // Time spent answering the question, in milliseconds
+		Client      map[string]string `json:"client,omitempty"`        // Information about the client used to answer (platform, version...)
+	}
+
+	// Completion describes a completed questionnaire, as passed to the completion hook (see WithCompletionHook).
+	Completion struct {
+		Answers    map[string]int            // The answers completing the questionnaire, including defaulted answers
+		Values     map[string]interface{}    // The answers to the questions that aren't single choice (see NextAnswers)
+		Computed   map[string]interface{}    // The values of the computed block
+		Metadata   map[string]AnswerMetadata // The metadata of the answers (see WithAnswerMetadata)
+		Score      float64                   // Sum of the scores of the chosen answers
+		Terminated bool                      // Whether the questionnaire ended early
+		Quotas     []string                  // The quotas the chosen answer options are counted toward, to increment when the respondent qualifies
+		QuotaFull  string                    // The full quota that screened the respondent out, empty when the respondent qualifies (see WithQuotaChecker)
+	}
+)
+
+// answeredMetadata returns the metadata of the answered questions, nil when there is none.
+func answeredMetadata(answers map[string]int, metadata map[string]AnswerMetadata) map[string]AnswerMetadata {
+	var answered map[string]AnswerMetadata
+	for id, meta := range metadata {
+		if _, ok := answers[id]; !ok {
+			continue
+		}
+		if answered == nil {
+			answered = make(map[string]AnswerMetadata, len(metadata))
+		}
+		answered[id] = meta
+	}
+	return answered
+}
+
+// complete calls the completion hook, if any, with the completed questionnaire.
+// The answers and values are cloned, so that the hook can keep them.
+func (q *questionnaire) complete(completion Completion) {
+	if q.completionHook == nil {
+		return
+	}
+	completion.Answers = maps.Clone(withoutSensitive(q, completion.Answers))
+	completion.Values = maps.Clone(withoutSensitive(q, completion.Values))
+	completion.Metadata = withoutSensitive(q, completion.Metadata)
+	q.completionHook(completion)
+}
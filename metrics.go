@@ -0,0 +1,45 @@
+package go_dynamic_questionnaire
+
+import "time"
+
+// Metrics receives usage measurements of a questionnaire (see WithMetrics),
+// so that they can be exported to a monitoring system such as Prometheus.
+//
+// Implementations must be safe for concurrent use, as a questionnaire is shared between goroutines.
+// Use one Metrics per questionnaire to monitor them separately, for instance by currying
+// Prometheus vectors with a questionnaire label:
+//
+//	type promMetrics struct {
+//	    next, completions prometheus.Counter
+//	    errors            *prometheus.CounterVec
+//	    evaluations       prometheus.Observer
+//	}
+//
+//	func (m promMetrics) NextCalled()                        { m.next.Inc() }
+//	func (m promMetrics) Completed()                         { m.completions.Inc() }
+//	func (m promMetrics) ValidationFailed(errType string)    { m.errors.WithLabelValues(errType).Inc() }
+//	func (m promMetrics) ConditionEvaluated(d time.Duration) { m.evaluations.Observe(d.Seconds()) }
+type Metrics interface {
+	// NextCalled is called on every Next call.
+	NextCalled()
+
+	// Completed is called when Next returns a completed response.
+	Completed()
+
+	// ValidationFailed is called for every validation error, with its type (e.g. InvalidAnswerRangeErrType):
+	// errors in the configuration passed to New, and in the answers passed to Next.
+	ValidationFailed(errType string)
+
+	// ConditionEvaluated is called after every condition evaluation, with its duration.
+	ConditionEvaluated(duration time.Duration)
+}
+
+// recordValidationErrors reports the type of every validation error of err to the metrics, if any.
+func (q *questionnaire) recordValidationErrors(err error) {
+	if q.metrics == nil {
+		return
+	}
+	for _, validationErr := range ValidationErrors(err) {
+		q.metrics.ValidationFailed(validationErr.Type)
+	}
+}
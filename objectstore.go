@@ -0,0 +1,67 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ObjectStore reads the objects of an object storage service, such as Amazon S3 or Google Cloud Storage,
+// so that questionnaires published to a bucket can be loaded from their URI (see WithObjectStore).
+//
+// It adapts the SDK of the service, which the package doesn't depend on:
+//
+//	type s3Store struct{ client *s3.Client }
+//
+//	func (s s3Store) Object(bucket, key string) (io.ReadCloser, error) {
+//	    out, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return out.Body, nil
+//	}
+//
+//	type gcsStore struct{ client *storage.Client }
+//
+//	func (s gcsStore) Object(bucket, key string) (io.ReadCloser, error) {
+//	    return s.client.Bucket(bucket).Object(key).NewReader(context.TODO())
+//	}
+type ObjectStore interface {
+	// Object returns the content of the object stored under the key in the bucket.
+	// The returned reader is closed once the content is read.
+	Object(bucket, key string) (io.ReadCloser, error)
+}
+
+// readConfig reads the configuration at the path: a file path, or the URI of an object
+// (e.g. s3://bucket/questionnaire.yaml) read from the object store registered for its scheme.
+func (q *questionnaire) readConfig(path string) ([]byte, error) {
+	scheme, location, isURI := strings.Cut(path, "://")
+	if !isURI {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %q: %w", path, err)
+		}
+		return content, nil
+	}
+
+	store, ok := q.objectStores[scheme]
+	if !ok {
+		return nil, fmt.Errorf("failed to read object %q: no object store registered for %s:// URIs (see WithObjectStore)", path, scheme)
+	}
+	bucket, key, _ := strings.Cut(location, "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("failed to read object %q: expected %s://bucket/key", path, scheme)
+	}
+
+	object, err := store.Object(bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %q: %w", path, err)
+	}
+	defer object.Close()
+	content, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %q: %w", path, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,507 @@
+package go_dynamic_questionnaire
+
+import (
+	"crypto/ed25519"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+type (
+	// Option configures a questionnaire created with New.
+	//
+	// Example usage:
+	//   q, err := gdq.New("questionnaire.yaml", gdq.WithFunctions(functions))
+	Option func(*questionnaire)
+
+	// NextOption configures a single call to Questionnaire.Next.
+	// Options are applied in order, so later options override earlier ones.
+	//
+	// Example usage:
+	//   response, err := q.Next(answers, gdq.WithSeed(respondentSeed))
+	NextOption func(*nextOptions)
+
+	// ContentTransform rewrites the configuration content before it is parsed (see WithContentTransform).
+	ContentTransform func(content []byte) ([]byte, error)
+
+	// nextOptions holds the settings collected from the NextOption values passed to Next.
+	nextOptions struct {
+		seed          uint64            // Seed used to shuffle questions and answers
+		applyDefaults bool              // Whether unanswered questions are filled with their default answer
+		locale        string            // Locale in which texts are returned
+		defaultLocale string            // Locale used when a text is not translated in the requested locale
+		pageSize      int               // Maximum number of questions returned (0 returns every eligible question)
+		explain       bool              // Whether the response explains why each question is shown or hidden
+		checksum      bool              // Whether the response includes the checksum of the questionnaire
+		lenient       bool              // Whether answers to unknown questions and out-of-range answers are ignored (see WithLenientAnswers)
+		allErrors     bool              // Whether every invalid answer is reported rather than the first one found
+		staleAnswers  StaleAnswerPolicy // What to do with the answers of the questions that wouldn't be shown anymore
+		tags          []string          // Tags restricting the questions returned (see WithTags)
+		dataProvider  DataProvider      // Provider of the answer options of the questions declaring an options_from source
+
+		metadata map[string]AnswerMetadata // Metadata of the answers (see WithAnswerMetadata)
+		context  map[string]Answer         // Answers of the hidden questions, from the application context
+		ignored  []ValidationError         // Answers already left out by NextAnswers in lenient mode
+	}
+)
+
+// WithFunctions registers custom functions usable inside question, answer and remark conditions.
+// Functions must not use the names of the built-in helpers (answers, skipped, answerText...).
+//
+// Example usage:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithFunctions(map[string]interface{}{
+//	    "isAdult": func(age int) bool { return age >= 18 },
+//	}))
+//
+// The function can then be used in conditions:
+//
+//	condition: 'isAdult(answers["age"])'
+func WithFunctions(functions map[string]interface{}) Option {
+	return func(q *questionnaire) {
+		if q.functions == nil {
+			q.functions = make(map[string]interface{}, len(functions))
+		}
+		for name, function := range functions {
+			q.functions[name] = function
+		}
+	}
+}
+
+// WithMaxExpressionLength limits the length, in bytes, of every condition of the questionnaire.
+// New fails with an invalid_condition error when a condition is longer than the limit.
+//
+// This guards servers loading untrusted questionnaire definitions against oversized expressions.
+// A limit of 0 disables the check.
+func WithMaxExpressionLength(length int) Option {
+	return func(q *questionnaire) {
+		q.maxExpressionLength = length
+	}
+}
+
+// WithMaxExpressionNodes limits the size of every condition of the questionnaire, in nodes of its syntax tree.
+// New fails with an invalid_condition error when a condition has more nodes than the limit.
+//
+// Unlike WithMaxExpressionLength, this also bounds conditions that are short but expand into a lot
+// of work. A limit of 0 keeps expr's default of 10000 nodes.
+func WithMaxExpressionNodes(nodes uint) Option {
+	return func(q *questionnaire) {
+		q.maxExpressionNodes = nodes
+	}
+}
+
+// WithEvaluationMemoryBudget limits the memory a single condition evaluation may allocate,
+// in units of expr's VM (roughly one per element of the ranges, arrays and maps the condition builds).
+// Next returns an error when a condition exceeds the budget, e.g. 'len(1..100000000) > 0'.
+//
+// The expr VM can't be interrupted: along with WithMaxExpressionNodes, the budget bounds the work of every
+// evaluation, so that untrusted definitions can't hang Next. Custom functions (see WithFunctions) must bound their own work.
+// A budget of 0 keeps expr's default of 1000000.
+func WithEvaluationMemoryBudget(budget uint) Option {
+	return func(q *questionnaire) {
+		q.evaluationMemoryBudget = budget
+	}
+}
+
+// WithMaxEvaluationDepth limits the work Next does on a single call: the number of rounds of the evaluations
+// repeated until the answers settle (applying defaults that unlock further defaults, detecting stale answers)
+// and the length of the dependency chains followed to estimate the progress.
+// Next returns an error wrapping ErrEvaluationDepthExceeded when a limit is reached.
+//
+// Validation rules out circular dependencies, but not every pathological configuration,
+// such as a very long chain of questions: this guards servers loading untrusted questionnaire definitions.
+// A depth of 0 disables the limit.
+func WithMaxEvaluationDepth(depth int) Option {
+	return func(q *questionnaire) {
+		q.maxEvaluationDepth = depth
+	}
+}
+
+// WithDisallowedBuiltins prevents conditions from using the given expr builtins
+// (e.g. "repeat", "sort" or "split").
+// New fails with an invalid_condition error when a condition calls one of them.
+//
+// Example usage:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithDisallowedBuiltins("repeat", "split"))
+func WithDisallowedBuiltins(names ...string) Option {
+	return func(q *questionnaire) {
+		q.disallowedBuiltins = append(q.disallowedBuiltins, names...)
+	}
+}
+
+// WithStrictValidation makes New reject conditions that would only fail when the questionnaire is used:
+//   - conditions that don't type-check as a boolean expression (e.g. 'answers["q1"] + 1')
+//   - conditions referencing question IDs that don't exist, including closing remark conditions
+//
+// Without strict validation, these conditions are reported by Next when they are evaluated.
+func WithStrictValidation() Option {
+	return func(q *questionnaire) {
+		q.strictValidation = true
+	}
+}
+
+// WithLogger makes the questionnaire emit debug logs to the logger:
+// loading, validation errors and warnings, and the outcome of every condition evaluation.
+// This helps diagnosing production questionnaires; nothing is logged without a logger.
+//
+// Example usage:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithLogger(slog.Default().With("questionnaire", "onboarding")))
+func WithLogger(logger *slog.Logger) Option {
+	return func(q *questionnaire) {
+		q.logger = logger
+	}
+}
+
+// WithMetrics reports usage measurements of the questionnaire to the metrics:
+// Next calls, completions, validation errors by type and condition evaluation durations.
+// Nothing is measured without metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(q *questionnaire) {
+		q.metrics = metrics
+	}
+}
+
+// WithCompletionHook registers a function called every time Next returns a completed response,
+// for instance to store the completed questionnaire or notify another system.
+// The hook is called synchronously, before Next returns: it must not block for long.
+//
+// Example usage:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithCompletionHook(func(c gdq.Completion) {
+//	    log.Printf("completed with score %v", c.Score)
+//	}))
+func WithCompletionHook(hook func(Completion)) Option {
+	return func(q *questionnaire) {
+		q.completionHook = hook
+	}
+}
+
+// WithParams renders the texts and expressions of the configuration as text/templates, with the params available as .params,
+// so that the same definition can be customized per tenant:
+//
+//	questions:
+//	  - id: "recommend"
+//	    text: "Would you recommend {{ .params.company_name }}?"
+//	    answers: ["Yes", "No"]
+//	closing_remarks:
+//	  - id: "discount"
+//	    text: "Enjoy {{ .params.discount }}% off your next order!"
+//	    condition: 'answers["recommend"] == 1 && {{ .params.discount }} > 0'
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithParams(map[string]interface{}{"company_name": "Acme", "discount": 10}))
+//
+// The configuration is rendered once parsed, so that values can't change its structure: texts receive the values
+// as written, and expressions receive them as expr literals, with strings quoted (e.g. 'answers["plan"] == {{ .params.plan }}').
+// New fails when the configuration references a missing param.
+// Without WithParams, the configuration isn't rendered, so {{ and }} are kept as written.
+func WithParams(params map[string]interface{}) Option {
+	return func(q *questionnaire) {
+		q.params = params
+		if q.params == nil {
+			q.params = map[string]interface{}{}
+		}
+	}
+}
+
+// WithEnvExpansion replaces the ${VAR} references of the configuration with the value of the environment variables
+// before parsing it, so that URLs, thresholds or feature toggles differ across environments without separate files:
+//
+//	computed:
+//	  high_risk: 'score >= ${RISK_THRESHOLD:-10}'
+//
+// ${VAR:-default} falls back to the default when the variable isn't set, and New fails when a variable without default isn't set.
+// Only the ${VAR} form is expanded: $name macro references are kept.
+func WithEnvExpansion() Option {
+	return func(q *questionnaire) {
+		q.expandEnv = true
+	}
+}
+
+// WithObjectStore registers the object store reading the configurations given as URIs with the scheme,
+// such as s3://bucket/key or gs://bucket/key, e.g. questionnaires published to a bucket by a CMS pipeline:
+//
+//	q, err := gdq.New("s3://surveys/onboarding.yaml", gdq.WithObjectStore("s3", s3Store{client}))
+//
+// The format of the configuration is detected from the extension of the key, as for file paths.
+func WithObjectStore(scheme string, store ObjectStore) Option {
+	return func(q *questionnaire) {
+		if q.objectStores == nil {
+			q.objectStores = make(map[string]ObjectStore)
+		}
+		q.objectStores[scheme] = store
+	}
+}
+
+// WithSignature makes New verify the detached Ed25519 signature of the configuration content before parsing it,
+// so that only the definitions signed by a trusted author are executed, e.g. in multi-tenant deployments:
+//
+//	signature, err := os.ReadFile("questionnaire.yaml.sig")
+//	if err != nil {
+//	    return err
+//	}
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithSignature(trustedKey, signature))
+//
+// The signature covers the content exactly as read, before environment variables and params are expanded.
+// New fails when the signature doesn't match.
+func WithSignature(publicKey ed25519.PublicKey, value []byte) Option {
+	return func(q *questionnaire) {
+		q.signature = &signature{publicKey: publicKey, value: value}
+	}
+}
+
+// WithContentTransform registers a function rewriting the configuration content before it is parsed,
+// for instance to decrypt SOPS or age encrypted questionnaires transparently:
+//
+//	q, err := gdq.New("questionnaire.enc.yaml", gdq.WithContentTransform(func(content []byte) ([]byte, error) {
+//	    return decrypt.Data(content, "yaml")
+//	}))
+//
+// Transforms are applied in registration order, after the signature is verified (see WithSignature)
+// and before environment variables and params are expanded. The format is still detected from the file
+// extension, or from the content as given to New.
+func WithContentTransform(transform ContentTransform) Option {
+	return func(q *questionnaire) {
+		q.transforms = append(q.transforms, transform)
+	}
+}
+
+// WithAuditSink records every Next call to the sink: the hash of the answers, the questions and closing remarks returned,
+// and every condition evaluated with its result, so that each decision of a compliance questionnaire can be explained later:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithAuditSink(dbSink{db}))
+//
+// Failed calls are recorded too, with their error. The sink is called synchronously, before Next returns,
+// and Next fails when the record can't be stored. WhatIf previews aren't recorded.
+func WithAuditSink(sink AuditSink) Option {
+	return func(q *questionnaire) {
+		q.auditSink = sink
+	}
+}
+
+// WithSensitiveAnswers sets what to do with the answers to the questions marked as sensitive, such as personal data,
+// in the summaries, reviews, completion hook and audit trail (see SensitiveAnswerPolicy):
+//
+//	questions:
+//	  - id: "email"
+//	    text: "What is your email address?"
+//	    type: "text"
+//	    sensitive: true
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithSensitiveAnswers(gdq.RedactSensitiveAnswers))
+//
+// The responses of Next are never redacted, as they are returned to the respondent.
+func WithSensitiveAnswers(policy SensitiveAnswerPolicy) Option {
+	return func(q *questionnaire) {
+		q.sensitiveAnswers = policy
+	}
+}
+
+// WithQuotaChecker enforces the quotas of the questionnaire with the counts of the checker:
+// once a quota is full, the respondents choosing an answer option counted toward it are screened out.
+// Next then ends the questionnaire early, with the remark of the quota as the only closing remark,
+// and reports the quota in Response.QuotaFull.
+//
+//	q, err := gdq.New("questionnaire.yaml",
+//	    gdq.WithQuotaChecker(counter),
+//	    gdq.WithCompletionHook(func(c gdq.Completion) {
+//	        if c.QuotaFull == "" {
+//	            counter.Increment(c.Quotas...)
+//	        }
+//	    }),
+//	)
+//
+// Quotas are checked on every Next call; they aren't enforced without a checker.
+func WithQuotaChecker(checker QuotaChecker) Option {
+	return func(q *questionnaire) {
+		q.quotaChecker = checker
+	}
+}
+
+// WithClock sets the function returning the current time, used to enforce the availability window of the questionnaire
+// (available_from and available_until) and to date the audit records. Defaults to time.Now.
+//
+// Example usage:
+//
+//	q, err := gdq.New("questionnaire.yaml", gdq.WithClock(func() time.Time { return fixedTime }))
+func WithClock(now func() time.Time) Option {
+	return func(q *questionnaire) {
+		q.clock = now
+	}
+}
+
+// WithSeed sets the seed used to randomize the order of questions and answers
+// when the questionnaire enables shuffling.
+//
+// Passing the same seed on every call (for instance a hash of the respondent ID)
+// guarantees that a respondent sees the questions in a stable order across Next calls.
+// Without a seed, a random one is drawn on every call.
+func WithSeed(seed uint64) NextOption {
+	return func(o *nextOptions) {
+		o.seed = seed
+	}
+}
+
+// WithDefaults makes Next answer unanswered questions with their configured default,
+// as if the user had selected it. This is useful for pre-populated forms.
+//
+// The defaulted answers are reported in Response.Defaulted so that clients can
+// store them alongside the answers provided by the user.
+func WithDefaults() NextOption {
+	return func(o *nextOptions) {
+		o.applyDefaults = true
+	}
+}
+
+// WithLocale sets the locale in which question, answer and remark texts are returned.
+//
+// When a text is not translated in the requested locale, its base language is tried
+// (e.g. "fr" for "fr-CA"), then the questionnaire default_locale, then the untranslated text.
+func WithLocale(locale string) NextOption {
+	return func(o *nextOptions) {
+		o.locale = locale
+	}
+}
+
+// WithPageSize limits the number of questions returned by Next.
+// By default, Next returns every question currently eligible, which suits forms showing them together;
+// WithPageSize(1) returns one question at a time, as expected by chat-like interfaces.
+//
+// The first eligible questions are returned, in evaluation order.
+// Completion and progress still account for every eligible question.
+// A size of zero or less returns every eligible question.
+func WithPageSize(size int) NextOption {
+	return func(o *nextOptions) {
+		o.pageSize = size
+	}
+}
+
+// WithExplain makes Next report, in Response.Explanations, why each question is shown or hidden:
+// the evaluated condition and its result, or the missing dependencies.
+// This is meant for debugging questionnaires ("my question never appears"): conditions are evaluated again.
+func WithExplain() NextOption {
+	return func(o *nextOptions) {
+		o.explain = true
+	}
+}
+
+// WithChecksum includes the checksum of the questionnaire (see Questionnaire.Checksum) in Response.Checksum,
+// so that clients can detect that the questionnaire definition changed mid-session and restart gracefully.
+func WithChecksum() NextOption {
+	return func(o *nextOptions) {
+		o.checksum = true
+	}
+}
+
+// WithAnswerMetadata attaches metadata to the answers passed to Next, keyed by question ID.
+// Metadata of unanswered questions is ignored.
+//
+// The metadata is returned in Response.Metadata once the questionnaire is completed
+// and passed to the completion hook (see WithCompletionHook).
+//
+// Example usage:
+//
+//	response, err := q.Next(answers, gdq.WithAnswerMetadata(map[string]gdq.AnswerMetadata{
+//	    "q1": {AnsweredAt: answeredAt, TimeSpentMs: 4200},
+//	}))
+func WithAnswerMetadata(metadata map[string]AnswerMetadata) NextOption {
+	return func(o *nextOptions) {
+		o.metadata = metadata
+	}
+}
+
+// WithLenientAnswers makes Next ignore, rather than fail on, answers to unknown questions and out-of-range answers.
+// This suits clients accumulating answers across questionnaire versions, where questions and options may have been removed.
+//
+// The ignored answers are reported in Response.Ignored, with the validation error they would have caused.
+// Other invalid answers (e.g. unavailable options or answers not matching the question type) are still rejected.
+func WithLenientAnswers() NextOption {
+	return func(o *nextOptions) {
+		o.lenient = true
+	}
+}
+
+// WithAllAnswerErrors makes Next report every invalid answer rather than the first one found.
+// The validation errors are aggregated with errors.Join, ordered by question ID (see ValidationErrors),
+// so that forms can flag every invalid field at once.
+//
+// Without this option, which invalid answer is reported is unspecified when there are several.
+func WithAllAnswerErrors() NextOption {
+	return func(o *nextOptions) {
+		o.allErrors = true
+	}
+}
+
+// WithStaleAnswers sets what Next does with stale answers: answers to questions that wouldn't be shown anymore
+// given the other answers, because an earlier answer changed since they were given.
+// For instance, with q2 only shown when q1 is "Yes", the answer to q2 becomes stale when q1 is changed to "No".
+//
+// FlagStaleAnswers reports them in Response.Stale; StripStaleAnswers also leaves them out,
+// so that they count neither in the progress nor in the score, and don't unlock further questions.
+func WithStaleAnswers(policy StaleAnswerPolicy) NextOption {
+	return func(o *nextOptions) {
+		o.staleAnswers = policy
+	}
+}
+
+// WithContextAnswers answers hidden questions from the application context (e.g. the user's plan or country).
+// Hidden questions are never returned by Next: they are answered from the context or with their value expression,
+// and take part in conditions and dependencies like any other question.
+//
+// Example usage:
+//
+//	response, err := q.Next(answers, gdq.WithContextAnswers(map[string]gdq.Answer{
+//	    "plan":    gdq.Choice(2),
+//	    "country": gdq.Text("FR"),
+//	}))
+//
+// Context answers to questions that aren't hidden are ignored; invalid context answers are rejected like other answers.
+func WithContextAnswers(answers map[string]Answer) NextOption {
+	return func(o *nextOptions) {
+		o.context = answers
+	}
+}
+
+// WithTags restricts Next to the questions tagged with at least one of the tags, so that a single questionnaire
+// can power several partial flows (e.g. WithTags("onboarding")). Other questions are never returned,
+// and count neither in the progress nor in the remaining time; their answers are still used by conditions.
+// The questionnaire is completed once every required question with one of the tags is answered.
+//
+// Example usage:
+//
+//	response, err := q.Next(answers, gdq.WithTags("onboarding"))
+func WithTags(tags ...string) NextOption {
+	return func(o *nextOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// newNextOptions builds the nextOptions from the provided NextOption values.
+// The defaultLocale comes from the questionnaire configuration.
+func newNextOptions(opts []NextOption, defaultLocale string) *nextOptions {
+	o := &nextOptions{seed: rand.Uint64(), locale: defaultLocale, defaultLocale: defaultLocale}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// translate returns the text in the requested locale, falling back to the default locale.
+func (o *nextOptions) translate(text localizedText) string {
+	return text.resolve(o.locale, o.defaultLocale)
+}
+
+// WithDataProvider sets the provider of the answer options of the questions declaring an options_from source,
+// for instance the projects of the user making the call, fetched from a database:
+//
+//	response, err := q.Next(answers, gdq.WithDataProvider(gdq.DataProviderFunc(func(source string) ([]gdq.DataOption, error) {
+//	    return store.Options(ctx, userID, source)
+//	})))
+//
+// The provider is called at most once per source and call. Calls to questionnaires with such questions fail without a provider.
+func WithDataProvider(provider DataProvider) NextOption {
+	return func(o *nextOptions) {
+		o.dataProvider = provider
+	}
+}
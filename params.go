@@ -0,0 +1,132 @@
+package go_dynamic_questionnaire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// renderParams renders the {{ .params.name }} variables of the texts and expressions of the configuration
+// (see WithParams), or leaves them untouched when no params were set.
+//
+// The configuration is rendered once parsed, field by field, so that a value can't change its structure:
+// texts receive the values as written, and expressions receive them as expr literals (e.g. quoted strings).
+func (q *questionnaire) renderParams() error {
+	if q.params == nil {
+		return nil
+	}
+
+	literals := make(map[string]interface{}, len(q.params))
+	for name, value := range q.params {
+		literal, err := exprLiteral(value)
+		if err != nil {
+			return fmt.Errorf("failed to render param '%s': %w", name, err)
+		}
+		literals[name] = literal
+	}
+	r := &paramsRenderer{
+		texts:       map[string]interface{}{"params": q.params},
+		expressions: map[string]interface{}{"params": literals},
+	}
+
+	for i := range q.QuestionList {
+		r.question(&q.QuestionList[i])
+	}
+	for i := range q.Groups {
+		for j := range q.Groups[i].Questions {
+			r.question(&q.Groups[i].Questions[j])
+		}
+	}
+	for i := range q.Remarks {
+		q.Remarks[i].Text = r.localized(q.Remarks[i].Text)
+		q.Remarks[i].Condition = r.expression(q.Remarks[i].Condition)
+	}
+	for i := range q.Rules {
+		q.Rules[i].Condition = r.expression(q.Rules[i].Condition)
+		q.Rules[i].Message = r.localized(q.Rules[i].Message)
+	}
+	for i := range q.Quotas {
+		q.Quotas[i].Remark = r.localized(q.Quotas[i].Remark)
+	}
+	q.CompleteWhen = r.expression(q.CompleteWhen)
+	for name, expression := range q.Computed {
+		q.Computed[name] = r.expression(expression)
+	}
+	for name, expression := range q.Macros {
+		q.Macros[name] = r.expression(expression)
+	}
+
+	return r.err
+}
+
+// paramsRenderer renders the params in the fields of a configuration, keeping the first error.
+type paramsRenderer struct {
+	texts       map[string]interface{} // Template data of the texts, holding the params as written
+	expressions map[string]interface{} // Template data of the expressions, holding the params as expr literals
+	err         error
+}
+
+// question renders the params in the texts and expressions of a question and its answer options.
+func (r *paramsRenderer) question(question *question) {
+	question.Text = r.localized(question.Text)
+	question.Description = r.localized(question.Description)
+	question.Help = r.localized(question.Help)
+	question.Condition = r.expression(question.Condition)
+	question.TerminateIf = r.expression(question.TerminateIf)
+	question.Value = r.expression(question.Value)
+	for i := range question.Answers {
+		question.Answers[i].Text = r.localized(question.Answers[i].Text)
+		question.Answers[i].Condition = r.expression(question.Answers[i].Condition)
+	}
+}
+
+// localized renders the params in every translation of a text.
+func (r *paramsRenderer) localized(text localizedText) localizedText {
+	if text == nil {
+		return nil
+	}
+	rendered := make(localizedText, len(text))
+	for locale, translation := range text {
+		rendered[locale] = r.render(translation, r.texts)
+	}
+	return rendered
+}
+
+// expression renders the params in an expression, as expr literals.
+func (r *paramsRenderer) expression(expression string) string {
+	return r.render(expression, r.expressions)
+}
+
+// render executes a field as a text/template, returning it untouched when it has no variables or after an error.
+func (r *paramsRenderer) render(field string, data map[string]interface{}) string {
+	if r.err != nil || !strings.Contains(field, "{{") {
+		return field
+	}
+
+	tmpl, err := template.New("questionnaire").Option("missingkey=error").Parse(field)
+	if err != nil {
+		r.err = fmt.Errorf("failed to parse params: %w", err)
+		return field
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		r.err = fmt.Errorf("failed to render params: %w", err)
+		return field
+	}
+	return rendered.String()
+}
+
+// exprLiteral formats a param as an expr literal: strings are quoted and escaped,
+// other values are formatted as JSON, which expr parses as numbers, booleans, nil, arrays and maps.
+func exprLiteral(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s), nil
+	}
+	literal, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(literal), nil
+}
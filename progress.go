@@ -0,0 +1,273 @@
+package go_dynamic_questionnaire
+
+import "slices"
+
+// selector records how a question condition depends on the answer of a single other question,
+// the most common branching pattern:
+//
+//	questions:
+//	  - id: "q2a"
+//	    depends_on: ["q1"]
+//	    condition: 'answers["q1"] == 1'
+//
+// Since the condition only depends on that answer, it is evaluated once for every possible answer
+// when the questionnaire is loaded, and progress is computed without evaluating conditions.
+type selector struct {
+	position int    // Position of the only question referenced by the condition
+	enabling []bool // Whether the condition holds for each answer of that question, SkipAnswer at index 0
+}
+
+// enables reports whether the condition holds when the referenced question is given the answer.
+func (s *selector) enables(answer int) bool {
+	if answer == SkipAnswer {
+		answer = 0
+	}
+	return answer >= 0 && answer < len(s.enabling) && s.enabling[answer]
+}
+
+// buildSelectors finds the questions whose condition only depends on the answer of another question
+// and evaluates their condition for every possible answer of that question.
+// The selectors are indexed by question position; dependencies must be valid and acyclic.
+func (q *questionnaire) buildSelectors() []*selector {
+	selectors := make([]*selector, len(q.QuestionList))
+	for i, question := range q.QuestionList {
+		refs := extractQuestionIDs(question.Condition)
+		if len(refs) != 1 || refs[0] == question.Id || usesAllAnswers(question.Condition) {
+			continue
+		}
+		position, ok := q.questionIndex[refs[0]]
+		if !ok {
+			continue
+		}
+
+		ref := q.QuestionList[position]
+		if !ref.isSingleChoice() || ref.hasProvidedOptions() {
+			continue
+		}
+		s := &selector{position: position, enabling: make([]bool, len(ref.Answers)+1)}
+		for _, value := range answerValues(ref) {
+			show, err := q.evaluateCondition(question.Condition, map[string]int{ref.Id: value})
+			// Conditions failing to evaluate are counted as shown: Next reports the error
+			if show || err != nil {
+				s.enabling[max(value, 0)] = true
+			}
+		}
+		selectors[i] = s
+	}
+	return selectors
+}
+
+// answerValues returns every answer the question accepts: its options and, when allowed, SkipAnswer.
+func answerValues(question question) []int {
+	values := make([]int, 0, len(question.Answers)+1)
+	for answer := 1; answer <= len(question.Answers); answer++ {
+		values = append(values, answer)
+	}
+	if question.canBeSkipped() {
+		values = append(values, SkipAnswer)
+	}
+	return values
+}
+
+// usesAllAnswers reports whether the condition depends on every answer rather than on specific questions,
+// for instance through the score, computed values, countAnswered() or len(answers).
+func usesAllAnswers(condition string) bool {
+	if referencesIdentifier(condition, "score") || referencesIdentifier(condition, "countAnswered") || referencesIdentifier(condition, "computed") {
+		return true
+	}
+	return usesWholeMap(condition, "answers") || usesWholeMap(condition, "values")
+}
+
+// usesWholeMap reports whether the condition uses the map other than by indexing it with a question ID.
+func usesWholeMap(condition, name string) bool {
+	for i := 0; i+len(name) <= len(condition); i++ {
+		if condition[i:i+len(name)] != name {
+			continue
+		}
+		before := i == 0 || !isIdentifierChar(condition[i-1])
+		after := i+len(name) == len(condition) || !isIdentifierChar(condition[i+len(name)])
+		if !before || !after {
+			continue
+		}
+		// Only name["id"] refers to a specific question
+		next := skipSpaces(condition, i+len(name))
+		if next == len(condition) || condition[next] != '[' {
+			return true
+		}
+	}
+	return false
+}
+
+// remainingQuestions estimates the number of questions left on the longest path through the questionnaire
+// given the answers, so that the progress total doesn't jump around as branches open up (see longestRemainingPath).
+func (q *questionnaire) remainingQuestions(answers map[string]int) (int, error) {
+	return q.longestRemainingPath(answers, q.progressWeight)
+}
+
+// progressWeight returns the weight of the question in the progress: its weight, 1 when it has none.
+// Hidden questions are answered by Next and questions outside the tags of the call are never returned, so they don't count.
+func (q *questionnaire) progressWeight(question *question) int {
+	switch {
+	case question.Hidden || !q.inScope(question):
+		return 0
+	case question.Weight > 0:
+		return question.Weight
+	default:
+		return 1
+	}
+}
+
+// remainingTime estimates the number of seconds needed to answer the questions left on the longest path
+// through the questionnaire given the answers, from the time_estimate of the questions (see longestRemainingPath).
+func (q *questionnaire) remainingTime(answers map[string]int) (int, error) {
+	return q.longestRemainingPath(answers, func(question *question) int {
+		if !q.inScope(question) {
+			return 0
+		}
+		return question.TimeEstimate
+	})
+}
+
+// longestRemainingPath estimates the weight of the longest path left through the questionnaire given the answers,
+// where the weight of a path is the sum of the weights of its questions.
+//
+// Unanswered questions that can't be shown anymore are not counted, and questions on mutually exclusive
+// branches are not added up: for each unanswered question selecting a branch (see selector), only its
+// heaviest branch is counted. Other conditions can't be decided before their questions are answered,
+// so their questions are counted as if they were shown: the estimate never underestimates the remaining path.
+//
+// Next calls it on every request: the state is kept in slices indexed by question position.
+// It fails when the dependency chains followed are deeper than the maximum evaluation depth (see WithMaxEvaluationDepth).
+func (q *questionnaire) longestRemainingPath(answers map[string]int, weight func(*question) int) (int, error) {
+	n := len(q.QuestionList)
+	state := make([]int, 4*n)
+	possible := state[:n]        // 0 when unknown, the length of its chain of unanswered dependencies when the question may still be shown, -1 otherwise
+	lengths := state[n : 2*n]    // Memoized weight of the longest path starting with the question, plus one (0 when unknown)
+	branches := state[2*n : 3*n] // First question selected by the question, plus one (0 when none)
+	siblings := state[3*n:]      // Next question selected by the same question, plus one (0 when none)
+
+	var candidates []int
+	for i := n - 1; i >= 0; i-- {
+		if _, answered := answers[q.QuestionList[i].Id]; answered {
+			continue
+		}
+		stillPossible, err := q.isStillPossible(i, answers, possible)
+		if err != nil {
+			return 0, err
+		}
+		if !stillPossible {
+			continue
+		}
+		if s := q.selectors[i]; s != nil {
+			if _, answered := answers[q.QuestionList[s.position].Id]; !answered {
+				siblings[i] = branches[s.position]
+				branches[s.position] = i + 1
+				continue
+			}
+		}
+		candidates = append(candidates, i)
+	}
+
+	// length returns the weight of the longest path starting with the question, following its branches
+	var length func(position int) int
+	length = func(position int) int {
+		if lengths[position] > 0 {
+			return lengths[position] - 1
+		}
+		longest := 0
+		if branches[position] > 0 {
+			for _, value := range answerValues(q.QuestionList[position]) {
+				branch := 0
+				for selected := branches[position]; selected > 0; selected = siblings[selected-1] {
+					if q.selectors[selected-1].enables(value) {
+						branch += length(selected - 1)
+					}
+				}
+				longest = max(longest, branch)
+			}
+		}
+		result := weight(&q.QuestionList[position]) + longest
+		lengths[position] = result + 1
+		return result
+	}
+
+	remaining := 0
+	for _, position := range candidates {
+		remaining += length(position)
+	}
+	return remaining, nil
+}
+
+// isStillPossible reports whether the unanswered question at the position may be shown given the answers:
+// its dependencies are answered or may still be shown, and its condition isn't already known to be false.
+// Results are memoized in possible; dependencies must not be circular.
+// It fails when the chain of unanswered dependencies leading to the question is longer than the maximum evaluation depth.
+func (q *questionnaire) isStillPossible(position int, answers map[string]int, possible []int) (bool, error) {
+	if possible[position] != 0 {
+		return possible[position] > 0, nil
+	}
+
+	question := &q.QuestionList[position]
+	result := true
+	depth := 1 // Length of the longest chain of unanswered dependencies ending with the question
+	for _, depID := range question.DependsOn {
+		if _, answered := answers[depID]; answered {
+			continue
+		}
+		dep, ok := q.questionIndex[depID]
+		if !ok {
+			result = false
+			break
+		}
+		depPossible, err := q.isStillPossible(dep, answers, possible)
+		if err != nil {
+			return false, err
+		}
+		if !depPossible {
+			result = false
+			break
+		}
+		depth = max(depth, possible[dep]+1)
+	}
+	if result {
+		if err := q.checkEvaluationDepth(depth, "dependency chain"); err != nil {
+			return false, err
+		}
+		result = q.mayConditionHold(position, answers)
+	}
+
+	possible[position] = -1
+	if result {
+		possible[position] = depth
+	}
+	return result, nil
+}
+
+// mayConditionHold reports whether the condition of the question at the position holds,
+// or may hold once more questions are answered.
+func (q *questionnaire) mayConditionHold(position int, answers map[string]int) bool {
+	question := &q.QuestionList[position]
+	if question.Condition == "" {
+		return true
+	}
+
+	if s := q.selectors[position]; s != nil {
+		if answer, answered := answers[q.QuestionList[s.position].Id]; answered {
+			return s.enables(answer)
+		}
+		return slices.Contains(s.enabling, true)
+	}
+
+	if usesAllAnswers(question.Condition) {
+		return true
+	}
+	for _, id := range extractQuestionIDs(question.Condition) {
+		if _, answered := answers[id]; !answered {
+			return true
+		}
+	}
+
+	// Every referenced question is answered: the condition is decided
+	show, err := q.evaluateCondition(question.Condition, answers)
+	return show || err != nil
+}
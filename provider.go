@@ -0,0 +1,97 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type (
+	// DataProvider supplies at runtime the answer options of the questions declaring an options_from source,
+	// instead of options hardcoded in the configuration (see WithDataProvider):
+	//
+	//	questions:
+	//	  - id: "project"
+	//	    text: "Which project is this feedback about?"
+	//	    options_from: "projects"
+	//
+	// Options are called at most once per source and Next call, however many questions use the source.
+	// Answers are the 1-indexed positions of the options returned, so the options of a source must be
+	// returned in a stable order across calls, e.g. sorted by ID.
+	DataProvider interface {
+		// Options returns the answer options of the source, e.g. the projects of the user fetched from a database.
+		Options(source string) ([]DataOption, error)
+	}
+
+	// DataProviderFunc is a function used as a DataProvider.
+	//
+	// Example usage:
+	//   provider := gdq.DataProviderFunc(func(source string) ([]gdq.DataOption, error) {
+	//       return store.ProjectOptions(userID)
+	//   })
+	DataProviderFunc func(source string) ([]DataOption, error)
+
+	// DataOption is an answer option supplied by a DataProvider.
+	DataOption struct {
+		Id   string `json:"id,omitempty"` // Optional stable identifier of the option (e.g. the project ID), returned in Question.AnswerIds
+		Text string `json:"text"`         // The answer text shown to users
+	}
+)
+
+// Options calls f(source).
+func (f DataProviderFunc) Options(source string) ([]DataOption, error) {
+	return f(source)
+}
+
+// hasProvidedOptions reports whether the answer options of the question are supplied by a DataProvider.
+func (q question) hasProvidedOptions() bool {
+	return q.OptionsFrom != ""
+}
+
+// validOptionsSource checks that a question declaring an options_from source is answered by choosing options,
+// and doesn't declare its own options.
+func (q question) validOptionsSource() error {
+	switch {
+	case !q.hasProvidedOptions():
+		return nil
+	case !q.hasOptions():
+		return errors.New("only questions answered by choosing options can get them from a data provider")
+	case len(q.Answers) > 0:
+		return errors.New("answers and options_from can't be used together")
+	}
+	return nil
+}
+
+// withProvidedOptions returns a copy of the questionnaire where the questions declaring an options_from source
+// offer the answer options supplied by the data provider (see WithDataProvider), or the questionnaire itself
+// when no question gets its options from a provider. The options of a source are only requested once.
+func (q *questionnaire) withProvidedOptions(options *nextOptions) (*questionnaire, error) {
+	if len(q.provided) == 0 || q.optionsProvided {
+		return q, nil
+	}
+	if options.dataProvider == nil {
+		return nil, errors.New("questions get their options from a data provider, but none was set (see WithDataProvider)")
+	}
+
+	scoped := *q
+	scoped.QuestionList = slices.Clone(q.QuestionList)
+	scoped.optionsProvided = true
+	sources := make(map[string][]answerOption)
+	for _, position := range q.provided {
+		question := &scoped.QuestionList[position]
+		answers, cached := sources[question.OptionsFrom]
+		if !cached {
+			provided, err := options.dataProvider.Options(question.OptionsFrom)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the options of source '%s': %w", question.OptionsFrom, err)
+			}
+			answers = make([]answerOption, len(provided))
+			for i, option := range provided {
+				answers[i] = answerOption{Id: option.Id, Text: newLocalizedText(option.Text)}
+			}
+			sources[question.OptionsFrom] = answers
+		}
+		question.Answers = answers
+	}
+	return &scoped, nil
+}
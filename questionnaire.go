@@ -25,9 +25,31 @@ for fixing the issue.
 package go_dynamic_questionnaire
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"time"
 
-	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// SkipAnswer is the sentinel answer value used to skip an optional or skippable question.
+// A skipped question counts as answered: it is no longer returned by Next
+// and questions depending on it can be shown.
+// Conditions can test whether a question was skipped with skipped("question_id").
+//
+// Example usage:
+//
+//	answers := map[string]int{"q1": 2, "nickname": gdq.SkipAnswer}
+const SkipAnswer = -1
+
+// Media types used for the image and video shorthands.
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
 )
 
 type (
@@ -72,7 +94,186 @@ type (
 		// The method validates all provided answers before processing. If any answer
 		// is invalid, the entire operation fails and returns a validation error with
 		// details about what went wrong.
-		Next(answers map[string]int) (*Response, error)
+		//
+		// Options such as WithSeed or WithPageSize can be passed to tune how the next step is computed.
+		Next(answers map[string]int, opts ...NextOption) (*Response, error)
+
+		// NextAnswers works like Next, with answers of any question type: choices, multiple choices,
+		// texts and numbers (see Answer). Next only accepts answers to choice questions.
+		//
+		// Parameters:
+		//   answers: A map where keys are question IDs and values are the answers (e.g. gdq.Choices(1, 3)).
+		//
+		// Returns:
+		//   *Response: The same response as Next.
+		//   error: Returns validation errors for invalid question IDs, answers not matching
+		//          the question type, out-of-range choices, or condition evaluation errors.
+		NextAnswers(answers map[string]Answer, opts ...NextOption) (*Response, error)
+
+		// Revise changes the answer of a question, as done by edit-your-answers screens, and leaves out the answers
+		// invalidated by the change: the answers to the questions that wouldn't be shown anymore (see WithStaleAnswers).
+		// The revised question is invalidated too when it wouldn't be shown itself.
+		//
+		// Parameters:
+		//   answers: The answers given so far, as passed to Next.
+		//   questionID: The ID of the question whose answer changes.
+		//   answer: The new 1-indexed answer choice, or SkipAnswer.
+		//
+		// Returns:
+		//   *Revision: The revised answers, ready for Next, and the invalidated questions.
+		//   error: Returns validation errors for an invalid question ID or answer.
+		Revise(answers map[string]int, questionID string, answer int) (*Revision, error)
+
+		// Summary lists the answered questions on the path taken through the questionnaire with the texts of their answers,
+		// so that review and confirmation screens don't have to map answer choices back to labels.
+		//
+		// Parameters:
+		//   answers: The answers given so far, as passed to Next.
+		//
+		// Returns:
+		//   []SummaryItem: The answered questions, in configuration order, without stale answers and hidden questions.
+		//   error: Returns validation errors for invalid question IDs or answers, or condition evaluation errors.
+		Summary(answers map[string]int, opts ...NextOption) ([]SummaryItem, error)
+
+		// Review lists the answered questions on the path taken through the questionnaire with their answer,
+		// rendered as Next returns them, so that clients can show an editable review page before completion.
+		//
+		// Parameters:
+		//   answers: The answers given so far, as passed to Next.
+		//
+		// Returns:
+		//   []ReviewItem: The answered questions with their answer, in configuration order, without stale answers and hidden questions.
+		//   error: Returns validation errors for invalid question IDs or answers, or condition evaluation errors.
+		Review(answers map[string]int, opts ...NextOption) ([]ReviewItem, error)
+
+		// Remaining lists the unanswered questions that may still be shown given the answers,
+		// including those later answers may unlock, for "what's left" screens.
+		//
+		// Parameters:
+		//   answers: The answers given so far, as passed to Next.
+		//
+		// Returns:
+		//   []Question: The questions that may still be shown, in configuration order.
+		//   error: Returns validation errors for invalid question IDs or answers, or condition evaluation errors.
+		Remaining(answers map[string]int, opts ...NextOption) ([]Question, error)
+
+		// WhatIf previews the effect of hypothetical answers, without committing them: which questions and closing remarks
+		// would be shown or hidden if they were added to the answers. This suits authoring tools and previews.
+		//
+		// Parameters:
+		//   answers: The answers given so far, as passed to Next.
+		//   hypothetical: The answers to evaluate, overriding the answers to the same questions.
+		//
+		// Returns:
+		//   *WhatIfResult: The response Next would return with the hypothetical answers, and what it changes.
+		//   error: Returns validation errors for invalid answers or hypothetical answers, or condition evaluation errors.
+		WhatIf(answers map[string]int, hypothetical map[string]int, opts ...NextOption) (*WhatIfResult, error)
+
+		// ValidateAnswerSet checks a complete set of answers received from an untrusted client: beyond the checks of Next,
+		// every answered question must be reachable given the other answers, which catches tampered or stale submissions.
+		//
+		// Parameters:
+		//   answers: The answers to check, as passed to Next.
+		//
+		// Returns:
+		//   error: nil when the answers are consistent, or validation errors (e.g. ErrUnreachableAnswer for each question answered off the path).
+		ValidateAnswerSet(answers map[string]int, opts ...NextOption) error
+
+		// ResolveAnswers converts answers expressed with answer option IDs into the
+		// 1-indexed answer choices expected by Next.
+		//
+		// Storing option IDs rather than indices lets stored responses survive
+		// options being reordered or reworded.
+		//
+		// Parameters:
+		//   answers: A map where keys are question IDs and values are answer option IDs.
+		//
+		// Returns:
+		//   map[string]int: The answers as 1-indexed answer choices.
+		//   error: Returns validation errors for invalid question IDs or unknown answer IDs.
+		ResolveAnswers(answers map[string]string) (map[string]int, error)
+
+		// Questions returns the questions of the questionnaire, so that tooling can introspect its definition.
+		// Texts are returned in the default locale, and every answer option is listed.
+		// The returned questions are copies, down to their metadata maps, tags and bounds: modifying them
+		// doesn't change the questionnaire. Nested metadata values (e.g. lists) are still shared.
+		//
+		// Returns:
+		//   []Question: Every question, hidden ones included, in configuration order.
+		Questions() []Question
+
+		// ClosingRemarks returns the closing remarks of the questionnaire, whatever their condition,
+		// in the default locale. The returned remarks are copies, down to their metadata maps: modifying them
+		// doesn't change the questionnaire. Nested metadata values (e.g. lists) are still shared.
+		//
+		// Returns:
+		//   []ClosingRemark: Every closing remark, in configuration order.
+		ClosingRemarks() []ClosingRemark
+
+		// Warnings returns the problems detected when the questionnaire was created
+		// that don't prevent it from being used, such as questions that can never be shown.
+		//
+		// Returns:
+		//   []Warning: The detected problems, nil if the questionnaire has none.
+		Warnings() []Warning
+
+		// Analyze explores every answer path of the questionnaire to find dead ends:
+		// paths completing the questionnaire without any closing remark, and questions or
+		// closing remarks never shown on any path.
+		//
+		// The analysis is more expensive than the checks performed by New and is meant
+		// to lint questionnaires, for instance in CI.
+		//
+		// Returns:
+		//   []Warning: The detected problems, nil if the questionnaire has none.
+		Analyze() []Warning
+
+		// Lint reports all the problems found in the questionnaire, separating errors
+		// (conditions failing to evaluate for some answers) from warnings
+		// (see Warnings and Analyze). It is meant for CI gating of questionnaire changes.
+		//
+		// Returns:
+		//   LintReport: The errors and warnings found in the questionnaire.
+		Lint() LintReport
+
+		// Stats measures the complexity of the questionnaire: question counts, depth of the dependencies,
+		// branch factor, number of outcomes and longest path. Like Analyze, it explores every answer path,
+		// and is meant to help reviewers judge questionnaire changes.
+		//
+		// Returns:
+		//   Stats: The complexity measures of the questionnaire.
+		Stats() Stats
+
+		// ExportMermaid renders the questionnaire flow as a Mermaid flowchart:
+		// questions and closing remarks are nodes, dependencies are edges labeled with conditions.
+		// Texts are rendered in the default locale.
+		//
+		// Returns:
+		//   string: The Mermaid flowchart definition, ready to embed in Markdown.
+		ExportMermaid() string
+
+		// ExportDOT renders the questionnaire flow as a Graphviz DOT digraph, with the same
+		// nodes and edges as ExportMermaid, to generate diagrams of large questionnaires.
+		//
+		// Returns:
+		//   string: The DOT definition, to render with e.g. "dot -Tsvg".
+		ExportDOT() string
+
+		// AnswersSchema describes the answers accepted by Next as a JSON Schema object,
+		// with one property per question restricted to its answer choices, so that API
+		// documentation (e.g. an OpenAPI document) can describe each questionnaire precisely.
+		//
+		// Returns:
+		//   map[string]interface{}: The JSON Schema, ready to be encoded as JSON.
+		AnswersSchema() map[string]interface{}
+
+		// Checksum returns a fingerprint of the questionnaire definition: a hash of its content,
+		// stable across loads and independent of the configuration format and formatting.
+		// Clients can compare it (see WithChecksum) to detect that the definition changed mid-session.
+		//
+		// Returns:
+		//   string: The SHA-256 of the definition, in hexadecimal.
+		Checksum() string
 	}
 
 	// config is a constraint interface for configuration inputs to the New function.
@@ -90,27 +291,131 @@ type (
 	//
 	// This struct is not exported as users should interact with the Questionnaire interface.
 	// Instances are created through the New function and are immutable after creation.
+	// Unexported fields hold the settings provided through options.
 	questionnaire struct {
-		Questions []question      `yaml:"questions" json:"questions"`             // List of all questions in the questionnaire
-		Remarks   []closingRemark `yaml:"closing_remarks" json:"closing_remarks"` // List of all closing remarks
+		Id               string            `yaml:"id,omitempty" json:"id,omitempty"`                               // Optional identifier of the questionnaire, its key when loaded with LoadDir
+		QuestionList     []question        `yaml:"questions" json:"questions"`                                     // List of all questions in the questionnaire
+		Remarks          []closingRemark   `yaml:"closing_remarks" json:"closing_remarks"`                         // List of all closing remarks
+		ShuffleQuestions bool              `yaml:"shuffle_questions,omitempty" json:"shuffle_questions,omitempty"` // Whether eligible questions are returned in a randomized order
+		DefaultLocale    string            `yaml:"default_locale,omitempty" json:"default_locale,omitempty"`       // Locale used when a text has no translation for the requested locale
+		CompleteWhen     string            `yaml:"complete_when,omitempty" json:"complete_when,omitempty"`         // Optional expression completing the questionnaire early, even with eligible questions left
+		Rules            []consistencyRule `yaml:"rules,omitempty" json:"rules,omitempty"`                         // Consistency rules across several answers, checked by Next
+		Computed         map[string]string `yaml:"computed,omitempty" json:"computed,omitempty"`                   // Named expressions deriving values from the answers, exposed to conditions
+		Macros           map[string]string `yaml:"macros,omitempty" json:"macros,omitempty"`                       // Named condition snippets, referenced in expressions as $name
+		Groups           []questionGroup   `yaml:"groups,omitempty" json:"groups,omitempty"`                       // Questions repeated for every item counted by a number question
+		Quotas           []quota           `yaml:"quotas,omitempty" json:"quotas,omitempty"`                       // Limits on the number of respondents choosing answer options (see WithQuotaChecker)
+		AvailableFrom    time.Time         `yaml:"available_from,omitempty" json:"available_from,omitzero"`        // Optional time from which the questionnaire can be answered
+		AvailableUntil   time.Time         `yaml:"available_until,omitempty" json:"available_until,omitzero"`      // Optional time from which the questionnaire can't be answered anymore
+
+		functions        map[string]interface{} // Custom functions available in conditions (see WithFunctions)
+		programs         map[string]*vm.Program // Compiled conditions, keyed by expression
+		typedEnvironment map[string]interface{} // Typed values and computed values used to type-check the expressions (see typedEnv)
+		warnings         []Warning              // Problems detected at load time (see Warnings)
+		checksum         string                 // Fingerprint of the definition (see Checksum)
+
+		questionIndex map[string]int   // Position of each question in QuestionList, by ID
+		roots         []int            // Positions of the questions without dependencies
+		dependents    map[string][]int // Positions of the questions depending on each question, by ID
+		selectors     []*selector      // Questions whose condition only depends on the answer of another question, by position
+		terminators   []int            // Positions of the questions able to end the questionnaire early
+		hidden        []int            // Positions of the hidden questions, answered by Next rather than displayed
+		provided      []int            // Positions of the questions whose answer options are supplied by a DataProvider
+		timeEstimated bool             // Whether at least one question declares a time_estimate
+
+		maxExpressionLength    int                    // Maximum length of a condition, 0 for no limit (see WithMaxExpressionLength)
+		maxExpressionNodes     uint                   // Maximum number of syntax tree nodes of a condition, 0 for expr's default (see WithMaxExpressionNodes)
+		evaluationMemoryBudget uint                   // Maximum memory a condition evaluation may allocate, 0 for expr's default (see WithEvaluationMemoryBudget)
+		maxEvaluationDepth     int                    // Maximum number of evaluation rounds and dependency chain length of a Next call, 0 for no limit (see WithMaxEvaluationDepth)
+		disallowedBuiltins     []string               // expr builtins that conditions are not allowed to call (see WithDisallowedBuiltins)
+		strictValidation       bool                   // Whether conditions are type-checked and their question references verified (see WithStrictValidation)
+		logger                 *slog.Logger           // Logger receiving debug logs, nil to disable logging (see WithLogger)
+		metrics                Metrics                // Metrics receiving usage measurements, nil to disable them (see WithMetrics)
+		completionHook         func(Completion)       // Function called when Next completes the questionnaire (see WithCompletionHook)
+		params                 map[string]interface{} // Values of the {{ .params.name }} variables of the configuration, nil when not rendered (see WithParams)
+		expandEnv              bool                   // Whether the ${VAR} references of the configuration are replaced with environment variables (see WithEnvExpansion)
+		objectStores           map[string]ObjectStore // Object stores reading the configurations given as URIs, by scheme (see WithObjectStore)
+		signature              *signature             // Detached signature the configuration content must match, nil when unsigned (see WithSignature)
+		transforms             []ContentTransform     // Functions applied in order to the configuration content before it is parsed (see WithContentTransform)
+		auditSink              AuditSink              // Sink receiving a record of every Next call, nil to disable auditing (see WithAuditSink)
+		sensitiveAnswers       SensitiveAnswerPolicy  // What to do with the answers to sensitive questions outside of the responses (see WithSensitiveAnswers)
+		quotaChecker           QuotaChecker           // Checker counting the respondents of each quota, nil to disable quotas (see WithQuotaChecker)
+		clock                  func() time.Time       // Function returning the current time, nil for time.Now (see WithClock)
+
+		values map[string]interface{} // Answers of the questions that aren't single choice, only set for the NextAnswers call in progress
+		tags   []string               // Tags restricting the questions returned, only set for the Next call in progress (see WithTags)
+
+		optionsProvided bool        // Whether the options supplied by the DataProvider are set, only for the Next call in progress
+		audit           *auditTrail // Evaluations collected for the audit sink, only set for the Next call in progress
 	}
 
 	// question represents a single question in the questionnaire configuration.
 	// Questions can have conditional logic that determines when they should be shown.
 	question struct {
-		Id        string   `yaml:"id" json:"id"`                                     // Unique identifier for the question
-		Text      string   `yaml:"text" json:"text"`                                 // The question text shown to users
-		Answers   []string `yaml:"answers" json:"answers"`                           // List of possible answer choices
-		DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"` // Explicit list of question IDs this question depends on (required if condition is used)
-		Condition string   `yaml:"condition,omitempty" json:"condition,omitempty"`   // Optional expression to determine if question should be shown
+		Id             string                 `yaml:"id" json:"id"`                                               // Unique identifier for the question
+		Text           localizedText          `yaml:"text" json:"text"`                                           // The question text shown to users
+		Description    localizedText          `yaml:"description,omitempty" json:"description,omitempty"`         // Optional longer description displayed with the question
+		Help           localizedText          `yaml:"help,omitempty" json:"help,omitempty"`                       // Optional hint explaining how to answer the question
+		Type           string                 `yaml:"type,omitempty" json:"type,omitempty"`                       // Kind of answer expected (ChoiceQuestion when empty, see the question types)
+		Answers        []answerOption         `yaml:"answers" json:"answers"`                                     // List of possible answer choices
+		OptionsFrom    string                 `yaml:"options_from,omitempty" json:"options_from,omitempty"`       // Optional source of answer options supplied at runtime (see WithDataProvider)
+		DependsOn      []string               `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`           // Question IDs this question depends on, inferred from the conditions when omitted
+		Condition      string                 `yaml:"condition,omitempty" json:"condition,omitempty"`             // Optional expression to determine if question should be shown
+		TerminateIf    string                 `yaml:"terminate_if,omitempty" json:"terminate_if,omitempty"`       // Optional expression ending the questionnaire once the question is answered
+		When           *whenRule              `yaml:"when,omitempty" json:"when,omitempty"`                       // Optional structured rule to determine if question should be shown (alternative to condition)
+		ShuffleAnswers bool                   `yaml:"shuffle_answers,omitempty" json:"shuffle_answers,omitempty"` // Whether answer choices are returned in a randomized order
+		Required       *bool                  `yaml:"required,omitempty" json:"required,omitempty"`               // Whether the question must be answered (defaults to true)
+		Skippable      bool                   `yaml:"skippable,omitempty" json:"skippable,omitempty"`             // Whether a required question accepts the skip answer ("prefer not to say")
+		Default        int                    `yaml:"default,omitempty" json:"default,omitempty"`                 // Optional 1-indexed answer used to prefill the question
+		TimeEstimate   int                    `yaml:"time_estimate,omitempty" json:"time_estimate,omitempty"`     // Optional number of seconds needed to answer the question
+		Weight         int                    `yaml:"weight,omitempty" json:"weight,omitempty"`                   // Optional effort needed to answer the question, counted in the progress (1 when omitted)
+		MinChoices     int                    `yaml:"min_choices,omitempty" json:"min_choices,omitempty"`         // Minimum number of options chosen in a multiple choice question (0 when omitted)
+		MaxChoices     int                    `yaml:"max_choices,omitempty" json:"max_choices,omitempty"`         // Maximum number of options chosen in a multiple choice question (every option when omitted)
+		Min            *float64               `yaml:"min,omitempty" json:"min,omitempty"`                         // Optional smallest answer accepted by a number question
+		Max            *float64               `yaml:"max,omitempty" json:"max,omitempty"`                         // Optional largest answer accepted by a number question
+		Image          string                 `yaml:"image,omitempty" json:"image,omitempty"`                     // Optional URL of an image illustrating the question
+		Video          string                 `yaml:"video,omitempty" json:"video,omitempty"`                     // Optional URL of a video illustrating the question
+		Media          []Media                `yaml:"media,omitempty" json:"media,omitempty"`                     // Optional generic media attached to the question
+		Metadata       map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`               // Arbitrary data passed through untouched to the response
+		Hidden         bool                   `yaml:"hidden,omitempty" json:"hidden,omitempty"`                   // Whether the question is never displayed, but answered from the context or its value (see WithContextAnswers)
+		Value          string                 `yaml:"value,omitempty" json:"value,omitempty"`                     // Optional expression answering a hidden question
+		Tags           []string               `yaml:"tags,omitempty" json:"tags,omitempty"`                       // Optional labels grouping the question into partial flows (see WithTags)
+		Sensitive      bool                   `yaml:"sensitive,omitempty" json:"sensitive,omitempty"`             // Whether the answer is personal or sensitive data (see WithSensitiveAnswers)
+
+		group     string // ID of the repeating group the question was expanded from, empty for the other questions
+		iteration int    // Iteration of the repeating group the question belongs to (1-indexed)
+	}
+
+	// answerOption represents a single answer choice of a question.
+	// In the configuration, an option is either a plain string (its text)
+	// or an object with a text, an optional stable ID and an optional condition:
+	//   answers:
+	//     - "Keep my plan"
+	//     - id: "upgrade"
+	//       text: "Upgrade plan"
+	//       condition: 'answers["plan"] == 1'
+	answerOption struct {
+		Id         string        `yaml:"id,omitempty" json:"id,omitempty"`                 // Optional stable identifier of the option
+		Text       localizedText `yaml:"text" json:"text"`                                 // The answer text shown to users
+		Condition  string        `yaml:"condition,omitempty" json:"condition,omitempty"`   // Optional expression to determine if the option should be offered
+		When       *whenRule     `yaml:"when,omitempty" json:"when,omitempty"`             // Optional structured rule to determine if the option should be offered
+		Score      float64       `yaml:"score,omitempty" json:"score,omitempty"`           // Points added to the questionnaire score when the option is chosen
+		Terminates bool          `yaml:"terminates,omitempty" json:"terminates,omitempty"` // Whether choosing the option immediately ends the questionnaire
+		Next       string        `yaml:"next,omitempty" json:"next,omitempty"`             // Optional ID of the question shown only when the option is chosen
+		Exclusive  bool          `yaml:"exclusive,omitempty" json:"exclusive,omitempty"`   // Whether the option can't be chosen along with others in a multiple choice question (e.g. "None of the above")
+		Image      string        `yaml:"image,omitempty" json:"image,omitempty"`           // Optional URL of an image illustrating the option
+		Video      string        `yaml:"video,omitempty" json:"video,omitempty"`           // Optional URL of a video illustrating the option
+		Media      []Media       `yaml:"media,omitempty" json:"media,omitempty"`           // Optional generic media attached to the option
+		Quota      string        `yaml:"quota,omitempty" json:"quota,omitempty"`           // Optional ID of the quota the respondents choosing the option are counted toward
 	}
 
 	// closingRemark represents a message shown when the questionnaire is completed.
 	// Like questions, closing remarks can have conditional logic.
 	closingRemark struct {
-		Id        string `yaml:"id" json:"id"`                                   // Unique identifier for the remark
-		Text      string `yaml:"text" json:"text"`                               // The remark text shown to users
-		Condition string `yaml:"condition,omitempty" json:"condition,omitempty"` // Optional expression to determine if remark should be shown
+		Id        string                 `yaml:"id" json:"id"`                                   // Unique identifier for the remark
+		Text      localizedText          `yaml:"text" json:"text"`                               // The remark text shown to users
+		Condition string                 `yaml:"condition,omitempty" json:"condition,omitempty"` // Optional expression to determine if remark should be shown
+		When      *whenRule              `yaml:"when,omitempty" json:"when,omitempty"`           // Optional structured rule to determine if remark should be shown
+		Metadata  map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`   // Arbitrary data passed through untouched to the response
 	}
 
 	// Response represents the complete response from processing a questionnaire step.
@@ -129,18 +434,68 @@ type (
 	//     "progress": {"current": 2, "total": 5}
 	//   }
 	Response struct {
-		Questions      []Question      `json:"questions"`                 // Next questions to show (empty if completed)
-		ClosingRemarks []ClosingRemark `json:"closing_remarks,omitempty"` // Closing remarks (only when completed)
-		Completed      bool            `json:"completed"`                 // Whether the questionnaire is finished
-		Progress       *Progress       `json:"progress,omitempty"`        // Progress information (nil when completed)
+		Questions      []Question                `json:"questions"`                   // Next questions to show (only the optional ones left once completed)
+		ClosingRemarks []ClosingRemark           `json:"closing_remarks,omitempty"`   // Closing remarks (only when completed)
+		Completed      bool                      `json:"completed"`                   // Whether the questionnaire is finished
+		Progress       *Progress                 `json:"progress,omitempty"`          // Progress information (100% when completed)
+		Defaulted      map[string]int            `json:"defaulted_answers,omitempty"` // Answers filled from question defaults (only with WithDefaults)
+		Score          float64                   `json:"score,omitempty"`             // Sum of the scores of the chosen answers
+		Terminated     bool                      `json:"terminated,omitempty"`        // Whether the questionnaire ended early (see complete_when, terminates and terminate_if)
+		RemainingTime  int                       `json:"remaining_time,omitempty"`    // Estimated number of seconds needed to finish, from the time_estimate of the questions left
+		Explanations   []Explanation             `json:"explanations,omitempty"`      // Why each question is shown or hidden (only with WithExplain)
+		Checksum       string                    `json:"checksum,omitempty"`          // Fingerprint of the questionnaire definition (only with WithChecksum)
+		Metadata       map[string]AnswerMetadata `json:"answer_metadata,omitempty"`   // Metadata of the answers (only when completed, with WithAnswerMetadata)
+		Ignored        []ValidationError         `json:"ignored_answers,omitempty"`   // Answers left out because they are invalid (only with WithLenientAnswers)
+		Stale          []string                  `json:"stale_answers,omitempty"`     // Answers to questions that wouldn't be shown anymore (only with WithStaleAnswers)
+		Computed       map[string]interface{}    `json:"computed,omitempty"`          // Values of the computed block (only when completed)
+		QuotaFull      string                    `json:"quota_full,omitempty"`        // ID of the full quota ending the questionnaire early (see WithQuotaChecker)
 	}
 
 	// Question represents a question that should be presented to the user.
 	// This is the external representation used in API responses.
+	//
+	// When the answer choices are not returned in their configured order (e.g. shuffle_answers),
+	// AnswerIndices maps each displayed choice back to its canonical 1-indexed value.
+	// Answers must always be submitted using the canonical values:
+	//   Answers:       ["No", "Maybe", "Yes"]
+	//   AnswerIndices: [2, 3, 1]  // "No" is submitted as 2
 	Question struct {
-		Id      string   `json:"id"`      // Unique identifier for the question
-		Text    string   `json:"text"`    // The question text to display
-		Answers []string `json:"answers"` // List of answer choices (1-indexed when referenced)
+		Id            string                 `json:"id"`                       // Unique identifier for the question
+		Text          string                 `json:"text"`                     // The question text to display
+		Description   string                 `json:"description,omitempty"`    // Optional longer description of the question
+		Help          string                 `json:"help,omitempty"`           // Optional hint explaining how to answer
+		Type          string                 `json:"type,omitempty"`           // Kind of answer expected (see the question types), empty for choice questions
+		Media         []Media                `json:"media,omitempty"`          // Media attached to the question
+		Answers       []string               `json:"answers"`                  // List of answer choices (1-indexed when referenced)
+		AnswerIndices []int                  `json:"answer_indices,omitempty"` // Canonical value of each displayed answer (nil when in configured order)
+		AnswerIds     []string               `json:"answer_ids,omitempty"`     // Stable ID of each displayed answer (nil when no answer has an ID)
+		AnswerMedia   [][]Media              `json:"answer_media,omitempty"`   // Media attached to each displayed answer (nil when no answer has media)
+		Exclusive     []bool                 `json:"exclusive,omitempty"`      // Whether each displayed answer can't be chosen along with others (nil when no answer is exclusive)
+		MinChoices    int                    `json:"min_choices,omitempty"`    // Minimum number of answers chosen in a multiple choice question (0 when there is no minimum)
+		MaxChoices    int                    `json:"max_choices,omitempty"`    // Maximum number of answers chosen in a multiple choice question (0 when there is no maximum)
+		Min           *float64               `json:"min,omitempty"`            // Smallest answer accepted by a number question (nil when there is no minimum)
+		Max           *float64               `json:"max,omitempty"`            // Largest answer accepted by a number question (nil when there is no maximum)
+		Optional      bool                   `json:"optional,omitempty"`       // Whether the question can be left unanswered or skipped (see SkipAnswer)
+		Skippable     bool                   `json:"skippable,omitempty"`      // Whether the question must be answered but accepts SkipAnswer ("prefer not to say")
+		Default       int                    `json:"default,omitempty"`        // Canonical value of the prefilled answer (0 when there is no default)
+		TimeEstimate  int                    `json:"time_estimate,omitempty"`  // Estimated number of seconds needed to answer (0 when unknown)
+		Metadata      map[string]interface{} `json:"metadata,omitempty"`       // Arbitrary data declared in the configuration (e.g. widget type, icon)
+		Tags          []string               `json:"tags,omitempty"`           // Labels grouping the question into partial flows (see WithTags)
+		Group         string                 `json:"group,omitempty"`          // ID of the repeating group the question belongs to, empty outside groups
+		Iteration     int                    `json:"iteration,omitempty"`      // Iteration of the repeating group the question is asked for (1-indexed, 0 outside groups)
+		Sensitive     bool                   `json:"sensitive,omitempty"`      // Whether the answer is personal or sensitive data (see WithSensitiveAnswers)
+	}
+
+	// Media represents an image, a video or any other media attached to a question or an answer.
+	//
+	// Example usage in JSON:
+	//   {
+	//     "type": "image",
+	//     "url": "https://example.com/product.png"
+	//   }
+	Media struct {
+		Type string `yaml:"type,omitempty" json:"type,omitempty"` // Kind of media (image, video, audio, ...)
+		URL  string `yaml:"url" json:"url"`                       // Location of the media
 	}
 
 	// ClosingRemark represents a message shown to users when the questionnaire is completed.
@@ -152,29 +507,71 @@ type (
 	//     "text": "Thank you for your feedback!"
 	//   }
 	ClosingRemark struct {
-		Id   string `json:"id"`   // Unique identifier for the remark
-		Text string `json:"text"` // The message text to display
+		Id       string                 `json:"id"`                 // Unique identifier for the remark
+		Text     string                 `json:"text"`               // The message text to display
+		Metadata map[string]interface{} `json:"metadata,omitempty"` // Arbitrary data declared in the configuration (e.g. rendering hints)
 	}
 
 	// Progress represents the user's progress through the questionnaire.
 	// It provides information about how many questions have been processed
 	// and how many remain, useful for displaying progress bars or indicators.
 	//
-	// The progress calculation is based on available questions at each step,
-	// taking into account conditional logic. Questions that cannot be reached
-	// due to user answers are not counted in the total.
+	// The total is the number of answered questions plus the length of the longest path left,
+	// taking into account conditional logic: questions that cannot be reached anymore
+	// due to user answers are not counted, and only the longest of mutually exclusive branches is.
+	// As a result, the total doesn't grow as the user answers questions.
 	//
-	// Note: Progress is nil when the questionnaire is completed.
+	// Once the questionnaire is completed, the total is the number of answered questions
+	// and the progress is 100%.
+	//
+	// Questions declaring a weight count as that many questions, so that the progress reflects the effort
+	// (e.g. a 10-row matrix can weigh 10 while a yes/no question weighs 1). Hidden questions don't count.
 	Progress struct {
-		Current int `json:"current"` // Number of questions answered so far
-		Total   int `json:"total"`   // Total number of questions that could be answered
+		Current   int `json:"current"`   // Number of questions answered so far
+		Total     int `json:"total"`     // Total number of questions that could be answered
+		Remaining int `json:"remaining"` // Number of questions left (Total - Current)
+		Percent   int `json:"percent"`   // Completion percentage, rounded down so that 100 means completed
+	}
+
+	// Warning describes a problem detected when the questionnaire is created
+	// that doesn't prevent it from being used, such as a question that can never be shown.
+	Warning struct {
+		Type       string `json:"type"`                  // The kind of problem (e.g. UnreachableQuestionWarning)
+		QuestionID string `json:"question_id,omitempty"` // The question concerned by the problem, if any
+		RemarkID   string `json:"remark_id,omitempty"`   // The closing remark concerned by the problem, if any
+		Message    string `json:"message"`               // Human-readable description of the problem
 	}
 )
 
+const (
+	// UnreachableQuestionWarning is the Warning type of questions that can never be shown,
+	// because their condition is false for every possible answer of their dependencies
+	// or because they depend on a question that can never be shown.
+	UnreachableQuestionWarning = "unreachable_question"
+
+	// OutOfRangeConstantWarning is the Warning type of conditions comparing the answer of a question
+	// to a constant that isn't one of its answers (e.g. answers["q1"] == 7 when q1 only has 3 options).
+	OutOfRangeConstantWarning = "out_of_range_constant"
+
+	// UnreachableClosingRemarkWarning is the Warning type of closing remarks never shown
+	// on any path through the questionnaire (see Questionnaire.Analyze).
+	UnreachableClosingRemarkWarning = "unreachable_closing_remark"
+
+	// DeadEndWarning is the Warning type of answer paths completing the questionnaire
+	// without any closing remark (see Questionnaire.Analyze).
+	DeadEndWarning = "dead_end"
+
+	// AnalysisTruncatedWarning is reported when Questionnaire.Analyze stops before
+	// exploring every answer path of a large questionnaire, or reaches a question
+	// whose answers can't be enumerated (text, number or multiple_choice).
+	AnalysisTruncatedWarning = "analysis_truncated"
+)
+
 // New creates a new Questionnaire instance from either a file path or content (YAML or JSON).
 //
 // The function accepts two types of input:
-//   - string: Path to a configuration file (.yaml, .yml, or .json)
+//   - string: Path to a configuration file (.yaml, .yml, or .json),
+//     or URI of a configuration object such as s3://bucket/key.yaml (see WithObjectStore)
 //   - []byte: Raw configuration content (YAML or JSON)
 //
 // Parameters:
@@ -251,72 +648,205 @@ type (
 //   - Duplicate question IDs
 //   - Empty question IDs
 //   - Questions without answer options
+//   - Default answers out of range
+//   - Duplicate answer option IDs within a question
+//   - Conditions that are not valid expressions
 //   - Invalid configuration syntax
-func New[T config](config T) (Questionnaire, error) {
+//
+// Options such as WithFunctions can be passed to customize the questionnaire.
+func New[T config](config T, opts ...Option) (Questionnaire, error) {
 	q := &questionnaire{}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if err := q.validateFunctions(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
 	if err := loadConfig(config, q); err != nil {
+		q.debug("failed to load questionnaire", "error", err)
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	q.debug("questionnaire loaded", "questions", len(q.QuestionList), "closing_remarks", len(q.Remarks))
 
-	if err := q.validateQuestionnaireIntegrity(); err != nil {
+	// The checksum is computed from the configuration as loaded, environment variables and params already applied
+	// (see WithEnvExpansion and WithParams), but before the definition is expanded
+	checksum, err := q.computeChecksum()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute checksum: %w", err)
+	}
+	q.checksum = checksum
+
+	// Dependencies are inferred from the conditions as written, macros and when rules included,
+	// before jumps add their own; jumps add dependencies, so they are expanded before the questions are indexed
+	// Repeating groups are expanded first, so that their questions are then processed like the others
+	groupErr := q.expandGroups()
+	macroErr := q.expandMacros()
+	whenErr := q.expandWhenRules()
+	q.inferDependencies()
+	jumpErr := q.expandJumps()
+	q.buildIndexes()
+
+	// Every check runs so that all the problems are reported at once
+	err = errors.Join(
+		groupErr,
+		macroErr,
+		jumpErr,
+		whenErr,
+		q.compileConditions(),
+		q.validateQuestionnaireIntegrity(),
+		q.validateRules(),
+		q.validateHiddenQuestions(),
+		q.validateQuotas(),
+		q.validateAvailability(),
+	)
+	if err != nil {
+		for _, validationErr := range ValidationErrors(err) {
+			q.debug("questionnaire validation error", "type", validationErr.Type, "message", validationErr.Message)
+		}
+		q.recordValidationErrors(err)
 		return nil, fmt.Errorf("questionnaire validation failed: %w", err)
 	}
+	for _, warning := range q.warnings {
+		q.debug("questionnaire validation warning", "type", warning.Type, "message", warning.Message)
+	}
+	q.selectors = q.buildSelectors()
 
 	return q, nil
 }
 
+// debug emits a debug log when a logger is configured (see WithLogger).
+func (q *questionnaire) debug(msg string, args ...any) {
+	if q.logger != nil {
+		q.logger.Debug(msg, args...)
+	}
+}
+
 // validateQuestionnaireIntegrity validates the questionnaire configuration at load time
 func (q *questionnaire) validateQuestionnaireIntegrity() error {
+	var errs []error
 	questionIDs := make(map[string]bool)
 
 	// basic validation and collect question IDs
-	for _, question := range q.Questions {
+	for _, question := range q.QuestionList {
 		if question.Id == "" {
-			return emptyQuestionIDError()
+			errs = append(errs, emptyQuestionIDError())
+		} else if questionIDs[question.Id] {
+			errs = append(errs, duplicateQuestionIDError(question.Id))
+		}
+		switch question.kind() {
+		case ChoiceQuestion, MultipleChoiceQuestion, TextQuestion, NumberQuestion, NPSQuestion, ConsentQuestion:
+		default:
+			errs = append(errs, invalidQuestionTypeError(&question))
+		}
+		if len(question.Answers) == 0 && question.hasOptions() && !question.hasProvidedOptions() {
+			errs = append(errs, emptyAnswersError(question.Id))
+		}
+		if err := question.validOptionsSource(); err != nil {
+			errs = append(errs, invalidOptionsSourceError(&question, err))
+		}
+		if question.Default != 0 && (question.Default < 1 || question.Default > len(question.Answers) || !question.isSingleChoice()) {
+			errs = append(errs, invalidDefaultAnswerError(&question))
+		}
+		if question.TimeEstimate < 0 {
+			errs = append(errs, invalidTimeEstimateError(&question))
+		}
+		if question.Weight < 0 {
+			errs = append(errs, invalidWeightError(&question))
+		}
+		if !question.validChoiceLimits() {
+			errs = append(errs, invalidChoiceLimitsError(&question))
 		}
-		if questionIDs[question.Id] {
-			return duplicateQuestionIDError(question.Id)
+		if !question.validNumberBounds() {
+			errs = append(errs, invalidNumberBoundsError(&question))
 		}
-		if len(question.Answers) == 0 {
-			return emptyAnswersError(question.Id)
+		if !question.validConsent() {
+			errs = append(errs, invalidConsentError(&question))
+		}
+		if err := question.validateAnswerIDs(); err != nil {
+			errs = append(errs, err)
 		}
 		questionIDs[question.Id] = true
 	}
 
 	if err := q.detectInvalidDependencies(questionIDs); err != nil {
-		return err
+		errs = append(errs, err)
 	}
 
 	if err := q.detectCircularDependencies(); err != nil {
-		return err
+		errs = append(errs, err)
 	}
 
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// The reachability analysis needs valid, acyclic dependencies
+	q.warnings = append(q.detectUnreachableQuestions(), q.detectOutOfRangeConstants()...)
+
 	return nil
 }
 
+// validateAnswerIDs checks that the answer option IDs of the question are unique.
+func (q question) validateAnswerIDs() error {
+	var errs []error
+	answerIDs := make(map[string]bool)
+	for _, option := range q.Answers {
+		if option.Id == "" {
+			continue
+		}
+		if answerIDs[option.Id] {
+			errs = append(errs, duplicateAnswerIDError(q.Id, option.Id))
+		}
+		answerIDs[option.Id] = true
+	}
+	return errors.Join(errs...)
+}
+
 // detectInvalidDependencies checks if all dependencies declared in questions are valid and in sync between condition and depends_on.
 func (q *questionnaire) detectInvalidDependencies(questionIDs map[string]bool) error {
-	for _, question := range q.Questions {
+	var errs []error
+	for _, question := range q.QuestionList {
 		for _, depID := range question.DependsOn {
 			if !questionIDs[depID] {
-				return invalidDependencyError(question.Id, depID)
+				errs = append(errs, q.withSuggestedQuestionIDs(invalidDependencyError(question.Id, depID), depID))
 			}
 		}
 
-		if question.Condition != "" || len(question.DependsOn) > 0 {
+		if question.hasConditions() || len(question.DependsOn) > 0 {
 			if err := q.validateConditionDependencies(question); err != nil {
-				return err
+				errs = append(errs, err)
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// inferDependencies sets the dependencies of the questions omitting depends_on to the questions
+// referenced by their conditions, so that conditions don't have to be duplicated in depends_on:
+//
+//	questions:
+//	  - id: "language"
+//	    text: "Which language do you prefer?"
+//	    condition: 'answers["experience"] == 1'
+//
+// "language" gets depends_on: ["experience"]. Declared dependencies are kept and still validated
+// against the conditions; an empty depends_on declares that the question has no dependency.
+func (q *questionnaire) inferDependencies() {
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		if question.DependsOn != nil || !question.hasConditions() {
+			continue
+		}
+		question.DependsOn = question.extractQuestionIDsFromCondition(q.Computed)
+	}
 }
 
 // validateConditionDependencies validates that condition references match declared dependencies.
 // This ensures consistency between explicit dependencies and condition logic.
 func (q *questionnaire) validateConditionDependencies(question question) error {
-	if !question.matchingDependencies() {
-		return conditionDependencyMismatchError(question.Id, question.extractQuestionIDsFromCondition(), question.DependsOn)
+	if !question.matchingDependencies(q.Computed) {
+		return conditionDependencyMismatchError(question.Id, question.extractQuestionIDsFromCondition(q.Computed), question.DependsOn)
 	}
 
 	return nil
@@ -357,7 +887,7 @@ func (q *questionnaire) detectCircularDependencies() error {
 	}
 
 	// Check for cycles starting from each question
-	for _, question := range q.Questions {
+	for _, question := range q.QuestionList {
 		if err := hasCycle(question.Id, []string{}); err != nil {
 			return err
 		}
@@ -389,16 +919,23 @@ func (q *questionnaire) detectCircularDependencies() error {
 //	*Response: Complete response containing:
 //	           - Questions: Next questions to show (empty if completed)
 //	           - Completed: Whether questionnaire is finished
-//	           - Progress: Current progress (nil when completed)
+//	           - Progress: Current progress (100% when completed)
 //	           - ClosingRemarks: Final messages (only when completed)
 //	error: Validation errors for invalid inputs, or condition evaluation errors.
 //
 // Behavior:
 //   - Validates all answers before processing
 //   - Evaluates question conditions to determine visibility
+//   - Applies question defaults to unanswered questions (with WithDefaults)
 //   - Filters out already-answered questions
+//   - Completes once every reachable required question is answered,
+//     treating unanswered optional questions as done
 //   - Calculates progress based on reachable questions
 //   - Returns closing remarks only when questionnaire is complete
+//   - Shuffles questions when shuffle_questions is enabled (see WithSeed)
+//   - Fails outside of the availability window (available_from and available_until)
+//   - Screens respondents out once a quota is full (with WithQuotaChecker)
+//   - Records the call to the audit sink (with WithAuditSink)
 //   - Thread-safe: can be called concurrently
 //
 // Example usage:
@@ -432,91 +969,771 @@ func (q *questionnaire) detectCircularDependencies() error {
 //	}
 //
 // Common errors:
+//   - Unavailable questionnaire: "questionnaire is not available until 2025-03-01T09:00:00Z"
 //   - Invalid question ID: "question 'xyz' does not exist"
 //   - Out-of-range answer: "answer 5 is out of range for question 'q1' (valid: 1-3)"
 //   - Condition evaluation error: "failed to evaluate condition for question 'q2'"
-func (q *questionnaire) Next(answers map[string]int) (*Response, error) {
-	if err := q.validateAnswers(answers); err != nil {
+func (q *questionnaire) Next(answers map[string]int, opts ...NextOption) (*Response, error) {
+	if q.auditSink != nil && q.audit == nil {
+		return q.auditedNext(answers, opts)
+	}
+	options := newNextOptions(opts, q.DefaultLocale)
+	if q.metrics != nil {
+		q.metrics.NextCalled()
+	}
+	if err := q.checkAvailability(); err != nil {
+		q.recordValidationErrors(err)
+		return nil, err
+	}
+
+	ignored := options.ignored
+	if options.lenient {
+		var dropped []ValidationError
+		answers, dropped = q.dropInvalidAnswers(answers)
+		ignored = append(ignored, dropped...)
+	}
+
+	q = q.withTags(options.tags)
+	q, err := q.withProvidedOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	q, answers, err = q.fillHiddenAnswers(answers, options)
+	if err != nil {
+		q.recordValidationErrors(err)
+		return nil, fmt.Errorf("failed to answer hidden questions: %w", err)
+	}
+
+	var stale []string
+	if options.staleAnswers != KeepStaleAnswers {
+		stale, err = q.staleAnswers(answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect stale answers: %w", err)
+		}
+		if options.staleAnswers == StripStaleAnswers && len(stale) > 0 {
+			q, answers = q.withoutAnswers(answers, stale)
+		}
+	}
+
+	if err := q.validateAnswers(answers, options.allErrors); err != nil {
+		q.recordValidationErrors(err)
+		return nil, fmt.Errorf("invalid answers provided: %w", err)
+	}
+	if err := q.checkRules(answers, options); err != nil {
+		q.recordValidationErrors(err)
 		return nil, fmt.Errorf("invalid answers provided: %w", err)
 	}
 
-	questions, err := q.getNextQuestions(answers)
+	var defaulted map[string]int
+	if options.applyDefaults {
+		answers, defaulted, err = q.applyDefaults(answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply default answers: %w", err)
+		}
+	}
+
+	full, err := q.fullQuota(answers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get next questions: %w", err)
+		return nil, fmt.Errorf("failed to check quotas: %w", err)
+	}
+	terminated := full != nil
+	if !terminated {
+		terminated, err = q.isTerminated(answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate termination: %w", err)
+		}
 	}
 
-	completed := len(questions) == 0
-	var remarks []ClosingRemark
+	var questions []Question
+	if !terminated {
+		questions, err = q.getNextQuestions(answers, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next questions: %w", err)
+		}
+	}
 
+	// Once the required questions are answered, the questionnaire is completed,
+	// but the optional questions left are still returned so that they can be answered too
+	completed := terminated || !hasRequiredQuestion(questions)
+	pending := questions
 	if completed {
-		remarks, err = q.getClosingRemarks(answers)
+		pending = nil
+	}
+	progress, err := q.calculateProgress(answers, pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate progress: %w", err)
+	}
+	if options.pageSize > 0 && len(questions) > options.pageSize {
+		questions = questions[:options.pageSize]
+	}
+	var remainingTime int
+	if !completed && q.timeEstimated {
+		remainingTime, err = q.remainingTime(answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate remaining time: %w", err)
+		}
+	}
+	var remarks []ClosingRemark
+	var quotaFull string
+
+	switch {
+	case full != nil:
+		// Respondents screened out by a full quota only get the remark of the quota
+		remarks = []ClosingRemark{{Id: full.Id, Text: options.translate(full.Remark)}}
+		quotaFull = full.Id
+	case completed:
+		remarks, err = q.getClosingRemarks(answers, options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get closing remarks: %w", err)
 		}
 	}
 
-	progress := q.calculateProgress(answers, len(questions))
+	score := q.score(answers)
+	var metadata map[string]AnswerMetadata
+	var computed map[string]interface{}
+	if completed {
+		computed, err = q.computeValues(answers)
+		if err != nil {
+			return nil, err
+		}
+		metadata = answeredMetadata(answers, options.metadata)
+		if q.metrics != nil {
+			q.metrics.Completed()
+		}
+		q.complete(Completion{
+			Answers:    answers,
+			Values:     q.values,
+			Computed:   computed,
+			Metadata:   metadata,
+			Score:      score,
+			Terminated: terminated,
+			Quotas:     q.chosenQuotas(answers),
+			QuotaFull:  quotaFull,
+		})
+	}
+
+	var explanations []Explanation
+	if options.explain {
+		explanations, err = q.explain(answers, questions, terminated, completed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var checksum string
+	if options.checksum {
+		checksum = q.checksum
+	}
 
 	return &Response{
 		Questions:      questions,
 		ClosingRemarks: remarks,
 		Completed:      completed,
 		Progress:       progress,
+		Defaulted:      defaulted,
+		Score:          score,
+		Terminated:     terminated,
+		RemainingTime:  remainingTime,
+		Explanations:   explanations,
+		Checksum:       checksum,
+		Metadata:       metadata,
+		Ignored:        ignored,
+		Stale:          stale,
+		Computed:       computed,
+		QuotaFull:      quotaFull,
 	}, nil
 }
 
-// validateAnswers performs comprehensive validation on the provided answers
-func (q *questionnaire) validateAnswers(answers map[string]int) error {
-	for questionID, answer := range answers {
-		if err := q.validateSingleAnswer(questionID, answer); err != nil {
-			return err
+// isTerminated reports whether the answers end the questionnaire early:
+// the complete_when condition is met, or an answered question either has its terminate_if
+// condition met or was answered with an option marked as terminating.
+// Skipped questions only end the questionnaire through their terminate_if condition.
+func (q *questionnaire) isTerminated(answers map[string]int) (bool, error) {
+	if q.CompleteWhen != "" {
+		complete, err := q.evaluateCondition(q.CompleteWhen, answers)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate completion condition: %w", err)
+		}
+		if complete {
+			return true, nil
 		}
 	}
-	return nil
+
+	positions := q.terminators
+	if q.questionIndex == nil {
+		positions = make([]int, len(q.QuestionList))
+		for i := range q.QuestionList {
+			positions[i] = i
+		}
+	}
+
+	for _, position := range positions {
+		question := &q.QuestionList[position]
+		if _, answered := answers[question.Id]; !answered {
+			continue
+		}
+		for _, choice := range q.selectedChoices(question, answers) {
+			if question.terminatesWith(choice) {
+				return true, nil
+			}
+		}
+		if question.TerminateIf == "" {
+			continue
+		}
+		terminated, err := q.evaluateCondition(question.TerminateIf, answers)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate termination condition of question '%s': %w", question.Id, err)
+		}
+		if terminated {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-// validateSingleAnswer validates a single answer for a specific question
-func (q *questionnaire) validateSingleAnswer(questionID string, answer int) error {
+// canTerminate reports whether answering the question may end the questionnaire early.
+func (q question) canTerminate() bool {
+	if q.TerminateIf != "" || q.kind() == ConsentQuestion {
+		return true
+	}
+	for _, option := range q.Answers {
+		if option.Terminates {
+			return true
+		}
+	}
+	return false
+}
+
+// terminatesWith reports whether choosing the answer option ends the questionnaire:
+// the option terminates, or it declines the consent asked by a consent question.
+func (q question) terminatesWith(choice int) bool {
+	return q.Answers[choice-1].Terminates || (q.kind() == ConsentQuestion && choice != 1)
+}
+
+// score returns the sum of the scores of the chosen answer options.
+// Skipped questions don't contribute to the score.
+func (q *questionnaire) score(answers map[string]int) float64 {
+	var score float64
+	for questionID := range answers {
+		question := q.findQuestionByID(questionID)
+		if question == nil {
+			continue
+		}
+		for _, choice := range q.selectedChoices(question, answers) {
+			score += question.Answers[choice-1].Score
+		}
+	}
+	return score
+}
+
+// applyDefaults fills unanswered questions that have a default answer and would be shown.
+// Defaults are applied repeatedly, so that a defaulted answer can unlock further defaulted questions.
+// The provided answers map is not modified: a new map containing the defaulted answers is returned,
+// along with the defaulted answers only (nil when no default was applied).
+func (q *questionnaire) applyDefaults(answers map[string]int) (map[string]int, map[string]int, error) {
+	effective := maps.Clone(answers)
+	if effective == nil {
+		effective = make(map[string]int)
+	}
+	var defaulted map[string]int
+
+	for round, applied := 1, true; applied; round++ {
+		if err := q.checkEvaluationDepth(round, "default answers"); err != nil {
+			return nil, nil, err
+		}
+		applied = false
+		for _, qu := range q.QuestionList {
+			if qu.Default == 0 {
+				continue
+			}
+			show, err := q.shouldShowQuestion(qu, effective)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to show question: %w", err)
+			}
+			if show {
+				if defaulted == nil {
+					defaulted = make(map[string]int)
+				}
+				effective[qu.Id] = qu.Default
+				defaulted[qu.Id] = qu.Default
+				applied = true
+			}
+		}
+	}
+
+	return effective, defaulted, nil
+}
+
+// ResolveAnswers converts answers expressed with answer option IDs into 1-indexed answer choices.
+//
+// Example usage:
+//
+//	// Stored answers: {"plan": "pro", "action": "upgrade"}
+//	answers, err := q.ResolveAnswers(stored)
+//	if err != nil {
+//	    return err
+//	}
+//	response, err := q.Next(answers)
+func (q *questionnaire) ResolveAnswers(answers map[string]string) (map[string]int, error) {
+	resolved := make(map[string]int, len(answers))
+	for questionID, answerID := range answers {
+		question := q.findQuestionByID(questionID)
+		if question == nil {
+			return nil, q.withSuggestedQuestionIDs(invalidQuestionIDError(questionID, answerID), questionID)
+		}
+
+		answer := question.answerIndex(answerID)
+		if answer == 0 {
+			return nil, invalidAnswerIDError(questionID, answerID)
+		}
+		resolved[questionID] = answer
+	}
+	return resolved, nil
+}
+
+// Warnings returns the problems detected when the questionnaire was created.
+func (q *questionnaire) Warnings() []Warning {
+	return q.warnings
+}
+
+// Questions returns every question of the questionnaire, in configuration order and in the default locale,
+// hidden questions included. Each question has all its answer options, in configured order.
+func (q *questionnaire) Questions() []Question {
+	options := &nextOptions{locale: q.DefaultLocale, defaultLocale: q.DefaultLocale}
+	questions := make([]Question, len(q.QuestionList))
+	for i, question := range q.QuestionList {
+		indices := make([]int, len(question.Answers))
+		for j := range indices {
+			indices[j] = j + 1
+		}
+		described := q.describeQuestion(question, indices, false, options)
+		// The response shares the metadata, tags and bounds of the configuration, which callers may modify
+		described.Metadata = maps.Clone(described.Metadata)
+		described.Tags = slices.Clone(described.Tags)
+		described.Min, described.Max = cloneBound(described.Min), cloneBound(described.Max)
+		questions[i] = described
+	}
+	return questions
+}
+
+// ClosingRemarks returns every closing remark of the questionnaire, in configuration order and in the default locale.
+func (q *questionnaire) ClosingRemarks() []ClosingRemark {
+	remarks := make([]ClosingRemark, len(q.Remarks))
+	for i, remark := range q.Remarks {
+		remarks[i] = ClosingRemark{Id: remark.Id, Text: remark.Text.resolve(q.DefaultLocale, q.DefaultLocale), Metadata: maps.Clone(remark.Metadata)}
+	}
+	return remarks
+}
+
+// cloneBound returns a copy of a bound of a number question, or nil when it isn't set.
+func cloneBound(bound *float64) *float64 {
+	if bound == nil {
+		return nil
+	}
+	clone := *bound
+	return &clone
+}
+
+// answerIndex returns the 1-indexed value of the answer option with the given ID, or 0 if there is none.
+func (q question) answerIndex(answerID string) int {
+	for i, option := range q.Answers {
+		if option.Id != "" && option.Id == answerID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// validateAnswers performs comprehensive validation on the provided answers.
+// It returns the first invalid answer found or, when all is true, every invalid answer ordered by question ID
+// (see WithAllAnswerErrors).
+func (q *questionnaire) validateAnswers(answers map[string]int, all bool) error {
+	if !all {
+		for questionID, answer := range answers {
+			if err := q.validateSingleAnswer(questionID, answer, answers); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, questionID := range slices.Sorted(maps.Keys(answers)) {
+		if err := q.validateSingleAnswer(questionID, answers[questionID], answers); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dropInvalidAnswers leaves out the answers to unknown questions and the out-of-range answers (see WithLenientAnswers).
+// The provided answers map is not modified: a new map is returned when answers are left out,
+// along with the validation errors of the left out answers, ordered by question ID.
+func (q *questionnaire) dropInvalidAnswers(answers map[string]int) (map[string]int, []ValidationError) {
+	var ignored []ValidationError
+	kept := answers
+	for _, questionID := range slices.Sorted(maps.Keys(answers)) {
+		validationErr, ok := ignorableAnswerError(q.validateSingleAnswer(questionID, answers[questionID], answers))
+		if !ok {
+			continue
+		}
+		if len(ignored) == 0 {
+			kept = maps.Clone(answers)
+		}
+		delete(kept, questionID)
+		ignored = append(ignored, validationErr)
+	}
+	return kept, ignored
+}
+
+// validateSingleAnswer validates a single answer for a specific question.
+// The other answers are used to check that the chosen option is available.
+func (q *questionnaire) validateSingleAnswer(questionID string, answer int, answers map[string]int) error {
 	question := q.findQuestionByID(questionID)
 	if question == nil {
-		return invalidQuestionIDError(questionID, answer)
+		return q.withSuggestedQuestionIDs(invalidQuestionIDError(questionID, answer), questionID)
+	}
+
+	if answer == SkipAnswer && question.canBeSkipped() {
+		return nil
+	}
+
+	if !question.isSingleChoice() && answer != SkipAnswer {
+		// The answer is given through NextAnswers, which checks that it matches the question type
+		if _, ok := q.values[questionID]; !ok {
+			return invalidAnswerTypeError(question, answer)
+		}
+		for _, choice := range q.selectedChoices(question, answers) {
+			if err := q.validateChoiceAvailability(question, choice, answers); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	if answer < 1 || answer > len(question.Answers) {
 		return invalidAnswerRangeError(question, answer)
 	}
+	return q.validateChoiceAvailability(question, answer, answers)
+}
 
+// validateChoiceAvailability checks that the chosen option of the question is available given the other answers.
+func (q *questionnaire) validateChoiceAvailability(question *question, choice int, answers map[string]int) error {
+	available, err := q.isAnswerAvailable(question.Answers[choice-1], answers)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return unavailableAnswerError(question, choice)
+	}
 	return nil
 }
 
-// findQuestionByID finds a question by its ID
+// findQuestionByID finds a question by its ID, nil if there is none.
+// Questionnaires created by New look the question up in the ID index.
 func (q *questionnaire) findQuestionByID(id string) *question {
-	for i := range q.Questions {
-		if q.Questions[i].Id == id {
-			return &q.Questions[i]
+	if q.questionIndex != nil {
+		if i, ok := q.questionIndex[id]; ok {
+			return &q.QuestionList[i]
+		}
+		return nil
+	}
+
+	for i := range q.QuestionList {
+		if q.QuestionList[i].Id == id {
+			return &q.QuestionList[i]
 		}
 	}
 	return nil
 }
 
+// buildIndexes indexes the questions by ID and by dependency, so that lookups
+// don't scan the whole questionnaire. When IDs are duplicated, the first question wins
+// (duplicates are reported by validateQuestionnaireIntegrity).
+func (q *questionnaire) buildIndexes() {
+	q.questionIndex = make(map[string]int, len(q.QuestionList))
+	q.roots = nil
+	q.dependents = make(map[string][]int)
+	q.terminators = nil
+	q.hidden = nil
+	q.provided = nil
+	q.timeEstimated = false
+
+	for i, question := range q.QuestionList {
+		if _, exists := q.questionIndex[question.Id]; !exists {
+			q.questionIndex[question.Id] = i
+		}
+		if len(question.DependsOn) == 0 {
+			q.roots = append(q.roots, i)
+		}
+		for _, depID := range question.DependsOn {
+			q.dependents[depID] = append(q.dependents[depID], i)
+		}
+		if question.canTerminate() {
+			q.terminators = append(q.terminators, i)
+		}
+		if question.Hidden {
+			q.hidden = append(q.hidden, i)
+		}
+		if question.hasProvidedOptions() {
+			q.provided = append(q.provided, i)
+		}
+		q.timeEstimated = q.timeEstimated || question.TimeEstimate > 0
+	}
+}
+
+// candidateQuestions returns, in evaluation order, the questions whose dependencies may be satisfied:
+// the questions without dependencies and the ones depending on an answered question.
+// Other questions can't be shown, so their conditions don't need to be evaluated.
+func (q *questionnaire) candidateQuestions(answers map[string]int, options *nextOptions) []*question {
+	if q.dependents == nil || q.ShuffleQuestions {
+		return q.orderedQuestions(options)
+	}
+
+	positions := slices.Clone(q.roots)
+	for questionID := range answers {
+		positions = append(positions, q.dependents[questionID]...)
+	}
+	slices.Sort(positions)
+	positions = slices.Compact(positions)
+
+	// Questions are referenced rather than copied: Next runs on every request
+	// and answered questions don't need to be considered at all.
+	candidates := make([]*question, 0, len(positions))
+	for _, position := range positions {
+		if _, answered := answers[q.QuestionList[position].Id]; !answered {
+			candidates = append(candidates, &q.QuestionList[position])
+		}
+	}
+	return candidates
+}
+
 // getNextQuestions retrieves the next set of questions based on the provided answers.
 // It considers both explicit dependencies and conditional logic to determine which questions to show.
-func (q *questionnaire) getNextQuestions(answers map[string]int) ([]Question, error) {
+func (q *questionnaire) getNextQuestions(answers map[string]int, options *nextOptions) ([]Question, error) {
 	var nextQuestions []Question
 
-	for _, qu := range q.Questions {
-		show, err := q.shouldShowQuestion(qu, answers)
+	for _, qu := range q.candidateQuestions(answers, options) {
+		// Hidden questions are answered by Next, never displayed
+		if qu.Hidden || !q.inScope(qu) {
+			continue
+		}
+		show, err := q.shouldShowQuestion(*qu, answers)
 		if err != nil {
 			return nil, fmt.Errorf("failed to show question: %w", err)
 		}
-		if show {
-			nextQuestions = append(nextQuestions, Question{Id: qu.Id, Text: qu.Text, Answers: qu.Answers})
+		if !show {
+			continue
+		}
+
+		question, err := q.toQuestion(*qu, answers, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to show question: %w", err)
+		}
+		// A question without any available answer option cannot be answered
+		if len(question.Answers) > 0 || !qu.hasOptions() {
+			nextQuestions = append(nextQuestions, question)
 		}
 	}
 
 	return nextQuestions, nil
 }
 
+// orderedQuestions returns the questions in the order they should be evaluated.
+// When shuffling is enabled, the whole list is permuted with the seed from the options
+// so that the relative order of eligible questions stays stable across calls using the same seed.
+func (q *questionnaire) orderedQuestions(options *nextOptions) []*question {
+	ordered := make([]*question, len(q.QuestionList))
+	for i := range q.QuestionList {
+		ordered[i] = &q.QuestionList[i]
+	}
+	if !q.ShuffleQuestions {
+		return ordered
+	}
+
+	r := rand.New(rand.NewPCG(options.seed, options.seed))
+	r.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+
+	return ordered
+}
+
+// toQuestion converts the question into its external representation.
+// Answer options whose condition is not met are left out and, when answer shuffling is enabled,
+// the choices are permuted with a seed derived from the options seed and the question ID.
+// AnswerIndices records the canonical values whenever the choices differ from the configured order.
+func (q *questionnaire) toQuestion(question question, answers map[string]int, options *nextOptions) (Question, error) {
+	indices, err := q.availableAnswers(question, answers)
+	if err != nil {
+		return Question{}, err
+	}
+	reordered := len(indices) != len(question.Answers)
+
+	if question.ShuffleAnswers {
+		r := rand.New(rand.NewPCG(options.seed, hashString(question.Id)))
+		r.Shuffle(len(indices), func(i, j int) {
+			indices[i], indices[j] = indices[j], indices[i]
+		})
+		reordered = true
+	}
+	return q.describeQuestion(question, indices, reordered, options), nil
+}
+
+// describeQuestion converts the question into its external representation, with the answer options at the indices,
+// in order. AnswerIndices is only set when the options are reordered.
+func (q *questionnaire) describeQuestion(question question, indices []int, reordered bool, options *nextOptions) Question {
+	texts := make([]string, len(indices))
+	var answerIds []string
+	var answerMedia [][]Media
+	var exclusive []bool
+	for i, index := range indices {
+		option := question.Answers[index-1]
+		texts[i] = options.translate(option.Text)
+		if option.Id != "" {
+			if answerIds == nil {
+				answerIds = make([]string, len(indices))
+			}
+			answerIds[i] = option.Id
+		}
+		if media := option.media(); media != nil {
+			if answerMedia == nil {
+				answerMedia = make([][]Media, len(indices))
+			}
+			answerMedia[i] = media
+		}
+		if option.Exclusive {
+			if exclusive == nil {
+				exclusive = make([]bool, len(indices))
+			}
+			exclusive[i] = true
+		}
+	}
+
+	result := Question{
+		Id:           question.Id,
+		Text:         options.translate(question.Text),
+		Description:  options.translate(question.Description),
+		Help:         options.translate(question.Help),
+		Type:         question.Type,
+		Media:        question.media(),
+		Answers:      texts,
+		AnswerIds:    answerIds,
+		AnswerMedia:  answerMedia,
+		Exclusive:    exclusive,
+		MinChoices:   question.MinChoices,
+		MaxChoices:   question.MaxChoices,
+		Min:          question.Min,
+		Max:          question.Max,
+		Optional:     !question.isRequired(),
+		Skippable:    question.Skippable,
+		Default:      question.Default,
+		TimeEstimate: question.TimeEstimate,
+		Metadata:     question.Metadata,
+		Tags:         question.Tags,
+		Group:        question.group,
+		Iteration:    question.iteration,
+		Sensitive:    question.Sensitive,
+	}
+	if reordered {
+		result.AnswerIndices = indices
+	}
+	return result
+}
+
+// media returns all the media attached to the question.
+func (q question) media() []Media {
+	return collectMedia(q.Image, q.Video, q.Media)
+}
+
+// media returns all the media attached to the answer option.
+func (o answerOption) media() []Media {
+	return collectMedia(o.Image, o.Video, o.Media)
+}
+
+// collectMedia merges the image and video shorthands with the generic media list.
+// Images and videos come first. It returns nil when no media is declared.
+//
+// Example configuration:
+//
+//	image: "https://example.com/product.png"
+//	media:
+//	  - type: "audio"
+//	    url: "https://example.com/jingle.mp3"
+func collectMedia(image, video string, generic []Media) []Media {
+	var media []Media
+	if image != "" {
+		media = append(media, Media{Type: MediaTypeImage, URL: image})
+	}
+	if video != "" {
+		media = append(media, Media{Type: MediaTypeVideo, URL: video})
+	}
+	return append(media, generic...)
+}
+
+// hasConditions reports whether the question or any of its answer options has a condition.
+func (q question) hasConditions() bool {
+	if q.Condition != "" {
+		return true
+	}
+	for _, option := range q.Answers {
+		if option.Condition != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// availableAnswers returns the canonical values (1-indexed) of the answer options whose condition is met.
+func (q *questionnaire) availableAnswers(question question, answers map[string]int) ([]int, error) {
+	indices := make([]int, 0, len(question.Answers))
+	for i, option := range question.Answers {
+		available, err := q.isAnswerAvailable(option, answers)
+		if err != nil {
+			return nil, err
+		}
+		if available {
+			indices = append(indices, i+1)
+		}
+	}
+	return indices, nil
+}
+
+// isAnswerAvailable determines if the answer option can be chosen based on its condition and the provided answers.
+func (q *questionnaire) isAnswerAvailable(option answerOption, answers map[string]int) (bool, error) {
+	if option.Condition == "" {
+		return true, nil
+	}
+
+	available, err := q.evaluateCondition(option.Condition, answers)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate answer condition: %w", err)
+	}
+	return available, nil
+}
+
+// isRequired reports whether the question must be answered for the questionnaire to complete.
+// Questions are required unless explicitly marked with required: false.
+func (q question) isRequired() bool {
+	return q.Required == nil || *q.Required
+}
+
+// canBeSkipped reports whether the question accepts SkipAnswer.
+// Optional questions can always be skipped, required questions only when they are skippable.
+func (q question) canBeSkipped() bool {
+	return !q.isRequired() || q.Skippable
+}
+
+// hasRequiredQuestion reports whether at least one of the questions is required.
+func hasRequiredQuestion(questions []Question) bool {
+	for _, question := range questions {
+		if !question.Optional {
+			return true
+		}
+	}
+	return false
+}
+
 // shouldShowQuestion determines if a question should be shown based on its condition and the provided answers.
 func (q *questionnaire) shouldShowQuestion(question question, answers map[string]int) (bool, error) {
 	if !q.areDependenciesSatisfied(question, answers) {
@@ -531,23 +1748,7 @@ func (q *questionnaire) shouldShowQuestion(question question, answers map[string
 		return true, nil
 	}
 
-	env := map[string]interface{}{
-		"answers": answers,
-	}
-
-	program, err := expr.Compile(question.Condition, expr.Env(env))
-	if err != nil {
-		return false, fmt.Errorf("failed to compile condition expression: %w", err)
-	}
-	result, err := expr.Run(program, env)
-	if err != nil {
-		return false, err
-	}
-	show, ok := result.(bool)
-	if !ok {
-		return false, fmt.Errorf("condition '%s' does not return a boolean", question.Condition)
-	}
-	return show, nil
+	return q.evaluateCondition(question.Condition, answers)
 }
 
 // areDependenciesSatisfied checks if all dependencies for a question are satisfied.
@@ -568,7 +1769,7 @@ func (q *questionnaire) isQuestionAnswered(question question, answers map[string
 }
 
 // getClosingRemarks retrieves the closing remarks based on the provided answers.
-func (q *questionnaire) getClosingRemarks(answers map[string]int) ([]ClosingRemark, error) {
+func (q *questionnaire) getClosingRemarks(answers map[string]int, options *nextOptions) ([]ClosingRemark, error) {
 	var remarks []ClosingRemark
 
 	for _, remark := range q.Remarks {
@@ -577,7 +1778,7 @@ func (q *questionnaire) getClosingRemarks(answers map[string]int) ([]ClosingRema
 			return nil, fmt.Errorf("failed to evaluate closing remark condition: %w", err)
 		}
 		if show {
-			remarks = append(remarks, ClosingRemark{Id: remark.Id, Text: remark.Text})
+			remarks = append(remarks, ClosingRemark{Id: remark.Id, Text: options.translate(remark.Text), Metadata: remark.Metadata})
 		}
 	}
 
@@ -590,43 +1791,50 @@ func (q *questionnaire) shouldShowClosingRemark(remark closingRemark, answers ma
 		return true, nil
 	}
 
-	env := map[string]interface{}{
-		"answers": answers,
-	}
+	return q.evaluateCondition(remark.Condition, answers)
+}
 
-	program, err := expr.Compile(remark.Condition, expr.Env(env))
-	if err != nil {
-		return false, fmt.Errorf("failed to compile condition expression: %w", err)
-	}
-	result, err := expr.Run(program, env)
-	if err != nil {
-		return false, err
+// calculateProgress calculates the progress of the questionnaire based on the provided answers and the available questions.
+// Questions are counted by weight (see progressWeight), and the total accounts for the longest path left
+// (see remainingQuestions), so that it doesn't grow as branches open up.
+// Without available questions, the questionnaire is completed.
+func (q *questionnaire) calculateProgress(answers map[string]int, availableQuestions []Question) (*Progress, error) {
+	current := 0
+	for questionID := range answers {
+		if question := q.findQuestionByID(questionID); question != nil {
+			current += q.progressWeight(question)
+		}
 	}
-	show, ok := result.(bool)
-	if !ok {
-		return false, fmt.Errorf("condition '%s' does not return a boolean", remark.Condition)
+	if len(availableQuestions) == 0 {
+		return &Progress{Current: current, Total: current, Percent: 100}, nil
 	}
-	return show, nil
-}
 
-// calculateProgress calculates the progress of the questionnaire based on the provided answers and the number of available questions.
-func (q *questionnaire) calculateProgress(answers map[string]int, availableQuestions int) *Progress {
-	if availableQuestions == 0 {
-		return nil
+	available := 0
+	for _, question := range availableQuestions {
+		available += q.progressWeight(q.findQuestionByID(question.Id))
 	}
-
-	current := len(answers)
-	total := current + availableQuestions
+	remaining := available
+	if q.selectors != nil {
+		longest, err := q.remainingQuestions(answers)
+		if err != nil {
+			return nil, err
+		}
+		remaining = max(longest, available)
+	}
+	total := current + remaining
 
 	return &Progress{
-		Current: current,
-		Total:   total,
-	}
+		Current:   current,
+		Total:     total,
+		Remaining: remaining,
+		Percent:   current * 100 / total,
+	}, nil
 }
 
 // matchingDependencies checks if the question's condition references match its declared dependencies.
-func (q question) matchingDependencies() bool {
-	referencedIDs := q.extractQuestionIDsFromCondition()
+// The questions referenced by the computed values used in the conditions count as referenced.
+func (q question) matchingDependencies(computed map[string]string) bool {
+	referencedIDs := q.extractQuestionIDsFromCondition(computed)
 
 	if len(referencedIDs) != len(q.DependsOn) {
 		return false
@@ -641,40 +1849,41 @@ func (q question) matchingDependencies() bool {
 	return true
 }
 
-// extractQuestionIDsFromCondition extracts question IDs referenced in a condition expression.
-// This is a simple implementation that looks for patterns like answers["question_id"] or  answers['question_id'].
-// It is designed for speed over complexity, assuming conditions are simple and well-formed.
-// It does not handle complex expressions or nested conditions.
-func (q question) extractQuestionIDsFromCondition() []string {
-	condition := q.Condition
-	var ids []string
-
-	for i := 0; i < len(condition); i++ {
-		if i+8 < len(condition) && condition[i:i+8] == `answers[` {
-			// Found start of answers[
-			start := i + 8
-
-			// Find the quote character (either " or ')
-			if condition[start] == '"' || condition[start] == '\'' {
-				quote := condition[start]
-				start++ // Skip opening quote
-
-				// Find closing quote
-				end := start
-				for end < len(condition) && condition[end] != quote {
-					end++
-				}
-
-				if end < len(condition) {
-					// Extract the question ID
-					questionID := condition[start:end]
-					if questionID != "" && !contains(ids, questionID) {
-						ids = append(ids, questionID)
-					}
-				}
+// extractQuestionIDsFromCondition extracts question IDs referenced in the question condition
+// and in the conditions of its answer options, including through computed values (see extractReferences).
+func (q question) extractQuestionIDsFromCondition(computed map[string]string) []string {
+	ids := extractReferences(q.Condition, computed)
+	for _, option := range q.Answers {
+		for _, id := range extractReferences(option.Condition, computed) {
+			if !contains(ids, id) {
+				ids = append(ids, id)
 			}
 		}
 	}
-
 	return ids
 }
+
+// withTags returns a copy of the questionnaire restricted to the questions tagged with one of the tags
+// (see WithTags), or the questionnaire itself when there is no tag.
+func (q *questionnaire) withTags(tags []string) *questionnaire {
+	if len(tags) == 0 {
+		return q
+	}
+	scoped := *q
+	scoped.tags = tags
+	return &scoped
+}
+
+// inScope reports whether the question has one of the tags of the call in progress (see WithTags).
+// Every question is in scope when there is no tag.
+func (q *questionnaire) inScope(question *question) bool {
+	if len(q.tags) == 0 {
+		return true
+	}
+	for _, tag := range question.Tags {
+		if slices.Contains(q.tags, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,20 @@
 package go_dynamic_questionnaire_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	gdq "github.com/antfroger/go-dynamic-questionnaire"
 	. "github.com/onsi/ginkgo/v2"
@@ -109,6 +122,35 @@ questions:
 			})
 		})
 
+		When("answer options have duplicate IDs", func() {
+			It("should fail to load", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan?"
+    answers:
+      - id: "pro"
+        text: "Pro"
+      - id: "pro"
+        text: "Enterprise"
+`))
+				Expect(err).To(MatchError("questionnaire validation failed: validation error (duplicate_answer_id): duplicated answer ID"))
+			})
+		})
+
+		When("questions have out-of-range defaults", func() {
+			It("should fail to load", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "color"
+    text: "Favorite color?"
+    answers: ["Red", "Blue"]
+    default: 3
+`))
+				Expect(err).To(MatchError("questionnaire validation failed: validation error (invalid_default_answer): default answer is out of range"))
+			})
+		})
+
 		When("questionnaire contains invalid dependencies", func() {
 			It("should detect dependency on non existing question", func() {
 				yamlData := []byte(`
@@ -118,7 +160,8 @@ questions:
     answers: ["Yes", "No"]
     depends_on: ["nonexistent"]`)
 				_, err := gdq.New(yamlData)
-				Expect(err).To(MatchError("questionnaire validation failed: validation error (invalid_dependency): question 'q1' depends on non-existent question 'nonexistent'"))
+				Expect(err).To(MatchError("questionnaire validation failed: validation error (invalid_dependency): question 'q1' depends on non-existent question 'nonexistent'\n" +
+					"validation error (condition_dependency_mismatch): question 'q1' conditions don't match the declared dependencies []"))
 			})
 
 			It("should detect condition-dependency mismatches", func() {
@@ -178,6 +221,79 @@ questions:
 				Expect(err).To(MatchError("questionnaire validation failed: validation error (circular_dependency): circular dependency detected: q1 -> q3 -> q2 -> q1"))
 			})
 		})
+
+		When("questionnaire has several problems", func() {
+			It("should report all of them at once", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1"
+    answers: []
+  - id: "q1"
+    text: "Question 2"
+    answers: ["Yes", "No"]
+    default: 3
+  - id: "q3"
+    text: "Question 3"
+    answers: ["Yes", "No"]
+    condition: 'answers["q1"] =='`))
+				Expect(err).To(HaveOccurred())
+
+				var validationErrors interface{ Unwrap() []error }
+				Expect(errors.As(err, &validationErrors)).To(BeTrue())
+				Expect(err.Error()).To(HavePrefix("questionnaire validation failed: "))
+				Expect(err.Error()).To(ContainSubstring("validation error (invalid_condition)"))
+				Expect(err.Error()).To(ContainSubstring("validation error (empty_answers)"))
+				Expect(err.Error()).To(ContainSubstring("validation error (duplicate_question_id)"))
+				Expect(err.Error()).To(ContainSubstring("validation error (invalid_default_answer)"))
+			})
+		})
+
+		When("conditions are not valid expressions", func() {
+			It("should fail to load with an invalid question condition", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Answer 1", "Answer 2"]
+    condition: '1 : 2'`))
+				Expect(err).To(MatchError(ContainSubstring("questionnaire validation failed: validation error (invalid_condition): condition '1 : 2' of 'q1' is not a valid expression")))
+			})
+
+			It("should fail to load with an invalid answer option condition", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers:
+      - text: "Answer 1"
+        condition: 'answers['`))
+				Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition): condition 'answers[' of 'q1' is not a valid expression")))
+			})
+
+			It("should fail to load with an invalid closing remark condition", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Answer 1", "Answer 2"]
+closing_remarks:
+  - id: "invalid"
+    text: "Invalid remark"
+    condition: '1 : 2'`))
+				Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition): condition '1 : 2' of 'invalid' is not a valid expression")))
+			})
+
+			It("should fail to load when a condition uses an unknown variable", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Answer 1", "Answer 2"]
+    condition: 'unknown == 1'`))
+				Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition)")))
+			})
+		})
 	})
 
 	Describe("Next", func() {
@@ -224,7 +340,8 @@ questions:
 				}))
 				Expect(r.Completed).To(BeFalse())
 				Expect(r.ClosingRemarks).To(BeEmpty())
-				Expect(r.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 2}))
+				// q3 is still ahead when q1 is answered with "Yes"
+				Expect(r.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 3, Remaining: 3, Percent: 0}))
 			})
 		})
 
@@ -239,7 +356,7 @@ questions:
 				Expect(err).ToNot(HaveOccurred())
 				Expect(r.Questions).To(BeEmpty())
 				Expect(r.Completed).To(BeTrue())
-				Expect(r.Progress).To(BeNil())
+				Expect(r.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 0, Percent: 100}))
 			})
 		})
 
@@ -329,29 +446,11 @@ questions:
 				Expect(err).ToNot(HaveOccurred())
 				Expect(r.Questions).To(BeEmpty())
 				Expect(r.Completed).To(BeTrue())
-				Expect(r.Progress).To(BeNil())
+				Expect(r.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 1, Percent: 100}))
 			})
 		})
 
 		When("the questionnaire has invalid conditions", func() {
-			When("condition is not a valid expression", func() {
-				BeforeEach(func() {
-					config = `
-questions:
-  - id: "q1"
-    text: "Question 1?"
-    answers:
-      - "Answer 1"
-      - "Answer 2"
-    condition: '1 : 2'`
-				})
-
-				It("should return an error", func() {
-					_, err = q.Next(map[string]int{})
-					Expect(err).To(MatchError(ContainSubstring("failed to show question: failed to compile condition expression: ")))
-				})
-			})
-
 			When("condition does not return a boolean", func() {
 				BeforeEach(func() {
 					config = `
@@ -528,28 +627,6 @@ questions:
 		})
 
 		When("questionnaire has invalid closing remark conditions", func() {
-			When("condition is not a valid expression", func() {
-				BeforeEach(func() {
-					config = `
-questions:
-  - id: "q1"
-    text: "Question 1?"
-    answers:
-      - "Answer 1"
-      - "Answer 2"
-
-closing_remarks:
-  - id: "invalid"
-    text: "Invalid remark"
-    condition: '1 : 2'`
-				})
-
-				It("should return an error", func() {
-					_, err = q.Next(map[string]int{"q1": 1})
-					Expect(err).To(MatchError(ContainSubstring("failed to evaluate closing remark condition: failed to compile condition expression: ")))
-				})
-			})
-
 			When("condition does not return a boolean", func() {
 				BeforeEach(func() {
 					config = `
@@ -618,19 +695,4986 @@ questions:
 			})
 
 			It("should calculate progress correctly for different paths", func() {
+				// Path A is the longest one
 				response, err := q.Next(map[string]int{})
 				Expect(err).ToNot(HaveOccurred())
-				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 1}))
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 3, Remaining: 3, Percent: 0}))
 
 				response, err = q.Next(map[string]int{"q1": 1})
 				Expect(err).ToNot(HaveOccurred())
-				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 3}))
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 3, Remaining: 2, Percent: 33}))
+
+				response, err = q.Next(map[string]int{"q1": 2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 2, Remaining: 1, Percent: 50}))
 
 				response, err = q.Next(map[string]int{"q1": 1, "q2a": 1, "q3a": 2})
 				Expect(err).ToNot(HaveOccurred())
 				Expect(response.Completed).To(BeTrue())
-				Expect(response.Progress).To(BeNil())
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 3, Total: 3, Percent: 100}))
+			})
+		})
+
+		When("questions declare a weight", func() {
+			BeforeEach(func() {
+				config = `
+questions:
+  - id: "q1"
+    text: "Path selector?"
+    answers: ["Path A", "Path B"]
+  - id: "matrix"
+    text: "Rate each feature"
+    answers: ["Good", "Bad"]
+    weight: 4
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+  - id: "q2b"
+    text: "Question 2B?"
+    answers: ["Yes", "No"]
+    weight: 2
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'`
+			})
+
+			It("should count the questions by weight", func() {
+				response, err := q.Next(map[string]int{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 5, Remaining: 5, Percent: 0}))
+
+				response, err = q.Next(map[string]int{"q1": 2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 3, Remaining: 2, Percent: 33}))
+
+				response, err = q.Next(map[string]int{"q1": 1, "matrix": 1})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.Completed).To(BeTrue())
+				Expect(response.Progress).To(Equal(&gdq.Progress{Current: 5, Total: 5, Percent: 100}))
+			})
+
+			It("should reject negative weights", func() {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question?"
+    answers: ["Yes", "No"]
+    weight: -2`))
+				Expect(err).To(MatchError(gdq.ErrInvalidWeight))
+			})
+		})
+
+		When("branches are nested", func() {
+			BeforeEach(func() {
+				config = `
+questions:
+  - id: "q1"
+    text: "Path selector?"
+    answers: ["Path A", "Path B"]
+  - id: "q2a"
+    text: "Question 2A?"
+    answers: ["Go deeper", "Stop"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+  - id: "q3a"
+    text: "Question 3A?"
+    answers: ["Yes", "No"]
+    depends_on: ["q2a"]
+    condition: 'answers["q2a"] == 1'
+  - id: "q4a"
+    text: "Question 4A?"
+    answers: ["Yes", "No"]
+    depends_on: ["q3a"]
+    condition: 'answered("q3a")'
+  - id: "q2b"
+    text: "Question 2B?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'
+  - id: "q3b"
+    text: "Question 3B?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'
+  - id: "last"
+    text: "Any comment?"
+    answers: ["Yes", "No"]`
+			})
+
+			It("should never increase the total along a path", func() {
+				steps := []struct {
+					answers map[string]int
+					total   int
+				}{
+					{map[string]int{}, 5},
+					{map[string]int{"q1": 1}, 5},
+					{map[string]int{"q1": 1, "q2a": 1}, 5},
+					{map[string]int{"q1": 1, "q2a": 2}, 3},
+					{map[string]int{"q1": 2}, 4},
+				}
+				for _, step := range steps {
+					response, err := q.Next(step.answers)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(response.Progress.Total).To(Equal(step.total), "answers %v", step.answers)
+				}
 			})
 		})
 	})
-})
+
+	Describe("Time Estimates", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Path selector?"
+    answers: ["Path A", "Path B"]
+    time_estimate: 5
+  - id: "q2a"
+    text: "Question 2A?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+    time_estimate: 30
+  - id: "q2b"
+    text: "Question 2B?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'
+    time_estimate: 10
+  - id: "q3b"
+    text: "Question 3B?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'
+    time_estimate: 10
+  - id: "last"
+    text: "Any comment?"
+    answers: ["Yes", "No"]`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should expose the estimate of each question", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].TimeEstimate).To(Equal(5))
+			Expect(r.Questions[1].TimeEstimate).To(BeZero())
+		})
+
+		It("should estimate the time left on the longest path", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.RemainingTime).To(Equal(35))
+
+			r, err = q.Next(map[string]int{"q1": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.RemainingTime).To(Equal(20))
+
+			r, err = q.Next(map[string]int{"q1": 2, "q2b": 1, "q3b": 1, "last": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.RemainingTime).To(BeZero())
+		})
+
+		It("should reject negative estimates", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question?"
+    answers: ["Yes", "No"]
+    time_estimate: -5`))
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, gdq.ErrInvalidTimeEstimate)).To(BeTrue())
+		})
+	})
+
+	Describe("Explain Mode", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "experience"
+    text: "Do you have programming experience?"
+    answers: ["Yes", "No"]
+  - id: "language"
+    text: "Which language do you prefer?"
+    answers: ["Go", "Python"]
+    depends_on: ["experience"]
+    condition: 'answers["experience"] == 1'
+  - id: "years"
+    text: "How many years of Go?"
+    answers: ["Less than 2", "2 or more"]
+    depends_on: ["language"]
+    condition: 'answers["language"] == 1'
+  - id: "nickname"
+    text: "Do you want to share a nickname?"
+    answers: ["Yes", "No"]
+    required: false`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not explain by default", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Explanations).To(BeNil())
+		})
+
+		It("should explain why each question is shown or hidden", func() {
+			yes, no := true, false
+			r, err := q.Next(map[string]int{"experience": 2}, gdq.WithExplain(), gdq.WithPageSize(1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Explanations).To(Equal([]gdq.Explanation{
+				{QuestionID: "experience", Reason: gdq.AnsweredReason},
+				{QuestionID: "language", Reason: gdq.ConditionNotMetReason, Condition: `answers["experience"] == 1`, ConditionResult: &no},
+				{QuestionID: "years", Reason: gdq.MissingDependenciesReason, Condition: `answers["language"] == 1`, MissingDependencies: []string{"language"}},
+				{QuestionID: "nickname", Shown: true, Reason: gdq.ShownReason},
+			}))
+			Expect(r.Completed).To(BeTrue())
+
+			r, err = q.Next(map[string]int{"experience": 1}, gdq.WithExplain(), gdq.WithPageSize(1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Explanations[1]).To(Equal(gdq.Explanation{QuestionID: "language", Shown: true, Reason: gdq.ShownReason, Condition: `answers["experience"] == 1`, ConditionResult: &yes}))
+			Expect(r.Explanations[3]).To(Equal(gdq.Explanation{QuestionID: "nickname", Reason: gdq.OutsidePageReason}))
+		})
+	})
+
+	Describe("Question Shuffling", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		questionIDs := func(r *gdq.Response) []string {
+			var ids []string
+			for _, question := range r.Questions {
+				ids = append(ids, question.Id)
+			}
+			return ids
+		}
+
+		When("shuffling is enabled", func() {
+			BeforeEach(func() {
+				config = `
+shuffle_questions: true
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+  - id: "q4"
+    text: "Question 4?"
+    answers: ["Yes", "No"]
+  - id: "q5"
+    text: "Question 5?"
+    answers: ["Yes", "No"]
+  - id: "q6"
+    text: "Question 6?"
+    answers: ["Yes", "No"]`
+			})
+
+			It("should return the same order for the same seed", func() {
+				first, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+				Expect(err).ToNot(HaveOccurred())
+				second, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(questionIDs(first)).To(Equal(questionIDs(second)))
+				Expect(questionIDs(first)).To(ConsistOf("q1", "q2", "q3", "q4", "q5", "q6"))
+			})
+
+			It("should keep the relative order of the remaining questions across calls", func() {
+				first, err := q.Next(map[string]int{}, gdq.WithSeed(7))
+				Expect(err).ToNot(HaveOccurred())
+				answered := questionIDs(first)[0]
+
+				second, err := q.Next(map[string]int{answered: 1}, gdq.WithSeed(7))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(questionIDs(second)).To(Equal(questionIDs(first)[1:]))
+			})
+
+			It("should produce different orders for different seeds", func() {
+				orders := map[string]bool{}
+				for seed := uint64(0); seed < 10; seed++ {
+					r, err := q.Next(map[string]int{}, gdq.WithSeed(seed))
+					Expect(err).ToNot(HaveOccurred())
+					orders[fmt.Sprint(questionIDs(r))] = true
+				}
+				Expect(len(orders)).To(BeNumerically(">", 1))
+			})
+		})
+
+		When("shuffling is disabled", func() {
+			BeforeEach(func() {
+				config = `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]`
+			})
+
+			It("should ignore the seed and keep the configuration order", func() {
+				r, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(questionIDs(r)).To(Equal([]string{"q1", "q2", "q3"}))
+			})
+		})
+	})
+
+	Describe("Answer Shuffling", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "color"
+    text: "Favorite color?"
+    answers: ["Red", "Green", "Blue", "Yellow", "Purple", "Orange"]
+    shuffle_answers: true
+  - id: "plain"
+    text: "Plain question?"
+    answers: ["Yes", "No"]
+  - id: "follow_up"
+    text: "Why blue?"
+    answers: ["Calm", "Sky"]
+    depends_on: ["color"]
+    condition: 'answers["color"] == 3'`
+		})
+
+		It("should map each displayed answer back to its canonical index", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+			Expect(err).ToNot(HaveOccurred())
+
+			color := r.Questions[0]
+			Expect(color.Answers).To(ConsistOf("Red", "Green", "Blue", "Yellow", "Purple", "Orange"))
+			Expect(color.AnswerIndices).To(ConsistOf(1, 2, 3, 4, 5, 6))
+			canonical := []string{"Red", "Green", "Blue", "Yellow", "Purple", "Orange"}
+			for i, answer := range color.Answers {
+				Expect(canonical[color.AnswerIndices[i]-1]).To(Equal(answer))
+			}
+		})
+
+		It("should return a stable order for the same seed", func() {
+			first, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+			Expect(err).ToNot(HaveOccurred())
+			second, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.Questions[0].Answers).To(Equal(second.Questions[0].Answers))
+		})
+
+		It("should not shuffle questions without shuffle_answers", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithSeed(42))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[1]).To(Equal(gdq.Question{Id: "plain", Text: "Plain question?", Answers: []string{"Yes", "No"}}))
+		})
+
+		It("should evaluate conditions on the canonical values", func() {
+			r, err := q.Next(map[string]int{"color": 3, "plain": 1}, gdq.WithSeed(42))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("follow_up"))
+		})
+	})
+
+	Describe("Optional Questions", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "name"
+    text: "Do you want to share your name?"
+    answers: ["Yes", "No"]
+  - id: "nickname"
+    text: "Do you have a nickname?"
+    answers: ["Yes", "No"]
+    required: false
+  - id: "newsletter"
+    text: "Subscribe to the newsletter?"
+    answers: ["Yes", "No"]
+    depends_on: ["name"]
+    condition: 'answers["name"] == 1'
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`
+		})
+
+		It("should flag optional questions", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "name", Text: "Do you want to share your name?", Answers: []string{"Yes", "No"}},
+				{Id: "nickname", Text: "Do you have a nickname?", Answers: []string{"Yes", "No"}, Optional: true},
+			}))
+		})
+
+		It("should accept the skip answer for optional questions", func() {
+			r, err := q.Next(map[string]int{"name": 1, "nickname": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("newsletter"))
+			Expect(r.Progress).To(Equal(&gdq.Progress{Current: 2, Total: 3, Remaining: 1, Percent: 66}))
+		})
+
+		It("should reject the skip answer for required questions", func() {
+			_, err := q.Next(map[string]int{"name": gdq.SkipAnswer})
+			Expect(err).To(MatchError("invalid answers provided: validation error (invalid_answer_range): answer is out of range"))
+		})
+
+		It("should complete once all required questions are answered", func() {
+			r, err := q.Next(map[string]int{"name": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "nickname", Text: "Do you have a nickname?", Answers: []string{"Yes", "No"}, Optional: true},
+			}))
+			Expect(r.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 1, Percent: 100}))
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "thanks", Text: "Thank you!"}}))
+
+			r, err = q.Next(map[string]int{"name": 2, "nickname": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Questions).To(BeEmpty())
+		})
+
+		It("should return the trailing optional questions once completed", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "rating"
+    text: "How would you rate us?"
+    answers: ["Good", "Bad"]
+  - id: "comment"
+    text: "Anything to add?"
+    type: "text"
+    required: false
+    depends_on: ["rating"]
+    condition: 'answered("rating")'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeFalse())
+			Expect(r.Questions).To(HaveLen(1))
+
+			r, err = q.Next(map[string]int{"rating": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("comment"))
+			Expect(r.Questions[0].Optional).To(BeTrue())
+		})
+	})
+
+	Describe("Skippable Questions", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "income"
+    text: "What is your income range?"
+    answers: ["Low", "Medium", "High"]
+    skippable: true
+  - id: "why"
+    text: "Why did you prefer not to say?"
+    answers: ["Privacy", "Other"]
+    depends_on: ["income"]
+    condition: 'skipped("income")'
+closing_remarks:
+  - id: "skipped"
+    text: "No worries."
+    condition: 'skipped("income")'
+  - id: "answered"
+    text: "Thanks for sharing."
+    condition: '!skipped("income")'`
+		})
+
+		It("should flag skippable questions as required", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "income", Text: "What is your income range?", Answers: []string{"Low", "Medium", "High"}, Skippable: true},
+			}))
+		})
+
+		It("should let conditions test whether a question was skipped", func() {
+			r, err := q.Next(map[string]int{"income": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("why"))
+
+			r, err = q.Next(map[string]int{"income": gdq.SkipAnswer, "why": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "skipped", Text: "No worries."}}))
+		})
+
+		It("should distinguish a real choice from a skip", func() {
+			r, err := q.Next(map[string]int{"income": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "answered", Text: "Thanks for sharing."}}))
+		})
+	})
+
+	Describe("Default Answers", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "country"
+    text: "Where do you live?"
+    answers: ["France", "Germany"]
+    default: 1
+  - id: "region"
+    text: "Which region?"
+    answers: ["North", "South"]
+    depends_on: ["country"]
+    condition: 'answers["country"] == 1'
+    default: 2
+  - id: "age"
+    text: "How old are you?"
+    answers: ["Under 18", "18 or over"]`
+		})
+
+		It("should expose the default without applying it", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "country", Text: "Where do you live?", Answers: []string{"France", "Germany"}, Default: 1},
+				{Id: "age", Text: "How old are you?", Answers: []string{"Under 18", "18 or over"}},
+			}))
+			Expect(r.Defaulted).To(BeNil())
+		})
+
+		It("should apply defaults, including for questions they unlock", func() {
+			answers := map[string]int{}
+			r, err := q.Next(answers, gdq.WithDefaults())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Defaulted).To(Equal(map[string]int{"country": 1, "region": 2}))
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("age"))
+			Expect(r.Progress).To(Equal(&gdq.Progress{Current: 2, Total: 3, Remaining: 1, Percent: 66}))
+			Expect(answers).To(BeEmpty())
+		})
+
+		It("should not override provided answers", func() {
+			r, err := q.Next(map[string]int{"country": 2}, gdq.WithDefaults())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Defaulted).To(BeNil())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("age"))
+		})
+	})
+
+	Describe("Early Termination", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "age"
+    text: "Are you over 18?"
+    answers:
+      - "Yes"
+      - text: "No"
+        terminates: true
+  - id: "income"
+    text: "What is your income range?"
+    answers: ["Low", "Medium", "High"]
+    required: false
+    terminate_if: 'answers["income"] == 1'
+  - id: "job"
+    text: "Do you have a job?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "not_eligible"
+    text: "Sorry, this survey is for adults only."
+    condition: 'answers["age"] == 2'
+  - id: "thanks"
+    text: "Thank you!"
+    condition: 'answers["age"] == 1'`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should end the questionnaire when a terminating answer is chosen", func() {
+			r, err := q.Next(map[string]int{"age": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(BeEmpty())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Terminated).To(BeTrue())
+			Expect(r.Progress).To(Equal(&gdq.Progress{Current: 1, Total: 1, Percent: 100}))
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "not_eligible", Text: "Sorry, this survey is for adults only."}}))
+		})
+
+		It("should end the questionnaire when a termination condition is met", func() {
+			r, err := q.Next(map[string]int{"age": 1, "income": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Terminated).To(BeTrue())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "thanks", Text: "Thank you!"}}))
+		})
+
+		It("should continue otherwise", func() {
+			r, err := q.Next(map[string]int{"age": 1, "income": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeFalse())
+			Expect(r.Terminated).To(BeFalse())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("job"))
+
+			r, err = q.Next(map[string]int{"age": 1, "income": gdq.SkipAnswer, "job": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Terminated).To(BeFalse())
+		})
+
+		It("should complete the questionnaire once the completion condition holds", func() {
+			q, err := gdq.New([]byte(`
+complete_when: 'score >= 10'
+questions:
+  - id: "q1"
+    text: "Do you smoke?"
+    answers:
+      - text: "Yes"
+        score: 10
+      - text: "No"
+        score: 0
+  - id: "q2"
+    text: "Do you practice sport?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "risk"
+    text: "Please see a doctor."
+    condition: 'score >= 10'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{"q1": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeFalse())
+			Expect(r.Questions).To(HaveLen(1))
+
+			r, err = q.Next(map[string]int{"q1": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(BeEmpty())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.Terminated).To(BeTrue())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "risk", Text: "Please see a doctor."}}))
+		})
+
+		It("should reject invalid completion conditions", func() {
+			_, err := gdq.New([]byte(`
+complete_when: 'score >='
+questions:
+  - id: "q1"
+    text: "Question?"
+    answers: ["Yes", "No"]`))
+			Expect(err).To(HaveOccurred())
+			Expect(gdq.ValidationErrors(err)).To(ContainElement(HaveField("Context", HaveKeyWithValue("setting", "complete_when"))))
+		})
+
+		It("should not report questions after a terminating answer as a dead end", func() {
+			Expect(q.Analyze()).To(BeEmpty())
+		})
+
+		It("should reject invalid termination conditions", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question?"
+    answers: ["Yes", "No"]
+    terminate_if: 'answers["q1"] =='`))
+			Expect(err).To(HaveOccurred())
+			Expect(gdq.ValidationErrors(err)).To(ContainElement(HaveField("Type", gdq.InvalidConditionErrType)))
+		})
+	})
+
+	Describe("Answer Jumps", func() {
+		It("should only show jump targets when a jumping option is chosen", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "smoker"
+    text: "Do you smoke?"
+    answers:
+      - text: "Yes, every day"
+        next: "cigarettes"
+      - text: "Sometimes"
+        next: "cigarettes"
+      - "No"
+  - id: "cigarettes"
+    text: "How many cigarettes a day?"
+    answers: ["Less than 5", "5 or more"]
+  - id: "sport"
+    text: "Do you practice sport?"
+    answers: ["Yes", "No"]
+    depends_on: ["smoker"]
+    condition: 'answered("smoker")'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("smoker"))
+
+			for _, answer := range []int{1, 2} {
+				r, err = q.Next(map[string]int{"smoker": answer})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(r.Questions).To(HaveLen(2))
+				Expect(r.Questions[0].Id).To(Equal("cigarettes"))
+				Expect(r.Questions[1].Id).To(Equal("sport"))
+			}
+
+			r, err = q.Next(map[string]int{"smoker": 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("sport"))
+		})
+
+		It("should keep the target's own condition", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "age"
+    text: "How old are you?"
+    answers: ["Under 18", "18 or over"]
+  - id: "smoker"
+    text: "Do you smoke?"
+    answers:
+      - text: "Yes"
+        next: "cigarettes"
+      - "No"
+  - id: "cigarettes"
+    text: "How many cigarettes a day?"
+    answers: ["Less than 5", "5 or more"]
+    depends_on: ["age"]
+    condition: 'answers["age"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{"age": 1, "smoker": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+
+			r, err = q.Next(map[string]int{"age": 2, "smoker": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("cigarettes"))
+		})
+
+		It("should reject invalid jumps", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "First question?"
+    answers:
+      - text: "Unknown"
+        next: "missing"
+      - text: "Self"
+        next: "q1"
+      - text: "Shared"
+        next: "q3"
+  - id: "q2"
+    text: "Second question?"
+    answers:
+      - text: "Shared"
+        next: "q3"
+  - id: "q3"
+    text: "Third question?"
+    answers: ["Yes", "No"]`))
+			Expect(err).To(HaveOccurred())
+
+			var jumpErrors []gdq.ValidationError
+			for _, validationErr := range gdq.ValidationErrors(err) {
+				if validationErr.Type == gdq.InvalidJumpErrType {
+					jumpErrors = append(jumpErrors, validationErr)
+				}
+			}
+			Expect(jumpErrors).To(HaveLen(3))
+			Expect(errors.Is(err, gdq.ErrInvalidJump)).To(BeTrue())
+			Expect(jumpErrors[0].Message).To(Equal("jump from question 'q1' to 'missing' is invalid: the question does not exist"))
+			Expect(jumpErrors[1].Message).To(Equal("jump from question 'q1' to 'q1' is invalid: a question can't jump to itself"))
+			Expect(jumpErrors[2].Message).To(Equal("jump from question 'q2' to 'q3' is invalid: the question is already the jump target of question 'q1'"))
+		})
+	})
+
+	Describe("Page Size", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "name"
+    text: "Do you want to share your name?"
+    answers: ["Yes", "No"]
+    required: false
+  - id: "age"
+    text: "How old are you?"
+    answers: ["Under 18", "18 or over"]
+  - id: "country"
+    text: "Where do you live?"
+    answers: ["France", "Germany"]`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return every eligible question by default", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(3))
+		})
+
+		It("should return the first eligible questions", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithPageSize(2))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(2))
+			Expect(r.Questions[0].Id).To(Equal("name"))
+			Expect(r.Questions[1].Id).To(Equal("age"))
+
+			r, err = q.Next(map[string]int{"name": 1}, gdq.WithPageSize(1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("age"))
+		})
+
+		It("should account for every eligible question in completion and progress", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithPageSize(1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Optional).To(BeTrue())
+			Expect(r.Completed).To(BeFalse())
+			Expect(r.Progress).To(Equal(&gdq.Progress{Current: 0, Total: 3, Remaining: 3, Percent: 0}))
+		})
+
+		It("should ignore sizes of zero or less", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithPageSize(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(3))
+
+			r, err = q.Next(map[string]int{}, gdq.WithPageSize(-1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(3))
+		})
+	})
+
+	Describe("Conditional Answer Options", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "action"
+    text: "What would you like to do?"
+    depends_on: ["plan"]
+    answers:
+      - "Keep my plan"
+      - text: "Upgrade plan"
+        condition: 'answers["plan"] == 1'
+      - "Cancel"`
+		})
+
+		It("should offer the option when its condition is met", func() {
+			r, err := q.Next(map[string]int{"plan": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "action", Text: "What would you like to do?", Answers: []string{"Keep my plan", "Upgrade plan", "Cancel"}},
+			}))
+		})
+
+		It("should hide the option and map the remaining ones to their canonical values", func() {
+			r, err := q.Next(map[string]int{"plan": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "action", Text: "What would you like to do?", Answers: []string{"Keep my plan", "Cancel"}, AnswerIndices: []int{1, 3}},
+			}))
+		})
+
+		It("should reject an option that is not offered", func() {
+			_, err := q.Next(map[string]int{"plan": 2, "action": 2})
+			Expect(err).To(MatchError("invalid answers provided: validation error (unavailable_answer): answer is not available"))
+		})
+
+		It("should require option conditions to be declared as dependencies", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "action"
+    text: "What would you like to do?"
+    depends_on: []
+    answers:
+      - "Keep my plan"
+      - text: "Upgrade plan"
+        condition: 'answers["plan"] == 1'`))
+			Expect(err).To(MatchError("questionnaire validation failed: validation error (condition_dependency_mismatch): question 'action' conditions don't match the declared dependencies [plan]"))
+		})
+
+		It("should load options declared as objects in JSON", func() {
+			q, err := gdq.New([]byte(`{
+  "questions": [
+    {"id": "plan", "text": "Which plan?", "answers": ["Free", {"text": "Pro"}]}
+  ]
+}`))
+			Expect(err).ToNot(HaveOccurred())
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Answers).To(Equal([]string{"Free", "Pro"}))
+		})
+	})
+
+	Describe("Help Text", func() {
+		It("should return the description and help of the questions", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "nps"
+    text: "How likely are you to recommend us?"
+    description: "Think about your experience over the last month."
+    help: "1 means not likely at all."
+    answers: ["1", "2", "3"]
+  - id: "plain"
+    text: "Plain question?"
+    answers: ["Yes", "No"]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{
+					Id:          "nps",
+					Text:        "How likely are you to recommend us?",
+					Description: "Think about your experience over the last month.",
+					Help:        "1 means not likely at all.",
+					Answers:     []string{"1", "2", "3"},
+				},
+				{Id: "plain", Text: "Plain question?", Answers: []string{"Yes", "No"}},
+			}))
+		})
+	})
+
+	Describe("Media", func() {
+		It("should return the media attached to questions and answers", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "product"
+    text: "Which product do you prefer?"
+    image: "https://example.com/products.png"
+    media:
+      - type: "audio"
+        url: "https://example.com/jingle.mp3"
+    answers:
+      - text: "Product A"
+        image: "https://example.com/a.png"
+      - "Product B"
+      - text: "Product C"
+        video: "https://example.com/c.mp4"
+  - id: "plain"
+    text: "Plain question?"
+    answers: ["Yes", "No"]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Media).To(Equal([]gdq.Media{
+				{Type: gdq.MediaTypeImage, URL: "https://example.com/products.png"},
+				{Type: "audio", URL: "https://example.com/jingle.mp3"},
+			}))
+			Expect(r.Questions[0].AnswerMedia).To(Equal([][]gdq.Media{
+				{{Type: gdq.MediaTypeImage, URL: "https://example.com/a.png"}},
+				nil,
+				{{Type: gdq.MediaTypeVideo, URL: "https://example.com/c.mp4"}},
+			}))
+			Expect(r.Questions[1].Media).To(BeNil())
+			Expect(r.Questions[1].AnswerMedia).To(BeNil())
+		})
+	})
+
+	Describe("Metadata", func() {
+		It("should pass question and remark metadata through untouched", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "rating"
+    text: "How would you rate us?"
+    answers: ["Bad", "Good"]
+    metadata:
+      widget: "stars"
+      highlight: true
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"
+    metadata:
+      icon: "heart"`))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Metadata).To(Equal(map[string]interface{}{"widget": "stars", "highlight": true}))
+
+			r, err = q.Next(map[string]int{"rating": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{
+				{Id: "thanks", Text: "Thank you!", Metadata: map[string]interface{}{"icon": "heart"}},
+			}))
+		})
+	})
+
+	Describe("Translations", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+default_locale: "en"
+questions:
+  - id: "satisfaction"
+    text:
+      en: "Are you satisfied?"
+      fr: "Êtes-vous satisfait ?"
+    answers:
+      - text:
+          en: "Yes"
+          fr: "Oui"
+      - "No"
+closing_remarks:
+  - id: "thanks"
+    text:
+      en: "Thank you!"
+      fr: "Merci !"`
+		})
+
+		It("should return the texts in the requested locale", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithLocale("fr"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(Equal([]gdq.Question{
+				{Id: "satisfaction", Text: "Êtes-vous satisfait ?", Answers: []string{"Oui", "No"}},
+			}))
+
+			r, err = q.Next(map[string]int{"satisfaction": 1}, gdq.WithLocale("fr-CA"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "thanks", Text: "Merci !"}}))
+		})
+
+		It("should fall back to the default locale", func() {
+			r, err := q.Next(map[string]int{}, gdq.WithLocale("de"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Text).To(Equal("Are you satisfied?"))
+			Expect(r.Questions[0].Answers).To(Equal([]string{"Yes", "No"}))
+		})
+
+		It("should use the default locale when no locale is requested", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Text).To(Equal("Are you satisfied?"))
+		})
+
+		It("should load translations from JSON", func() {
+			q, err := gdq.New([]byte(`{
+  "questions": [
+    {"id": "q1", "text": {"en": "Hello", "fr": "Bonjour"}, "answers": [{"text": {"en": "Yes", "fr": "Oui"}}]}
+  ]
+}`))
+			Expect(err).ToNot(HaveOccurred())
+			r, err := q.Next(map[string]int{}, gdq.WithLocale("fr"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].Text).To(Equal("Bonjour"))
+			Expect(r.Questions[0].Answers).To(Equal([]string{"Oui"}))
+		})
+	})
+
+	Describe("Answer Text In Conditions", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+default_locale: "en"
+questions:
+  - id: "language"
+    text: "Favorite language?"
+    answers:
+      - "Python"
+      - text: { en: "Go", fr: "Go (fr)" }
+    skippable: true
+  - id: "gopher"
+    text: "Are you a gopher?"
+    answers: ["Yes", "No"]
+    depends_on: ["language"]
+    condition: 'answerText("language") == "Go"'
+closing_remarks:
+  - id: "language"
+    text: "You picked a language."
+    condition: 'answerText("language") != ""'`
+		})
+
+		It("should evaluate conditions against the chosen answer text", func() {
+			r, err := q.Next(map[string]int{"language": 2}, gdq.WithLocale("fr"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("gopher"))
+
+			r, err = q.Next(map[string]int{"language": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(HaveLen(1))
+		})
+
+		It("should return an empty text for skipped questions", func() {
+			r, err := q.Next(map[string]int{"language": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(BeEmpty())
+		})
+	})
+
+	Describe("Answer IDs", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers:
+      - id: "free"
+        text: "Free"
+      - id: "pro"
+        text: "Pro"
+  - id: "color"
+    text: "Favorite color?"
+    answers: ["Red", "Blue"]`
+		})
+
+		It("should return the answer IDs", func() {
+			r, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions[0].AnswerIds).To(Equal([]string{"free", "pro"}))
+			Expect(r.Questions[1].AnswerIds).To(BeNil())
+		})
+
+		It("should resolve answer IDs into answer choices", func() {
+			answers, err := q.ResolveAnswers(map[string]string{"plan": "pro"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(answers).To(Equal(map[string]int{"plan": 2}))
+		})
+
+		It("should reject unknown answer IDs", func() {
+			_, err := q.ResolveAnswers(map[string]string{"plan": "enterprise"})
+			Expect(err).To(MatchError("validation error (invalid_answer_id): answer ID does not exist"))
+
+			_, err = q.ResolveAnswers(map[string]string{"color": "Red"})
+			Expect(err).To(MatchError("validation error (invalid_answer_id): answer ID does not exist"))
+		})
+
+		It("should reject unknown questions", func() {
+			_, err := q.ResolveAnswers(map[string]string{"nonexistent": "pro"})
+			Expect(err).To(MatchError("validation error (invalid_question_id): question does not exist"))
+		})
+	})
+
+	Describe("Custom Functions", func() {
+		config := []byte(`
+questions:
+  - id: "age"
+    text: "How old are you?"
+    answers: ["Under 18", "18-65", "Over 65"]
+  - id: "drink"
+    text: "Would you like a beer?"
+    answers: ["Yes", "No"]
+    depends_on: ["age"]
+    condition: 'isAdult(answers["age"])'
+closing_remarks:
+  - id: "senior"
+    text: "Enjoy your retirement!"
+    condition: 'between(answers["age"], 3, 3)'`)
+		functions := map[string]interface{}{
+			"isAdult": func(age int) bool { return age >= 2 },
+			"between": func(value, min, max int) bool { return value >= min && value <= max },
+		}
+
+		It("should make the functions available in conditions", func() {
+			q, err := gdq.New(config, gdq.WithFunctions(functions))
+			Expect(err).ToNot(HaveOccurred())
+
+			r, err := q.Next(map[string]int{"age": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+
+			r, err = q.Next(map[string]int{"age": 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("drink"))
+
+			r, err = q.Next(map[string]int{"age": 3, "drink": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "senior", Text: "Enjoy your retirement!"}}))
+		})
+
+		It("should fail when a condition uses an unknown function", func() {
+			_, err := gdq.New(config)
+			Expect(err).To(MatchError(ContainSubstring("questionnaire validation failed: validation error (invalid_condition): condition 'isAdult(answers[\"age\"])' of 'drink' is not a valid expression")))
+		})
+
+		It("should reject functions shadowing built-in helpers", func() {
+			_, err := gdq.New(config, gdq.WithFunctions(map[string]interface{}{
+				"skipped": func(string) bool { return false },
+			}))
+			Expect(err).To(MatchError(`invalid options: function "skipped" conflicts with a built-in condition helper`))
+		})
+
+		It("should reject values that are not functions", func() {
+			_, err := gdq.New(config, gdq.WithFunctions(map[string]interface{}{"threshold": 3}))
+			Expect(err).To(MatchError(`invalid options: function "threshold" is not a function: got int`))
+		})
+	})
+
+	Describe("Scoring", func() {
+		var (
+			config string
+			q      gdq.Questionnaire
+			err    error
+		)
+		JustBeforeEach(func() {
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		BeforeEach(func() {
+			config = `
+questions:
+  - id: "q1"
+    text: "2 + 2?"
+    answers:
+      - text: "4"
+        score: 10
+      - "5"
+  - id: "q2"
+    text: "3 * 3?"
+    answers:
+      - text: "9"
+        score: 5.5
+      - "6"
+    skippable: true
+  - id: "hard"
+    text: "Integral of 1/x?"
+    answers: ["ln(x)", "x"]
+    depends_on: ["q1", "q2"]
+    condition: 'allAnswered("q1", "q2") && score > 10'
+closing_remarks:
+  - id: "expert"
+    text: "Well done!"
+    condition: 'score >= 15'`
+		})
+
+		It("should return the running score", func() {
+			r, err := q.Next(map[string]int{"q1": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Score).To(Equal(10.0))
+
+			r, err = q.Next(map[string]int{"q1": 2, "q2": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Score).To(BeZero())
+		})
+
+		It("should branch on the score", func() {
+			r, err := q.Next(map[string]int{"q1": 1, "q2": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(BeEmpty())
+
+			r, err = q.Next(map[string]int{"q1": 1, "q2": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("hard"))
+
+			r, err = q.Next(map[string]int{"q1": 1, "q2": 1, "hard": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "expert", Text: "Well done!"}}))
+		})
+	})
+
+	Describe("Expression Limits", func() {
+		config := []byte(`
+questions:
+  - id: "name"
+    text: "What is your name?"
+    answers: ["Alice", "Bob"]
+  - id: "greeting"
+    text: "Shall we greet you?"
+    answers: ["Yes", "No"]
+    depends_on: ["name"]
+    condition: 'repeat(answerText("name"), 2) == "AliceAlice"'`)
+
+		It("should accept conditions within the limits", func() {
+			_, err := gdq.New(config, gdq.WithMaxExpressionLength(100), gdq.WithDisallowedBuiltins("split"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject conditions longer than the maximum length", func() {
+			_, err := gdq.New(config, gdq.WithMaxExpressionLength(20))
+			Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition): condition 'repeat(answerText(\"name\"), 2) == \"AliceAlice\"' of 'greeting' is not a valid expression: expression length 45 exceeds the maximum of 20")))
+		})
+
+		It("should reject conditions using disallowed builtins", func() {
+			_, err := gdq.New(config, gdq.WithDisallowedBuiltins("repeat"))
+			Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition)")))
+		})
+
+		It("should reject conditions with more nodes than the maximum", func() {
+			_, err := gdq.New(config, gdq.WithMaxExpressionNodes(5))
+			Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition)")))
+
+			_, err = gdq.New(config, gdq.WithMaxExpressionNodes(100))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should abort evaluations exceeding the memory budget", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    condition: 'len(map(1..1000, #)) > 0'`),
+				gdq.WithEvaluationMemoryBudget(100))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(ContainSubstring("memory budget exceeded")))
+		})
+
+		It("should abort calls exceeding the maximum evaluation depth", func() {
+			// Questions are declared in reverse order, so that each round of defaults unlocks a single question
+			chain := []byte(`
+questions:
+  - id: "q4"
+    text: "Question 4?"
+    answers: ["Yes", "No"]
+    default: 1
+    condition: 'answers["q3"] == 1'
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    default: 1
+    condition: 'answers["q2"] == 1'
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    default: 1
+    condition: 'answers["q1"] == 1'
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`)
+
+			q, err := gdq.New(chain, gdq.WithMaxEvaluationDepth(4))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = q.Next(map[string]int{"q1": 1}, gdq.WithDefaults())
+			Expect(err).ToNot(HaveOccurred())
+
+			q, err = gdq.New(chain, gdq.WithMaxEvaluationDepth(3))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(gdq.ErrEvaluationDepthExceeded))
+			Expect(err).To(MatchError("failed to calculate progress: maximum evaluation depth exceeded: dependency chain exceeded the maximum depth of 3"))
+
+			_, err = q.Next(map[string]int{"q1": 1}, gdq.WithDefaults())
+			Expect(err).To(MatchError(gdq.ErrEvaluationDepthExceeded))
+			Expect(err).To(MatchError(ContainSubstring("default answers exceeded the maximum depth of 3")))
+		})
+	})
+
+	Describe("Strict Validation", func() {
+		It("should accept valid questionnaires", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "yes"
+    text: "Great!"
+    condition: 'answers["q1"] == 1'`), gdq.WithStrictValidation())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject conditions that are not boolean", func() {
+			config := []byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "yes"
+    text: "Great!"
+    condition: 'answers["q1"] + 1'`)
+
+			_, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = gdq.New(config, gdq.WithStrictValidation())
+			Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition): condition 'answers[\"q1\"] + 1' of 'yes' is not a valid expression")))
+		})
+
+		It("should reject conditions referencing unknown questions", func() {
+			config := []byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "yes"
+    text: "Great!"
+    condition: 'answered("q2")'`)
+
+			_, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = gdq.New(config, gdq.WithStrictValidation())
+			Expect(err).To(MatchError("questionnaire validation failed: validation error (unknown_question_reference): condition 'answered(\"q2\")' of 'yes' references non-existent question 'q2'"))
+		})
+	})
+
+	Describe("Type Checking", func() {
+		const questions = `
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "seats"
+    text: "How many seats do you need?"
+    type: "number"
+  - id: "company"
+    text: "What is your company name?"
+    type: "text"
+computed:
+  seats_per_plan: 'values["seats"] / 2'
+closing_remarks:
+  - id: "remark"
+    text: "Thanks!"
+`
+
+		It("should accept conditions matching the types of the answers", func() {
+			_, err := gdq.New([]byte(questions + `    condition: 'values["seats"] > 10 && values["company"] startsWith "A" && computed["seats_per_plan"] > 1'`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject conditions comparing answers to values of another type", func() {
+			for _, condition := range []string{
+				`answers["plan"] == "Pro"`,
+				`values["seats"] == "ten"`,
+				`values["company"] > 3`,
+				`computed["seats_per_plan"] == "many"`,
+			} {
+				_, err := gdq.New([]byte(questions + `    condition: '` + condition + `'`))
+				Expect(err).To(MatchError(gdq.ErrInvalidCondition), condition)
+				Expect(err).To(MatchError(ContainSubstring("mismatched types")), condition)
+			}
+		})
+
+		It("should reject conditions indexing unknown keys in strict validation mode", func() {
+			for _, condition := range []string{`values["plan"] == 1`, `computed["unknown"] == 1`} {
+				config := []byte(questions + `    condition: '` + condition + `'`)
+				_, err := gdq.New(config)
+				Expect(err).ToNot(HaveOccurred(), condition)
+
+				_, err = gdq.New(config, gdq.WithStrictValidation())
+				Expect(err).To(MatchError(gdq.ErrInvalidCondition), condition)
+			}
+
+			_, err := gdq.New([]byte(questions+`    condition: 'values["unknown"] == 1'`), gdq.WithStrictValidation())
+			Expect(err).To(MatchError(gdq.ErrUnknownQuestionReference))
+		})
+	})
+
+	Describe("Condition Macros", func() {
+		config := `
+macros:
+  is_enterprise: 'answers["size"] == 3'
+questions:
+  - id: "size"
+    text: "How big is your company?"
+    answers: ["Small", "Medium", "Large"]
+  - id: "manager"
+    text: "Would you like to talk to an account manager?"
+    answers: ["Yes", "No"]
+    condition: '$is_enterprise'
+  - id: "discount"
+    text: "Would you like a volume discount?"
+    answers: ["Yes", "No"]
+    depends_on: ["size", "manager"]
+    condition: '$is_enterprise && answers["manager"] == 2'
+closing_remarks:
+  - id: "enterprise"
+    text: "Welcome, enterprise customer! (costs $is_enterprise)"
+    condition: '$is_enterprise && answerText("size") != "$is_enterprise"'`
+
+		It("should expand macros in conditions", func() {
+			q, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"size": 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("manager"))
+
+			response, err = q.Next(map[string]int{"size": 3, "manager": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("discount"))
+
+			response, err = q.Next(map[string]int{"size": 3, "manager": 2, "discount": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+
+			response, err = q.Next(map[string]int{"size": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(BeEmpty())
+		})
+
+		It("should reject references to undefined macros", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    condition: '$undefined'`))
+			Expect(err).To(MatchError(ContainSubstring("validation error (invalid_condition): condition '$undefined' of 'q1' is not a valid expression: macro 'undefined' is not defined")))
+		})
+
+		It("should reject invalid macros", func() {
+			_, err := gdq.New([]byte(`
+macros:
+  empty: ''
+  nested: '$empty'
+  env: 'true'
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`))
+			Expect(err).To(MatchError(gdq.ErrInvalidCondition))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(3))
+		})
+	})
+
+	Describe("Declarative Conditions", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "pet"
+    text: "Do you have a pet?"
+    answers: ["Dog", "Cat", "None"]
+  - id: "walks"
+    text: "How often do you walk your dog?"
+    answers:
+      - "Daily"
+      - "Weekly"
+      - text: "Never, I have a garden"
+        when:
+          question: "pet"
+          equals: 1
+    depends_on: ["pet"]
+    when:
+      question: "pet"
+      equals: 1
+  - id: "food"
+    text: "Which food do you buy?"
+    answers: ["Dry", "Wet"]
+    depends_on: ["pet"]
+    when:
+      question: "pet"
+      in: [1, 2]
+closing_remarks:
+  - id: "no-pet"
+    text: "Maybe adopt one?"
+    when:
+      any:
+        - question: "pet"
+          equals: 3
+        - not:
+            question: "pet"
+            answered: true
+`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should show questions matching their rule", func() {
+			r, err := q.Next(map[string]int{"pet": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(2))
+			Expect(r.Questions[0].Id).To(Equal("walks"))
+			Expect(r.Questions[0].Answers).To(HaveLen(3))
+			Expect(r.Questions[1].Id).To(Equal("food"))
+
+			r, err = q.Next(map[string]int{"pet": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Questions).To(HaveLen(1))
+			Expect(r.Questions[0].Id).To(Equal("food"))
+		})
+
+		It("should show closing remarks matching their rule", func() {
+			r, err := q.Next(map[string]int{"pet": 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Completed).To(BeTrue())
+			Expect(r.ClosingRemarks).To(Equal([]gdq.ClosingRemark{{Id: "no-pet", Text: "Maybe adopt one?"}}))
+		})
+
+		It("should reject incomplete rules", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    when:
+      question: "q1"`))
+			Expect(err).To(MatchError(ContainSubstring("questionnaire validation failed: validation error (invalid_when_rule): when rule of 'q2' is invalid: rule on question 'q1' has no comparison")))
+		})
+	})
+
+	Describe("Unreachable Questions", func() {
+		It("should not report warnings for reachable questions", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(BeEmpty())
+		})
+
+		It("should report questions whose condition can never be satisfied", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 3'
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    depends_on: ["q2"]
+    condition: 'answered("q2")'
+  - id: "q4"
+    text: "Question 4?"
+    answers: ["Yes", "No"]
+    required: false
+  - id: "q5"
+    text: "Question 5?"
+    answers: ["Yes", "No"]
+    depends_on: ["q4"]
+    condition: 'skipped("q4")'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(Equal([]gdq.Warning{
+				{
+					Type:       gdq.UnreachableQuestionWarning,
+					QuestionID: "q2",
+					Message:    "question 'q2' can never be shown: its condition is false for every answer of its dependencies",
+				},
+				{
+					Type:       gdq.UnreachableQuestionWarning,
+					QuestionID: "q3",
+					Message:    "question 'q3' can never be shown: it depends on unreachable question 'q2'",
+				},
+				{
+					Type:       gdq.OutOfRangeConstantWarning,
+					QuestionID: "q2",
+					Message:    "condition 'answers[\"q1\"] == 3' compares the answer of question 'q1' to 3, which is out of range (valid: 1-2)",
+				},
+			}))
+		})
+
+		It("should report questions with a constant false condition", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    condition: 'false'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(HaveLen(1))
+			Expect(q.Warnings()[0].QuestionID).To(Equal("q1"))
+		})
+	})
+
+	Describe("Out-of-Range Constants", func() {
+		It("should report conditions comparing answers to constants out of range", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No", "Maybe"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    required: false
+    condition: 'answers["q1"] in [1, 7] || anyOf(answers.q1, 3, 4)'
+closing_remarks:
+  - id: "skipped"
+    text: "You skipped question 2."
+    condition: 'answers["q2"] == -1 && answers["q1"] != -1'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(Equal([]gdq.Warning{
+				{
+					Type:       gdq.OutOfRangeConstantWarning,
+					QuestionID: "q2",
+					Message:    "condition 'answers[\"q1\"] in [1, 7] || anyOf(answers.q1, 3, 4)' compares the answer of question 'q1' to 7, which is out of range (valid: 1-3)",
+				},
+				{
+					Type:       gdq.OutOfRangeConstantWarning,
+					QuestionID: "q2",
+					Message:    "condition 'answers[\"q1\"] in [1, 7] || anyOf(answers.q1, 3, 4)' compares the answer of question 'q1' to 4, which is out of range (valid: 1-3)",
+				},
+				{
+					Type:     gdq.OutOfRangeConstantWarning,
+					RemarkID: "skipped",
+					Message:  "condition 'answers[\"q2\"] == -1 && answers[\"q1\"] != -1' compares the answer of question 'q1' to -1, which is out of range (valid: 1-3)",
+				},
+			}))
+		})
+
+		It("should not report comparisons with answers in range", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    condition: 'answers["q1"] == 2 || answers["q1"] > 5'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(BeEmpty())
+		})
+	})
+
+	Describe("Dead-End Analysis", func() {
+		It("should not report anything when every path ends with a closing remark", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+closing_remarks:
+  - id: "yes"
+    text: "Great!"
+    condition: 'answers["q1"] == 1'
+  - id: "no"
+    text: "Too bad!"
+    condition: 'answers["q1"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Analyze()).To(BeEmpty())
+		})
+
+		It("should explore large questionnaires whose answers don't change the flow", func() {
+			config := "questions:\n"
+			for i := 1; i <= 12; i++ {
+				config += fmt.Sprintf("  - id: \"q%d\"\n    text: \"Question %d?\"\n    answers: [\"A\", \"B\", \"C\"]\n", i, i)
+			}
+			config += "closing_remarks:\n  - id: \"thanks\"\n    text: \"Thanks!\"\n"
+
+			q, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Analyze()).To(BeEmpty())
+		})
+
+		It("should report paths without closing remarks and dead questions and remarks", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1", "q2"]
+    condition: 'answers["q1"] == 2 and answers["q2"] == 1'
+closing_remarks:
+  - id: "happy"
+    text: "Great!"
+    condition: 'answers["q2"] == 1'
+  - id: "never"
+    text: "Never shown"
+    condition: 'answered("q3")'`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Warnings()).To(BeEmpty())
+			Expect(q.Analyze()).To(Equal([]gdq.Warning{
+				{
+					Type:    gdq.DeadEndWarning,
+					Message: "2 answer path(s) complete the questionnaire without any closing remark, e.g. q1=1, q2=2",
+				},
+				{
+					Type:       gdq.UnreachableQuestionWarning,
+					QuestionID: "q3",
+					Message:    "question 'q3' is never shown on any path through the questionnaire",
+				},
+				{
+					Type:     gdq.UnreachableClosingRemarkWarning,
+					RemarkID: "never",
+					Message:  "closing remark 'never' is never shown on any path through the questionnaire",
+				},
+			}))
+		})
+	})
+
+	Describe("Error Types", func() {
+		config := []byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`)
+
+		It("should expose validation errors to errors.As", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"q1": 3})
+			var validationErr gdq.ValidationError
+			Expect(errors.As(err, &validationErr)).To(BeTrue())
+			Expect(validationErr.Type).To(Equal(gdq.InvalidAnswerRangeErrType))
+			Expect(validationErr.Context).To(HaveKeyWithValue("question_id", "q1"))
+		})
+
+		It("should match sentinel errors with errors.Is", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"q2": 1})
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+			Expect(errors.Is(err, gdq.ErrInvalidAnswerRange)).To(BeFalse())
+		})
+
+		It("should expose every aggregated validation error", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: ""
+    text: "Question 1?"
+    answers: []`))
+			Expect(errors.Is(err, gdq.ErrEmptyQuestionID)).To(BeTrue())
+			Expect(errors.Is(err, gdq.ErrEmptyAnswers)).To(BeTrue())
+
+			validationErrs := gdq.ValidationErrors(err)
+			Expect(validationErrs).To(HaveLen(2))
+			Expect(validationErrs[0].Type).To(Equal(gdq.EmptyQuestionIDErrType))
+			Expect(validationErrs[1].Type).To(Equal(gdq.EmptyAnswersErrType))
+		})
+
+		It("should encode validation errors as JSON", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"q1": 3})
+			data, err := json.Marshal(gdq.ValidationErrors(err)[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{
+				"type": "invalid_answer_range",
+				"message": "answer is out of range",
+				"context": {"question_id": "q1", "question_text": "Question 1?", "answer": 3, "valid_range": "1-2"}
+			}`))
+
+			data, err = json.Marshal(gdq.ErrInvalidQuestionID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"type": "invalid_question_id", "message": "question does not exist", "context": {}}`))
+		})
+	})
+
+	Describe("Lint", func() {
+		It("should report a clean questionnaire", func() {
+			report, err := gdq.Lint([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.HasErrors()).To(BeFalse())
+			Expect(report.Warnings).To(BeEmpty())
+		})
+
+		It("should report validation errors instead of failing", func() {
+			report, err := gdq.Lint([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: []`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.HasErrors()).To(BeTrue())
+			Expect(report.Errors).To(HaveLen(1))
+			Expect(report.Errors[0].Type).To(Equal(gdq.EmptyAnswersErrType))
+		})
+
+		It("should fail when the configuration can't be loaded", func() {
+			_, err := gdq.Lint("questionnaire.txt")
+			Expect(err).To(MatchError(ContainSubstring("unsupported file extension")))
+		})
+
+		It("should separate evaluation errors from warnings", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1 ? "yes" : false'
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] > 2'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			report := q.Lint()
+			Expect(report.Errors).To(HaveLen(1))
+			Expect(report.Errors[0].Type).To(Equal(gdq.ConditionEvaluationErrType))
+			Expect(report.Errors[0].Context).To(HaveKeyWithValue("answers", "q1=1"))
+			Expect(report.Warnings).To(ConsistOf(
+				HaveField("QuestionID", "q3"),
+				HaveField("QuestionID", "q2"),
+			))
+		})
+	})
+
+	Describe("Diagram Export", func() {
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(`
+questions:
+  - id: "experience"
+    text: "Do you have programming experience?"
+    answers: ["Yes", "No"]
+  - id: "language"
+    text: "Which language do you prefer?"
+    answers: ["Go", "Python"]
+    depends_on: ["experience"]
+    condition: 'answers["experience"] == 1'
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"
+  - id: "gopher"
+    text: "Welcome, gopher!"
+    condition: 'answers["language"] == 1'`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should render the questionnaire flow as a Mermaid flowchart", func() {
+			Expect(q.ExportMermaid()).To(Equal(`flowchart TD
+    start(("Start"))
+    q1["experience: Do you have programming experience?"]
+    q2["language: Which language do you prefer?"]
+    r1(["thanks: Thank you!"])
+    r2(["gopher: Welcome, gopher!"])
+    start --> q1
+    q1 -->|"answers[#quot;experience#quot;] == 1"| q2
+    q2 -.->|"answers[#quot;language#quot;] == 1"| r2
+`))
+		})
+
+		It("should render the questionnaire flow as a DOT digraph", func() {
+			Expect(q.ExportDOT()).To(Equal(`digraph questionnaire {
+    node [shape=box];
+    start [label="Start", shape=circle];
+    q1 [label="experience: Do you have programming experience?"];
+    q2 [label="language: Which language do you prefer?"];
+    r1 [label="thanks: Thank you!", style=rounded];
+    r2 [label="gopher: Welcome, gopher!", style=rounded];
+    start -> q1;
+    q1 -> q2 [label="answers[\"experience\"] == 1"];
+    q2 -> r2 [label="answers[\"language\"] == 1", style=dashed];
+}
+`))
+		})
+	})
+
+	Describe("Answers Schema", func() {
+		It("should describe the answer choices of every question", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No", "Maybe"]
+    required: false`))
+			Expect(err).ToNot(HaveOccurred())
+
+			schema := q.AnswersSchema()
+			Expect(schema).To(HaveKeyWithValue("type", "object"))
+			Expect(schema).To(HaveKeyWithValue("additionalProperties", false))
+			Expect(schema["properties"]).To(Equal(map[string]interface{}{
+				"q1": map[string]interface{}{
+					"type":        "integer",
+					"title":       "Question 1?",
+					"description": "1 = Yes, 2 = No",
+					"enum":        []int{1, 2},
+				},
+				"q2": map[string]interface{}{
+					"type":        "integer",
+					"title":       "Question 2?",
+					"description": "1 = Yes, 2 = No, 3 = Maybe, -1 = skipped",
+					"enum":        []int{1, 2, 3, gdq.SkipAnswer},
+				},
+			}))
+		})
+	})
+
+	Describe("Checksum", func() {
+		config := `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`
+
+		It("should fingerprint the definition independently of its format", func() {
+			q1, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+			q2, err := gdq.New([]byte(`{"questions": [{"id": "q1", "text": "Question 1?", "answers": ["Yes", "No"]}]}`))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q1.Checksum()).To(HaveLen(64))
+			Expect(q1.Checksum()).To(Equal(q2.Checksum()))
+		})
+
+		It("should change when the definition changes", func() {
+			q1, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+			q2, err := gdq.New([]byte(config + `
+    required: false`))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q1.Checksum()).ToNot(Equal(q2.Checksum()))
+		})
+
+		It("should only be included in responses with WithChecksum", func() {
+			q, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Checksum).To(BeEmpty())
+
+			response, err = q.Next(map[string]int{}, gdq.WithChecksum())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Checksum).To(Equal(q.Checksum()))
+		})
+	})
+
+	Describe("Rich Answers", func() {
+		config := `
+questions:
+  - id: "tools"
+    text: "Which tools do you use?"
+    type: "multiple_choice"
+    answers:
+      - text: "Go"
+        score: 2
+      - text: "Rust"
+        score: 3
+      - text: "Python"
+        score: 1
+  - id: "years"
+    text: "How many years of experience do you have?"
+    type: "number"
+  - id: "mentor"
+    text: "Would you mentor juniors?"
+    answers: ["Yes", "No"]
+    depends_on: ["years"]
+    condition: 'values["years"] >= 5'
+  - id: "comments"
+    text: "Any comments?"
+    type: "text"
+    required: false
+closing_remarks:
+  - id: "rustacean"
+    text: "Thanks, fellow Rustacean!"
+    condition: '2 in values["tools"]'`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return the type of the questions", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(3))
+			Expect(response.Questions[0].Type).To(Equal(gdq.MultipleChoiceQuestion))
+			Expect(response.Questions[1].Type).To(Equal(gdq.NumberQuestion))
+			Expect(response.Questions[2].Type).To(Equal(gdq.TextQuestion))
+		})
+
+		It("should expose the answer values to conditions", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"tools": gdq.Choices(1, 2),
+				"years": gdq.Number(7),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Id).To(Equal("mentor"))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{
+				"tools": gdq.Choices(1, 2),
+				"years": gdq.Number(2.5),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should complete the questionnaire with rich answers", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"tools":    gdq.Choices(2, 1),
+				"years":    gdq.Number(3),
+				"comments": gdq.Text("Nothing to add"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Score).To(Equal(5.0))
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.ClosingRemarks[0].Id).To(Equal("rustacean"))
+		})
+
+		It("should reject answers of the wrong type", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{"years": gdq.Text("many")})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerType))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"tools": gdq.Choices(1, 4)})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+
+			_, err = q.Next(map[string]int{"years": 5})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerType))
+		})
+
+		It("should let optional questions be skipped", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"tools":    gdq.Choices(1),
+				"years":    gdq.Number(1),
+				"comments": gdq.Skipped(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(BeEmpty())
+		})
+
+		It("should reject unknown question types", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    type: "slider"`))
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionType))
+		})
+
+		It("should decode answers from JSON", func() {
+			var answers map[string]gdq.Answer
+			Expect(json.Unmarshal([]byte(`{"a": 2, "b": [1, 3], "c": "text", "d": 4.5, "e": null}`), &answers)).To(Succeed())
+			Expect(answers).To(Equal(map[string]gdq.Answer{
+				"a": gdq.Choice(2),
+				"b": gdq.Choices(1, 3),
+				"c": gdq.Text("text"),
+				"d": gdq.Number(4.5),
+				"e": gdq.Skipped(),
+			}))
+
+			data, err := json.Marshal(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"a": 2, "b": [1, 3], "c": "text", "d": 4.5, "e": null}`))
+		})
+	})
+
+	Describe("Answer Metadata", func() {
+		config := `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]`
+
+		answeredAt := time.Date(2025, 3, 1, 10, 15, 0, 0, time.UTC)
+		metadata := map[string]gdq.AnswerMetadata{
+			"q1": {AnsweredAt: answeredAt, TimeSpentMs: 4200, Client: map[string]string{"platform": "ios"}},
+			"q2": {TimeSpentMs: 1300},
+		}
+
+		It("should return the metadata of the answers once completed", func() {
+			q, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"q1": 1}, gdq.WithAnswerMetadata(metadata))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeFalse())
+			Expect(response.Metadata).To(BeNil())
+
+			response, err = q.Next(map[string]int{"q1": 1, "q2": 2}, gdq.WithAnswerMetadata(metadata))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Metadata).To(Equal(metadata))
+		})
+
+		It("should ignore the metadata of unanswered questions", func() {
+			q, err := gdq.New([]byte(config + `
+complete_when: 'answers["q1"] == 2'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"q1": 2}, gdq.WithAnswerMetadata(metadata))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Metadata).To(Equal(map[string]gdq.AnswerMetadata{"q1": metadata["q1"]}))
+		})
+
+		It("should pass the completed answers and their metadata to the completion hook", func() {
+			var completions []gdq.Completion
+			q, err := gdq.New([]byte(config), gdq.WithCompletionHook(func(c gdq.Completion) {
+				completions = append(completions, c)
+			}))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"q1": 1}, gdq.WithAnswerMetadata(metadata))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(completions).To(BeEmpty())
+
+			_, err = q.Next(map[string]int{"q1": 1, "q2": 2}, gdq.WithAnswerMetadata(metadata))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(completions).To(HaveLen(1))
+			Expect(completions[0].Answers).To(Equal(map[string]int{"q1": 1, "q2": 2}))
+			Expect(completions[0].Metadata).To(Equal(metadata))
+		})
+
+		It("should not affect conditions", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    condition: 'metadata["q1"] != nil'`))
+			Expect(err).To(MatchError(gdq.ErrInvalidCondition))
+		})
+
+		It("should encode the metadata as JSON", func() {
+			data, err := json.Marshal(metadata["q1"])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"answered_at": "2025-03-01T10:15:00Z", "time_spent_ms": 4200, "client": {"platform": "ios"}}`))
+
+			data, err = json.Marshal(gdq.AnswerMetadata{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{}`))
+		})
+	})
+
+	Describe("Consistency Rules", func() {
+		config := `
+questions:
+  - id: "start_year"
+    text: "When did you start?"
+    type: "number"
+  - id: "end_year"
+    text: "When did you stop?"
+    type: "number"
+    required: false
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+rules:
+  - id: "years_order"
+    condition: 'values["end_year"] >= values["start_year"]'
+    message:
+      en: "The end year can't be before the start year."
+      fr: "L'année de fin ne peut pas précéder l'année de début."
+  - id: "q3_score"
+    condition: 'answers["q3"] == 1 or values["start_year"] > 2000'`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept answers satisfying the rules", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"start_year": gdq.Number(2010),
+				"end_year":   gdq.Number(2020),
+				"q3":         gdq.Choice(2),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should reject answers violating a rule with the rule message", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{
+				"start_year": gdq.Number(2010),
+				"end_year":   gdq.Number(2005),
+			}, gdq.WithLocale("fr"))
+			Expect(err).To(MatchError(gdq.ErrRuleViolation))
+
+			validationErrs := gdq.ValidationErrors(err)
+			Expect(validationErrs).To(HaveLen(1))
+			Expect(validationErrs[0].Context["rule_id"]).To(Equal("years_order"))
+			Expect(validationErrs[0].Message).To(Equal("L'année de fin ne peut pas précéder l'année de début."))
+			Expect(gdq.LocalizeError(err, "fr")).To(Equal(validationErrs[0].Message))
+		})
+
+		It("should report every violated rule", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{
+				"start_year": gdq.Number(1990),
+				"end_year":   gdq.Number(1980),
+				"q3":         gdq.Choice(2),
+			})
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(2))
+		})
+
+		It("should only check rules once their questions are answered", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{"start_year": gdq.Number(2010)})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{
+				"start_year": gdq.Number(2010),
+				"end_year":   gdq.Skipped(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should validate the rules when the questionnaire is created", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+rules:
+  - id: "r1"
+    condition: 'answers["q1"] =='
+  - id: "r1"
+    condition: 'answers["q1"] == 1'
+  - id: "r2"`))
+			Expect(err).To(MatchError(gdq.ErrInvalidCondition))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(3))
+		})
+	})
+
+	Describe("All Answer Errors", func() {
+		config := `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    type: "number"
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should report every invalid answer ordered by question ID", func() {
+			_, err := q.Next(map[string]int{"q3": 4, "q1": 3, "unknown": 1, "q2": 1}, gdq.WithAllAnswerErrors())
+			Expect(err).To(HaveOccurred())
+
+			validationErrs := gdq.ValidationErrors(err)
+			Expect(validationErrs).To(HaveLen(4))
+			Expect(validationErrs[0].Context["question_id"]).To(Equal("q1"))
+			Expect(validationErrs[1].Type).To(Equal(gdq.InvalidAnswerTypeErrType))
+			Expect(validationErrs[2].Context["question_id"]).To(Equal("q3"))
+			Expect(validationErrs[3].Type).To(Equal(gdq.InvalidQuestionIDErrType))
+		})
+
+		It("should report every invalid rich answer", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{
+				"q1": gdq.Text("Yes"),
+				"q2": gdq.Text("ten"),
+				"q3": gdq.Choice(1),
+			}, gdq.WithAllAnswerErrors())
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(2))
+		})
+
+		It("should report a single invalid answer by default", func() {
+			_, err := q.Next(map[string]int{"q1": 3, "q3": 4})
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(1))
+		})
+	})
+
+	Describe("Lenient Answers", func() {
+		config := `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    type: "multiple_choice"
+    answers: ["A", "B", "C"]
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should ignore and report unknown questions and out-of-range answers", func() {
+			response, err := q.Next(map[string]int{"q1": 1, "removed": 2, "q3": 5}, gdq.WithLenientAnswers())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[1].Id).To(Equal("q3"))
+
+			Expect(response.Ignored).To(HaveLen(2))
+			Expect(response.Ignored[0].Type).To(Equal(gdq.InvalidAnswerRangeErrType))
+			Expect(response.Ignored[0].Context["question_id"]).To(Equal("q3"))
+			Expect(response.Ignored[1].Type).To(Equal(gdq.InvalidQuestionIDErrType))
+			Expect(response.Ignored[1].Context["question_id"]).To(Equal("removed"))
+		})
+
+		It("should ignore invalid rich answers", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"q1":      gdq.Choice(1),
+				"q2":      gdq.Choices(1, 4),
+				"removed": gdq.Text("old"),
+			}, gdq.WithLenientAnswers())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Ignored).To(HaveLen(2))
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Id).To(Equal("q2"))
+		})
+
+		It("should still reject other invalid answers", func() {
+			_, err := q.NextAnswers(map[string]gdq.Answer{"q2": gdq.Text("A")}, gdq.WithLenientAnswers())
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerType))
+		})
+
+		It("should reject invalid answers by default", func() {
+			_, err := q.Next(map[string]int{"q1": 1, "removed": 2})
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+		})
+	})
+
+	Describe("Stale Answers", func() {
+		config := `
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    depends_on: ["employed"]
+    condition: 'answers["employed"] == 1'
+  - id: "remote"
+    text: "Do you work remotely?"
+    answers:
+      - "Yes"
+      - text: "Sometimes"
+        condition: 'answers["company_size"] == 2'
+      - "No"
+    depends_on: ["company_size"]
+  - id: "hobbies"
+    text: "What are your hobbies?"
+    answers: ["Sports", "Music"]`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should keep stale answers by default", func() {
+			response, err := q.Next(map[string]int{"employed": 2, "company_size": 1, "remote": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Stale).To(BeNil())
+			Expect(response.Progress.Current).To(Equal(3))
+		})
+
+		It("should flag the answers of questions that wouldn't be shown anymore", func() {
+			response, err := q.Next(map[string]int{"employed": 2, "company_size": 1, "remote": 1}, gdq.WithStaleAnswers(gdq.FlagStaleAnswers))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Stale).To(Equal([]string{"company_size", "remote"}))
+			Expect(response.Progress.Current).To(Equal(3))
+		})
+
+		It("should strip stale answers", func() {
+			response, err := q.Next(map[string]int{"employed": 2, "company_size": 1, "remote": 1}, gdq.WithStaleAnswers(gdq.StripStaleAnswers))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Stale).To(Equal([]string{"company_size", "remote"}))
+			Expect(response.Progress.Current).To(Equal(1))
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("hobbies"))
+		})
+
+		It("should detect answers whose option isn't available anymore", func() {
+			_, err := q.Next(map[string]int{"employed": 1, "company_size": 1, "remote": 2})
+			Expect(err).To(MatchError(gdq.ErrUnavailableAnswer))
+
+			response, err := q.Next(map[string]int{"employed": 1, "company_size": 1, "remote": 2}, gdq.WithStaleAnswers(gdq.StripStaleAnswers))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Stale).To(Equal([]string{"remote"}))
+			Expect(response.Questions[0].Id).To(Equal("remote"))
+		})
+
+		Describe("Revise", func() {
+			It("should leave out the answers invalidated by the change", func() {
+				answers := map[string]int{"employed": 1, "company_size": 2, "remote": 2, "hobbies": 1}
+				revision, err := q.Revise(answers, "employed", 2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revision.Answers).To(Equal(map[string]int{"employed": 2, "hobbies": 1}))
+				Expect(revision.Invalidated).To(Equal([]string{"company_size", "remote"}))
+				Expect(answers).To(HaveLen(4))
+			})
+
+			It("should invalidate answers whose option isn't available anymore", func() {
+				revision, err := q.Revise(map[string]int{"employed": 1, "company_size": 2, "remote": 2}, "company_size", 1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revision.Answers).To(Equal(map[string]int{"employed": 1, "company_size": 1}))
+				Expect(revision.Invalidated).To(Equal([]string{"remote"}))
+			})
+
+			It("should keep the answers still valid", func() {
+				revision, err := q.Revise(map[string]int{"employed": 1, "company_size": 2, "remote": 1}, "company_size", 1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revision.Answers).To(Equal(map[string]int{"employed": 1, "company_size": 1, "remote": 1}))
+				Expect(revision.Invalidated).To(BeEmpty())
+			})
+
+			It("should reject invalid revisions", func() {
+				_, err := q.Revise(map[string]int{"employed": 1}, "employed", 3)
+				Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+
+				_, err = q.Revise(map[string]int{"employed": 1}, "unknown", 1)
+				Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+			})
+		})
+
+		It("should strip stale rich answers", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "details"
+    text: "Tell us more"
+    type: "text"
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'
+closing_remarks:
+  - id: "detailed"
+    text: "Thanks for the details!"
+    condition: 'values["details"] != nil'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"q1": gdq.Choice(2), "details": gdq.Text("...")}, gdq.WithStaleAnswers(gdq.StripStaleAnswers))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Stale).To(Equal([]string{"details"}))
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(BeEmpty())
+		})
+	})
+
+	Describe("Computed Values", func() {
+		config := `
+questions:
+  - id: "weight"
+    text: "What is your weight (kg)?"
+    type: "number"
+  - id: "height"
+    text: "What is your height (m)?"
+    type: "number"
+  - id: "diet"
+    text: "Would you like diet advice?"
+    answers: ["Yes", "No"]
+    depends_on: ["weight", "height"]
+    condition: 'computed["bmi"] >= 25'
+computed:
+  bmi: 'values["weight"] / (values["height"] * values["height"])'
+  answered: 'countAnswered()'
+closing_remarks:
+  - id: "healthy"
+    text: "Your BMI is in the healthy range."
+    condition: 'computed["bmi"] < 25'`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should expose computed values to question conditions", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{"weight": gdq.Number(90), "height": gdq.Number(1.8)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("diet"))
+			Expect(response.Computed).To(BeNil())
+		})
+
+		It("should expose computed values to closing remarks and return them once completed", func() {
+			response, err := q.NextAnswers(map[string]gdq.Answer{"weight": gdq.Number(64.8), "height": gdq.Number(1.8)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.Computed).To(HaveKeyWithValue("bmi", BeNumerically("~", 20, 0.01)))
+			Expect(response.Computed).To(HaveKeyWithValue("answered", 2))
+		})
+
+		It("should count the questions referenced by computed values as dependencies", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    depends_on: ["q2"]
+    condition: 'computed["yes"]'
+computed:
+  yes: 'answers["q1"] == 1'`))
+			Expect(err).To(MatchError(gdq.ErrConditionDependencyMismatch))
+		})
+
+		It("should reject invalid computed values", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+computed:
+  invalid: 'answers["q1"] =='
+  chained: 'computed["invalid"] + 1'
+  empty: ''`))
+			Expect(err).To(MatchError(gdq.ErrInvalidCondition))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(3))
+		})
+	})
+
+	Describe("Hidden Questions", func() {
+		config := `
+questions:
+  - id: "plan"
+    text: "Customer plan"
+    hidden: true
+    answers: ["Free", "Pro"]
+  - id: "company"
+    text: "Do you use the product for your company?"
+    answers: ["Yes", "No"]
+  - id: "segment"
+    text: "Customer segment"
+    hidden: true
+    answers: ["Consumer", "Business"]
+    value: 'answers["company"] == 1 ? 2 : 1'
+  - id: "seats"
+    text: "How many seats do you need?"
+    type: "number"
+    depends_on: ["plan", "segment"]
+    condition: 'answers["plan"] == 2 && answers["segment"] == 2'`
+
+		var q gdq.Questionnaire
+
+		BeforeEach(func() {
+			var err error
+			q, err = gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should never return hidden questions", func() {
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("company"))
+			Expect(response.Progress.Current).To(Equal(0))
+		})
+
+		It("should answer hidden questions from the context and their value", func() {
+			context := gdq.WithContextAnswers(map[string]gdq.Answer{"plan": gdq.Choice(2)})
+
+			response, err := q.Next(map[string]int{"company": 1}, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("seats"))
+			Expect(response.Progress.Current).To(Equal(1))
+
+			response, err = q.Next(map[string]int{"company": 2}, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should ignore the answers provided for hidden questions", func() {
+			response, err := q.Next(map[string]int{"company": 2, "plan": 2, "segment": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should reject invalid context answers", func() {
+			_, err := q.Next(map[string]int{}, gdq.WithContextAnswers(map[string]gdq.Answer{"plan": gdq.Choice(3)}))
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+		})
+
+		It("should explain that hidden questions aren't displayed", func() {
+			response, err := q.Next(map[string]int{}, gdq.WithExplain())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Explanations[0].QuestionID).To(Equal("plan"))
+			Expect(response.Explanations[0].Reason).To(Equal(gdq.HiddenReason))
+		})
+
+		It("should reject values on questions that aren't hidden", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    value: '1'`))
+			Expect(err).To(MatchError(gdq.ErrInvalidCondition))
+		})
+	})
+
+	Describe("Dependency Inference", func() {
+		It("should infer the dependencies of questions omitting depends_on from their conditions", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "experience"
+    text: "Do you have programming experience?"
+    answers: ["Yes", "No"]
+  - id: "language"
+    text: "Which language do you prefer?"
+    answers: ["Go", "Python"]
+    condition: 'answers["experience"] == 1'
+  - id: "editor"
+    text: "Which editor do you use?"
+    answers:
+      - "Vim"
+      - text: "GoLand"
+        when:
+          question: "language"
+          equals: 1`))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("experience"))
+
+			response, err = q.Next(map[string]int{"experience": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("language"))
+
+			response, err = q.Next(map[string]int{"experience": 1, "language": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("editor"))
+			Expect(response.Questions[0].Answers).To(HaveLen(2))
+		})
+
+		It("should combine inferred dependencies with jumps", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "smoker"
+    text: "Do you smoke?"
+    answers:
+      - text: "Yes"
+        next: "cigarettes"
+      - "No"
+  - id: "age"
+    text: "Are you an adult?"
+    answers: ["Yes", "No"]
+  - id: "cigarettes"
+    text: "How many cigarettes a day?"
+    answers: ["Less than 10", "10 or more"]
+    condition: 'answers["age"] == 1'`))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"smoker": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("age"))
+
+			response, err = q.Next(map[string]int{"smoker": 1, "age": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("cigarettes"))
+		})
+
+		It("should report conditions referencing unknown questions", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+    condition: 'answers["removed"] == 1'`))
+			Expect(err).To(MatchError(gdq.ErrInvalidDependency))
+		})
+
+		It("should still validate declared dependencies", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+  - id: "q3"
+    text: "Question 3?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q2"] == 1'`))
+			Expect(err).To(MatchError(gdq.ErrConditionDependencyMismatch))
+		})
+	})
+
+	Describe("Question ID Suggestions", func() {
+		config := []byte(`
+questions:
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+  - id: "company_sector"
+    text: "What is your sector?"
+    answers: ["Retail", "Banking"]
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]`)
+
+		It("should suggest the closest question IDs for answers to unknown questions", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"compnay_size": 1})
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+			Expect(err).To(MatchError("invalid answers provided: validation error (invalid_question_id): question does not exist (did you mean 'company_size'?)"))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("suggestions", []string{"company_size"}))
+		})
+
+		It("should suggest the closest question IDs for unknown dependencies", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "plan"
+    text: "Which plan are you on?"
+    answers: ["Free", "Pro"]
+  - id: "upgrade"
+    text: "Would you like to upgrade?"
+    answers: ["Yes", "No"]
+    condition: 'answers["paln"] == 1'`))
+			Expect(err).To(MatchError(ContainSubstring("question 'upgrade' depends on non-existent question 'paln' (did you mean 'plan'?)")))
+		})
+
+		It("should not suggest anything when no question ID is close", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"unrelated": 1})
+			Expect(err).To(MatchError("invalid answers provided: validation error (invalid_question_id): question does not exist"))
+			Expect(gdq.ValidationErrors(err)[0].Context).ToNot(HaveKey("suggestions"))
+		})
+	})
+
+	Describe("Answer Summary", func() {
+		config := []byte(`
+default_locale: "en"
+questions:
+  - id: "employed"
+    text:
+      en: "Are you employed?"
+      fr: "Êtes-vous employé ?"
+    answers:
+      - text:
+          en: "Yes"
+          fr: "Oui"
+      - text:
+          en: "No"
+          fr: "Non"
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    condition: 'answers["employed"] == 1'
+  - id: "nickname"
+    text: "What is your nickname?"
+    type: "text"
+    required: false`)
+
+		It("should list the answered questions in configuration order with their answer texts", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			summary, err := q.Summary(map[string]int{"company_size": 2, "employed": 1, "nickname": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal([]gdq.SummaryItem{
+				{QuestionID: "employed", Question: "Are you employed?", Answer: "Yes"},
+				{QuestionID: "company_size", Question: "How big is your company?", Answer: "Large"},
+				{QuestionID: "nickname", Question: "What is your nickname?", Skipped: true},
+			}))
+		})
+
+		It("should leave out the answers off the path taken", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			summary, err := q.Summary(map[string]int{"employed": 2, "company_size": 2}, gdq.WithLocale("fr"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal([]gdq.SummaryItem{
+				{QuestionID: "employed", Question: "Êtes-vous employé ?", Answer: "Non"},
+			}))
+		})
+
+		It("should reject invalid answers", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Summary(map[string]int{"employed": 3})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+		})
+	})
+
+	Describe("Review", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    condition: 'answers["employed"] == 1'
+  - id: "nickname"
+    text: "What is your nickname?"
+    type: "text"
+    required: false`)
+
+		It("should return the answered questions with their answer", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			review, err := q.Review(map[string]int{"company_size": 2, "employed": 1, "nickname": gdq.SkipAnswer})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(review).To(HaveLen(3))
+			Expect(review[0].Question.Id).To(Equal("employed"))
+			Expect(review[0].Question.Answers).To(Equal([]string{"Yes", "No"}))
+			Expect(review[0].Answer).To(Equal(gdq.Choice(1)))
+			Expect(review[0].AnswerText).To(Equal("Yes"))
+			Expect(review[1].Question.Id).To(Equal("company_size"))
+			Expect(review[1].Answer).To(Equal(gdq.Choice(2)))
+			Expect(review[1].AnswerText).To(Equal("Large"))
+			Expect(review[2].Question.Id).To(Equal("nickname"))
+			Expect(review[2].Answer.IsSkipped()).To(BeTrue())
+			Expect(review[2].AnswerText).To(BeEmpty())
+
+			data, err := json.Marshal(review[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(ContainSubstring(`"answer":2,"answer_text":"Large"`))
+		})
+
+		It("should leave out the answers off the path taken", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			review, err := q.Review(map[string]int{"employed": 2, "company_size": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(review).To(HaveLen(1))
+			Expect(review[0].Question.Id).To(Equal("employed"))
+		})
+	})
+
+	Describe("Answer Set Validation", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    condition: 'answers["employed"] == 1'
+  - id: "remote"
+    text: "Do you work remotely?"
+    answers: ["Yes", "No"]
+    condition: 'answers["company_size"] == 2'`)
+
+		It("should accept answers following a path through the questionnaire", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q.ValidateAnswerSet(map[string]int{"employed": 1, "company_size": 2, "remote": 1})).To(Succeed())
+			Expect(q.ValidateAnswerSet(map[string]int{"employed": 2})).To(Succeed())
+		})
+
+		It("should report the questions answered off the path", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = q.ValidateAnswerSet(map[string]int{"employed": 2, "company_size": 2, "remote": 1})
+			Expect(err).To(MatchError(gdq.ErrUnreachableAnswer))
+			errs := gdq.ValidationErrors(err)
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Context).To(HaveKeyWithValue("question_id", "company_size"))
+			Expect(errs[1].Context).To(HaveKeyWithValue("question_id", "remote"))
+
+			err = q.ValidateAnswerSet(map[string]int{"remote": 1})
+			Expect(err).To(MatchError(gdq.ErrUnreachableAnswer))
+		})
+
+		It("should report invalid answers", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q.ValidateAnswerSet(map[string]int{"employed": 3})).To(MatchError(gdq.ErrInvalidAnswerRange))
+		})
+	})
+
+	Describe("Remaining Questions", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    condition: 'answers["employed"] == 1'
+  - id: "remote"
+    text: "Do you work remotely?"
+    answers: ["Yes", "No"]
+    condition: 'answers["company_size"] == 2'
+  - id: "hobbies"
+    text: "What are your hobbies?"
+    answers: ["Reading", "Sports"]
+    terminate_if: 'answers["hobbies"] == 2'`)
+
+		ids := func(questions []gdq.Question) []string {
+			var result []string
+			for _, question := range questions {
+				result = append(result, question.Id)
+			}
+			return result
+		}
+
+		It("should list the questions that may still be shown, including those not unlocked yet", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			remaining, err := q.Remaining(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(remaining)).To(Equal([]string{"employed", "company_size", "remote", "hobbies"}))
+			Expect(remaining[1].Text).To(Equal("How big is your company?"))
+		})
+
+		It("should leave out the questions that can't be shown anymore", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			remaining, err := q.Remaining(map[string]int{"employed": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(remaining)).To(Equal([]string{"hobbies"}))
+
+			remaining, err = q.Remaining(map[string]int{"employed": 1, "company_size": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(remaining)).To(Equal([]string{"hobbies"}))
+		})
+
+		It("should return nothing once the questionnaire is terminated", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			remaining, err := q.Remaining(map[string]int{"hobbies": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(remaining).To(BeEmpty())
+		})
+
+		It("should reject invalid answers", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Remaining(map[string]int{"unknown": 1})
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+		})
+	})
+
+	Describe("What-If Evaluation", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company_size"
+    text: "How big is your company?"
+    answers: ["Small", "Large"]
+    condition: 'answers["employed"] == 1'
+  - id: "looking"
+    text: "Are you looking for a job?"
+    answers: ["Yes", "No"]
+    condition: 'answers["employed"] == 2'
+closing_remarks:
+  - id: "employed_thanks"
+    text: "Thank you!"
+    condition: 'answers["employed"] == 1'
+  - id: "good_luck"
+    text: "Good luck!"
+    condition: 'answers["employed"] == 2'`)
+
+		It("should report the questions a hypothetical answer would show", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			answers := map[string]int{}
+			result, err := q.WhatIf(answers, map[string]int{"employed": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Shown).To(Equal([]string{"company_size"}))
+			Expect(result.Hidden).To(BeEmpty())
+			Expect(result.Response.Questions).To(HaveLen(1))
+			Expect(answers).To(BeEmpty())
+		})
+
+		It("should report the questions and closing remarks a changed answer would hide or show", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := q.WhatIf(map[string]int{"employed": 1}, map[string]int{"employed": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Shown).To(Equal([]string{"looking"}))
+			Expect(result.Hidden).To(Equal([]string{"company_size"}))
+
+			result, err = q.WhatIf(map[string]int{"employed": 1, "company_size": 1}, map[string]int{"employed": 2, "looking": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Response.Completed).To(BeTrue())
+			Expect(result.ShownRemarks).To(Equal([]string{"good_luck"}))
+			Expect(result.HiddenRemarks).To(Equal([]string{"employed_thanks"}))
+		})
+
+		It("should not call the completion hook", func() {
+			completions := 0
+			q, err := gdq.New(config, gdq.WithCompletionHook(func(gdq.Completion) { completions++ }))
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := q.WhatIf(map[string]int{"employed": 2}, map[string]int{"looking": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Response.Completed).To(BeTrue())
+			Expect(completions).To(BeZero())
+		})
+
+		It("should reject invalid hypothetical answers", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.WhatIf(map[string]int{}, map[string]int{"employed": 3})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+			Expect(err).To(MatchError(ContainSubstring("invalid hypothetical answers")))
+		})
+	})
+
+	Describe("Definition Accessors", func() {
+		config := []byte(`
+default_locale: "fr"
+questions:
+  - id: "plan"
+    text:
+      en: "Which plan are you on?"
+      fr: "Quelle est votre formule ?"
+    answers:
+      - "Free"
+      - id: "pro"
+        text: "Pro"
+        condition: 'answers["seats"] > 10'
+    shuffle_answers: true
+  - id: "seats"
+    text: "How many seats?"
+    type: "number"
+    hidden: true
+    min: 1
+    tags: ["billing"]
+    metadata:
+      widget: "stepper"
+closing_remarks:
+  - id: "thanks"
+    text:
+      en: "Thank you!"
+      fr: "Merci !"
+    condition: 'answers["plan"] == 2'
+    metadata:
+      tone: "warm"`)
+
+		It("should list every question in the default locale with all its answer options", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			questions := q.Questions()
+			Expect(questions).To(HaveLen(2))
+			Expect(questions[0]).To(Equal(gdq.Question{
+				Id:        "plan",
+				Text:      "Quelle est votre formule ?",
+				Answers:   []string{"Free", "Pro"},
+				AnswerIds: []string{"", "pro"},
+			}))
+			Expect(questions[1].Id).To(Equal("seats"))
+			Expect(questions[1].Type).To(Equal(gdq.NumberQuestion))
+		})
+
+		It("should list every closing remark whatever its condition", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q.ClosingRemarks()).To(Equal([]gdq.ClosingRemark{
+				{Id: "thanks", Text: "Merci !", Metadata: map[string]interface{}{"tone": "warm"}},
+			}))
+		})
+
+		It("should return copies of the definition", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			q.Questions()[0].Answers[0] = "Changed"
+			Expect(q.Questions()[0].Answers[0]).To(Equal("Free"))
+
+			seats := q.Questions()[1]
+			seats.Metadata["widget"] = "slider"
+			seats.Tags[0] = "changed"
+			*seats.Min = 5
+			Expect(q.Questions()[1].Metadata).To(Equal(map[string]interface{}{"widget": "stepper"}))
+			Expect(q.Questions()[1].Tags).To(Equal([]string{"billing"}))
+			Expect(*q.Questions()[1].Min).To(Equal(1.0))
+
+			q.ClosingRemarks()[0].Metadata["tone"] = "cold"
+			Expect(q.ClosingRemarks()[0].Metadata).To(Equal(map[string]interface{}{"tone": "warm"}))
+		})
+	})
+
+	Describe("Tag Filtering", func() {
+		config := []byte(`
+questions:
+  - id: "name"
+    text: "What is your name?"
+    type: "text"
+    tags: ["onboarding", "profile"]
+  - id: "role"
+    text: "What is your role?"
+    answers: ["Developer", "Manager"]
+    tags: ["onboarding"]
+  - id: "feedback"
+    text: "How do you like the product?"
+    answers: ["A lot", "Not much"]
+    tags: ["survey"]
+  - id: "team_size"
+    text: "How big is your team?"
+    answers: ["Small", "Large"]
+    condition: 'answers["role"] == 2'
+    tags: ["onboarding"]`)
+
+		It("should only return the questions with one of the tags", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{}, gdq.WithTags("onboarding"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Id).To(Equal("name"))
+			Expect(response.Questions[0].Tags).To(Equal([]string{"onboarding", "profile"}))
+			Expect(response.Questions[1].Id).To(Equal("role"))
+			Expect(response.Progress.Total).To(Equal(3))
+
+			response, err = q.Next(map[string]int{}, gdq.WithTags("survey", "profile"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Id).To(Equal("name"))
+			Expect(response.Questions[1].Id).To(Equal("feedback"))
+		})
+
+		It("should complete once the questions with the tags are answered", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"name": gdq.Text("Ada"), "role": gdq.Choice(1)}, gdq.WithTags("onboarding"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Progress.Current).To(Equal(2))
+			Expect(response.Progress.Percent).To(Equal(100))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{"name": gdq.Text("Ada"), "role": gdq.Choice(1)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeFalse())
+			Expect(response.Questions[0].Id).To(Equal("feedback"))
+		})
+
+		It("should explain why questions without the tags are left out", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{}, gdq.WithTags("onboarding"), gdq.WithExplain())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Explanations[2].QuestionID).To(Equal("feedback"))
+			Expect(response.Explanations[2].Reason).To(Equal(gdq.OutsideTagsReason))
+		})
+	})
+
+	Describe("Typed Answers", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "company"
+    text: "What is your company?"
+    type: "text"
+    condition: 'answers["employed"] == 1'`)
+
+		type yesNo int
+		const yes yesNo = 1
+
+		It("should process answer choices of any type based on int", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := gdq.Next(q, map[string]yesNo{"employed": yes})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("company"))
+
+			response, err = gdq.Next(q, map[string]int{"employed": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should process answers of any question type", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := gdq.Next(q, map[string]gdq.Answer{"employed": gdq.Choice(1), "company": gdq.Text("Acme")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should validate the answers", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = gdq.Next(q, map[string]yesNo{"employed": 3})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+		})
+	})
+
+	Describe("Exclusive Answers", func() {
+		config := []byte(`
+questions:
+  - id: "allergies"
+    text: "Do you have any allergies?"
+    type: "multiple_choice"
+    answers:
+      - "Peanuts"
+      - "Gluten"
+      - text: "None of the above"
+        exclusive: true`)
+
+		It("should flag the exclusive answer options", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Exclusive).To(Equal([]bool{false, false, true}))
+		})
+
+		It("should accept an exclusive answer option chosen alone", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"allergies": gdq.Choices(3)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{"allergies": gdq.Choices(1, 2)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should reject an exclusive answer option combined with other options", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"allergies": gdq.Choices(3, 1)})
+			Expect(err).To(MatchError(gdq.ErrExclusiveAnswer))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("answers", []int{1, 3}))
+			Expect(gdq.LocalizeError(err, "en")).To(Equal("answer 3 of question 'allergies' can't be combined with other answers"))
+		})
+	})
+
+	Describe("Choice Limits", func() {
+		config := []byte(`
+questions:
+  - id: "tools"
+    text: "Which tools do you use?"
+    type: "multiple_choice"
+    answers: ["Go", "Rust", "Python", "Java"]
+    min_choices: 2
+    max_choices: 3`)
+
+		It("should accept answers within the limits", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"tools": gdq.Choices(1, 3)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should expose the limits to clients", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].MinChoices).To(Equal(2))
+			Expect(response.Questions[0].MaxChoices).To(Equal(3))
+			Expect(q.AnswersSchema()["properties"]).To(HaveKeyWithValue("tools", And(HaveKeyWithValue("minItems", 2), HaveKeyWithValue("maxItems", 3))))
+		})
+
+		It("should reject too few or too many chosen options with the allowed range", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"tools": gdq.Choices(1)})
+			Expect(err).To(MatchError(gdq.ErrInvalidChoiceCount))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("valid_range", "2-3"))
+			Expect(gdq.LocalizeError(err, "en")).To(Equal("1 answers chosen for question 'tools' (valid: 2-3)"))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"tools": gdq.Choices(1, 2, 3, 4)})
+			Expect(err).To(MatchError(gdq.ErrInvalidChoiceCount))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("count", 4))
+		})
+
+		It("should reject limits that can't be met", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "tools"
+    text: "Which tools do you use?"
+    type: "multiple_choice"
+    answers: ["Go", "Rust"]
+    min_choices: 3
+  - id: "plan"
+    text: "Which plan?"
+    answers: ["Free", "Pro"]
+    max_choices: 1`))
+			Expect(err).To(MatchError(gdq.ErrInvalidChoiceLimits))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(2))
+		})
+	})
+
+	Describe("Number Bounds", func() {
+		config := []byte(`
+questions:
+  - id: "age"
+    text: "How old are you?"
+    type: "number"
+    min: 18
+    max: 120
+  - id: "budget"
+    text: "What is your budget?"
+    type: "number"
+    min: 0`)
+
+		It("should expose the bounds on the question and in the answers schema", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*response.Questions[0].Min).To(Equal(18.0))
+			Expect(*response.Questions[0].Max).To(Equal(120.0))
+			Expect(response.Questions[1].Max).To(BeNil())
+			Expect(q.AnswersSchema()["properties"]).To(HaveKeyWithValue("age", And(HaveKeyWithValue("minimum", 18.0), HaveKeyWithValue("maximum", 120.0))))
+		})
+
+		It("should reject numbers out of bounds with the valid range", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"age": gdq.Number(17.5)})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+			Expect(gdq.LocalizeError(err, "en")).To(Equal("answer 17.5 is out of range for question 'age' (valid: 18-120)"))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"budget": gdq.Choice(-1)})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("valid_range", "0-any"))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"age": gdq.Number(18), "budget": gdq.Number(1e6)})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject bounds that can't be met or on other question types", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "age"
+    text: "How old are you?"
+    type: "number"
+    min: 18
+    max: 12
+  - id: "plan"
+    text: "Which plan?"
+    answers: ["Free", "Pro"]
+    max: 1`))
+			Expect(err).To(MatchError(gdq.ErrInvalidNumberBounds))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(2))
+			Expect(gdq.LocalizeError(err, "en")).To(Equal("bounds 18-12 of number question 'age' are invalid\nbounds any-1 of choice question 'plan' are invalid"))
+		})
+	})
+
+	Describe("NPS Questions", func() {
+		config := []byte(`
+questions:
+  - id: "nps"
+    text: "How likely are you to recommend us to a friend?"
+    type: "nps"
+  - id: "improve"
+    text: "What should we improve?"
+    type: "text"
+    condition: 'npsCategory("nps") == "detractor"'
+closing_remarks:
+  - id: "review"
+    text: "Would you leave us a review?"
+    condition: 'npsCategory("nps") == "promoter"'
+  - id: "thanks"
+    text: "Thank you!"
+    condition: 'values["nps"] >= 7'`)
+
+		It("should classify the answers in conditions and closing remarks", func() {
+			q, err := gdq.New(config, gdq.WithStrictValidation())
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"nps": gdq.Choice(9)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(HaveLen(2))
+			Expect(response.ClosingRemarks[0].Id).To(Equal("review"))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{"nps": gdq.Choice(7)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.ClosingRemarks[0].Id).To(Equal("thanks"))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{"nps": gdq.Number(0)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("improve"))
+		})
+
+		It("should reject answers outside 0-10", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"nps": gdq.Choice(11)})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+			Expect(gdq.ValidationErrors(err)[0].Context).To(HaveKeyWithValue("valid_range", "0-10"))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"nps": gdq.Number(8.5)})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerType))
+		})
+
+		It("should describe the answers in the schema", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(q.AnswersSchema()["properties"]).To(HaveKeyWithValue("nps", And(
+				HaveKeyWithValue("type", "integer"),
+				HaveKeyWithValue("minimum", 0),
+				HaveKeyWithValue("maximum", 10),
+			)))
+		})
+	})
+
+	Describe("Consent Questions", func() {
+		config := []byte(`
+questions:
+  - id: "consent"
+    text: "Do you agree to the processing of your answers for this research?"
+    type: "consent"
+    answers:
+      - "I agree"
+      - "I don't agree"
+  - id: "age"
+    text: "How old are you?"
+    answers: ["Under 30", "30 or over"]
+closing_remarks:
+  - id: "declined"
+    text: "No answers were recorded. Thank you for your time."
+    condition: 'answers["consent"] == 2'
+  - id: "thanks"
+    text: "Thank you for taking part!"
+    condition: 'answers["consent"] == 1'`)
+
+		It("should go on when consent is given", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"consent": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("age"))
+
+			response, err = q.Next(map[string]int{"consent": 1, "age": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Terminated).To(BeFalse())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.ClosingRemarks[0].Id).To(Equal("thanks"))
+		})
+
+		It("should terminate with the matching remarks when consent is declined", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"consent": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(BeEmpty())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Terminated).To(BeTrue())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.ClosingRemarks[0].Id).To(Equal("declined"))
+		})
+
+		DescribeTable("should reject consent questions that can't collect consent",
+			func(options string) {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "consent"
+    text: "Do you agree?"
+    type: "consent"` + options))
+				Expect(err).To(MatchError(gdq.ErrInvalidConsent))
+			},
+			Entry("optional", `
+    required: false
+    answers: ["Yes", "No"]`),
+			Entry("skippable", `
+    skippable: true
+    answers: ["Yes", "No"]`),
+			Entry("granted by default", `
+    default: 1
+    answers: ["Yes", "No"]`),
+			Entry("without an answer declining consent", `
+    answers: ["Yes"]`),
+		)
+	})
+
+	Describe("Repeating Groups", func() {
+		config := []byte(`
+questions:
+  - id: "children"
+    text: "How many children do you have?"
+    type: "number"
+  - id: "pets"
+    text: "Do you have pets?"
+    answers: ["Yes", "No"]
+groups:
+  - id: "child"
+    repeat_for: "children"
+    max: 3
+    questions:
+      - id: "age"
+        text: "How old is child {index}?"
+        type: "number"
+      - id: "school"
+        text: "Does child {index} go to school?"
+        answers: ["Yes", "No"]
+        condition: 'values["child[{index}].age"] >= 3'`)
+
+		It("should ask the questions of the group once per counted item", func() {
+			q, err := gdq.New(config, gdq.WithStrictValidation())
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"children": gdq.Number(2), "pets": gdq.Choice(2)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Id).To(Equal("child[1].age"))
+			Expect(response.Questions[0].Text).To(Equal("How old is child 1?"))
+			Expect(response.Questions[0].Group).To(Equal("child"))
+			Expect(response.Questions[0].Iteration).To(Equal(1))
+			Expect(response.Questions[1].Id).To(Equal("child[2].age"))
+			Expect(response.Questions[1].Iteration).To(Equal(2))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{
+				"children":     gdq.Number(2),
+				"pets":         gdq.Choice(2),
+				"child[1].age": gdq.Number(7),
+				"child[2].age": gdq.Number(1),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("child[1].school"))
+			Expect(response.Questions[0].Text).To(Equal("Does child 1 go to school?"))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{
+				"children":        gdq.Number(2),
+				"pets":            gdq.Choice(2),
+				"child[1].age":    gdq.Number(7),
+				"child[2].age":    gdq.Number(1),
+				"child[1].school": gdq.Choice(1),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Progress.Current).To(Equal(response.Progress.Total))
+		})
+
+		It("should not repeat the group when no item is counted", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{"children": gdq.Number(0), "pets": gdq.Choice(1)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should validate the answers of the group questions", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"children": gdq.Number(1), "child[1].age": gdq.Text("seven")})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerType))
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"children": gdq.Number(1), "child[4].age": gdq.Number(7)})
+			Expect(err).To(MatchError(gdq.ErrInvalidQuestionID))
+		})
+
+		DescribeTable("should reject invalid groups",
+			func(group string) {
+				_, err := gdq.New([]byte(`
+questions:
+  - id: "children"
+    text: "How many children do you have?"
+    type: "number"
+  - id: "pets"
+    text: "Do you have pets?"
+    answers: ["Yes", "No"]
+groups:
+  - id: "child"` + group))
+				Expect(err).To(MatchError(gdq.ErrInvalidGroup))
+			},
+			Entry("unknown repeat_for question", `
+    repeat_for: "kids"
+    max: 3
+    questions:
+      - id: "age"
+        text: "How old is child {index}?"
+        type: "number"`),
+			Entry("repeat_for question that isn't a number question", `
+    repeat_for: "pets"
+    max: 3
+    questions:
+      - id: "age"
+        text: "How old is child {index}?"
+        type: "number"`),
+			Entry("no max", `
+    repeat_for: "children"
+    questions:
+      - id: "age"
+        text: "How old is child {index}?"
+        type: "number"`),
+			Entry("no questions", `
+    repeat_for: "children"
+    max: 3`),
+		)
+	})
+
+	Describe("Data Providers", func() {
+		config := []byte(`
+questions:
+  - id: "project"
+    text: "Which project is this feedback about?"
+    options_from: "projects"
+  - id: "reviewers"
+    text: "Who reviewed it?"
+    type: "multiple_choice"
+    options_from: "users"
+    condition: 'answers["project"] > 0'
+  - id: "owner"
+    text: "Who owns it?"
+    options_from: "users"
+    condition: 'answers["project"] > 0'`)
+
+		var calls map[string]int
+		provider := gdq.DataProviderFunc(func(source string) ([]gdq.DataOption, error) {
+			calls[source]++
+			switch source {
+			case "projects":
+				return []gdq.DataOption{{Id: "p1", Text: "Apollo"}, {Id: "p2", Text: "Gemini"}}, nil
+			case "users":
+				return []gdq.DataOption{{Text: "Alice"}, {Text: "Bob"}, {Text: "Carol"}}, nil
+			}
+			return nil, errors.New("unknown source")
+		})
+
+		BeforeEach(func() {
+			calls = make(map[string]int)
+		})
+
+		It("should offer the options supplied by the provider", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{}, gdq.WithDataProvider(provider))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Answers).To(Equal([]string{"Apollo", "Gemini"}))
+			Expect(response.Questions[0].AnswerIds).To(Equal([]string{"p1", "p2"}))
+
+			response, err = q.NextAnswers(map[string]gdq.Answer{"project": gdq.Choice(2)}, gdq.WithDataProvider(provider))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(2))
+			Expect(response.Questions[0].Answers).To(Equal([]string{"Alice", "Bob", "Carol"}))
+			Expect(response.Questions[1].Answers).To(Equal([]string{"Alice", "Bob", "Carol"}))
+		})
+
+		It("should request the options of a source once per call", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.NextAnswers(map[string]gdq.Answer{"project": gdq.Choice(1)}, gdq.WithDataProvider(provider))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(calls).To(Equal(map[string]int{"projects": 1, "users": 1}))
+		})
+
+		It("should validate the answers against the supplied options", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"project": 3}, gdq.WithDataProvider(provider))
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+
+			response, err := q.NextAnswers(map[string]gdq.Answer{
+				"project":   gdq.Choice(1),
+				"reviewers": gdq.Choices(1, 3),
+				"owner":     gdq.Choice(2),
+			}, gdq.WithDataProvider(provider))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+		})
+
+		It("should fail without a provider or when the provider fails", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(ContainSubstring("WithDataProvider")))
+
+			failing := gdq.DataProviderFunc(func(source string) ([]gdq.DataOption, error) {
+				return nil, errors.New("database unavailable")
+			})
+			_, err = q.Next(map[string]int{}, gdq.WithDataProvider(failing))
+			Expect(err).To(MatchError(ContainSubstring("database unavailable")))
+		})
+
+		It("should reject sources on questions without options or with their own answers", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "project"
+    text: "Which project is this feedback about?"
+    type: "text"
+    options_from: "projects"
+  - id: "owner"
+    text: "Who owns it?"
+    answers: ["Alice", "Bob"]
+    options_from: "users"`))
+			Expect(err).To(MatchError(gdq.ErrInvalidOptionsSource))
+			Expect(gdq.ValidationErrors(err)).To(HaveLen(2))
+		})
+	})
+
+	Describe("Parameters", func() {
+		config := []byte(`
+questions:
+  - id: "recommend"
+    text: "Would you recommend {{ .params.company_name }}?"
+    answers: ["Yes", "No"]
+closing_remarks:
+  - id: "discount"
+    text: "Enjoy {{ .params.discount }}% off your next order!"
+    condition: 'answers["recommend"] == 1 && {{ .params.discount }} > 0'`)
+
+		It("should render the params in texts and conditions", func() {
+			q, err := gdq.New(config, gdq.WithParams(map[string]interface{}{"company_name": "Acme", "discount": 10}))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Text).To(Equal("Would you recommend Acme?"))
+
+			response, err = q.Next(map[string]int{"recommend": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.ClosingRemarks).To(HaveLen(1))
+			Expect(response.ClosingRemarks[0].Text).To(Equal("Enjoy 10% off your next order!"))
+
+			q, err = gdq.New(config, gdq.WithParams(map[string]interface{}{"company_name": "Globex", "discount": 0}))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err = q.Next(map[string]int{"recommend": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.ClosingRemarks).To(BeEmpty())
+		})
+
+		It("should not let hostile values change the configuration", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "recommend"
+    text: "Would you recommend {{ .params.company_name }}?"
+    answers: ["Yes", "No"]
+    condition: '{{ .params.company_name }} != "Initech"'`), gdq.WithParams(map[string]interface{}{
+				"company_name": "Acme\"\n  - id: \"injected\"\n    text: 'x' || true || \"",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+			Expect(response.Questions[0].Id).To(Equal("recommend"))
+			Expect(response.Questions[0].Text).To(Equal("Would you recommend Acme\"\n  - id: \"injected\"\n    text: 'x' || true || \"?"))
+		})
+
+		It("should fail when a param is missing", func() {
+			_, err := gdq.New(config, gdq.WithParams(map[string]interface{}{"company_name": "Acme"}))
+			Expect(err).To(MatchError(ContainSubstring("discount")))
+		})
+
+		It("should keep the configuration as written without params", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "name"
+    text: "Hello {{name}}, how are you?"
+    answers: ["Fine", "Not great"]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Text).To(Equal("Hello {{name}}, how are you?"))
+		})
+	})
+
+	Describe("Object Stores", func() {
+		store := objectStore{
+			"surveys/onboarding.yaml": `
+questions:
+  - id: "role"
+    text: "What's your role?"
+    answers: ["Developer", "Manager"]`,
+		}
+
+		It("should load configurations from the object store registered for the scheme", func() {
+			q, err := gdq.New("s3://surveys/onboarding.yaml", gdq.WithObjectStore("s3", store))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Id).To(Equal("role"))
+		})
+
+		It("should fail when the object can't be read", func() {
+			_, err := gdq.New("gs://surveys/onboarding.yaml", gdq.WithObjectStore("s3", store))
+			Expect(err).To(MatchError(ContainSubstring("no object store registered for gs:// URIs")))
+
+			_, err = gdq.New("s3://surveys/missing.yaml", gdq.WithObjectStore("s3", store))
+			Expect(err).To(MatchError(ContainSubstring("object not found")))
+
+			_, err = gdq.New("s3://onboarding.yaml", gdq.WithObjectStore("s3", store))
+			Expect(err).To(MatchError(ContainSubstring("expected s3://bucket/key")))
+		})
+	})
+
+	Describe("Key-Value Store Watch", func() {
+		version := func(text string) []byte {
+			return []byte(`
+questions:
+  - id: "role"
+    text: "` + text + `"
+    answers: ["Developer", "Manager"]`)
+		}
+
+		It("should swap the questionnaire when the key changes", func(ctx SpecContext) {
+			store := &kvStore{value: version("What's your role?"), changes: make(chan []byte)}
+			watcher, err := gdq.WatchKV(ctx, store, "surveys/onboarding")
+			Expect(err).ToNot(HaveOccurred())
+
+			questionText := func() string {
+				response, err := watcher.Questionnaire().Next(map[string]int{})
+				Expect(err).ToNot(HaveOccurred())
+				return response.Questions[0].Text
+			}
+			Expect(questionText()).To(Equal("What's your role?"))
+
+			store.changes <- version("What's your job?")
+			Eventually(questionText).Should(Equal("What's your job?"))
+			Expect(watcher.Err()).ToNot(HaveOccurred())
+
+			store.changes <- []byte(`questions: [{id: "role", text: "Role?", answers: []}]`)
+			Eventually(watcher.Err).Should(MatchError(gdq.ErrEmptyAnswers))
+			Expect(questionText()).To(Equal("What's your job?"))
+		})
+
+		It("should fail when the key can't be loaded", func(ctx SpecContext) {
+			_, err := gdq.WatchKV(ctx, &kvStore{}, "surveys/onboarding")
+			Expect(err).To(MatchError(ContainSubstring(`failed to read key "surveys/onboarding"`)))
+		})
+	})
+
+	Describe("Signature Verification", func() {
+		config := []byte(`
+questions:
+  - id: "role"
+    text: "What's your role?"
+    answers: ["Developer", "Manager"]`)
+		publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+
+		It("should load configurations signed by the trusted key", func() {
+			_, err := gdq.New(config, gdq.WithSignature(publicKey, ed25519.Sign(privateKey, config)))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject configurations that aren't signed by the trusted key", func() {
+			_, otherKey, _ := ed25519.GenerateKey(nil)
+			_, err := gdq.New(config, gdq.WithSignature(publicKey, ed25519.Sign(otherKey, config)))
+			Expect(err).To(MatchError(ContainSubstring("invalid signature")))
+
+			tampered := append(bytes.Clone(config), []byte("\n    default: 2")...)
+			_, err = gdq.New(tampered, gdq.WithSignature(publicKey, ed25519.Sign(privateKey, config)))
+			Expect(err).To(MatchError(ContainSubstring("invalid signature")))
+		})
+	})
+
+	Describe("Content Transforms", func() {
+		It("should parse the transformed content", func() {
+			encrypted := []byte(base64.StdEncoding.EncodeToString([]byte(`
+questions:
+  - id: "role"
+    text: "What's your role?"
+    answers: ["Developer", "Manager"]`)))
+
+			q, err := gdq.New(encrypted, gdq.WithContentTransform(func(content []byte) ([]byte, error) {
+				return base64.StdEncoding.DecodeString(string(content))
+			}))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Id).To(Equal("role"))
+		})
+
+		It("should fail when a transform fails", func() {
+			_, err := gdq.New([]byte("ENC[...]"), gdq.WithContentTransform(func(content []byte) ([]byte, error) {
+				return nil, errors.New("no decryption key")
+			}))
+			Expect(err).To(MatchError(ContainSubstring("failed to transform content: no decryption key")))
+		})
+	})
+
+	Describe("Audit Trail", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "income"
+    text: "What is your income bracket?"
+    answers: ["Low", "High"]
+    condition: 'answers["employed"] == 1'`)
+
+		It("should record every Next call with the conditions evaluated", func() {
+			sink := &recordingSink{}
+			q, err := gdq.New(config, gdq.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"employed": 1})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = q.Next(map[string]int{"employed": 1, "income": 2})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(sink.records).To(HaveLen(2))
+			first := sink.records[0]
+			Expect(first.Checksum).To(Equal(q.Checksum()))
+			Expect(first.Questions).To(Equal([]string{"income"}))
+			Expect(first.Completed).To(BeFalse())
+			Expect(first.Evaluations).To(ContainElement(gdq.ConditionEvaluation{Condition: `answers["employed"] == 1`, Result: true}))
+			Expect(sink.records[1].Completed).To(BeTrue())
+
+			hash, err := gdq.HashAnswers(map[string]int{"employed": 1}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.AnswersHash).To(Equal(hash))
+			Expect(sink.records[1].AnswersHash).ToNot(Equal(hash))
+		})
+
+		It("should record failed calls with their error", func() {
+			sink := &recordingSink{}
+			q, err := gdq.New(config, gdq.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"employed": 3})
+			Expect(err).To(HaveOccurred())
+			Expect(sink.records).To(HaveLen(1))
+			Expect(sink.records[0].Error).To(Equal(err.Error()))
+		})
+
+		It("should fail when the record can't be stored", func() {
+			sink := &recordingSink{err: errors.New("database unavailable")}
+			q, err := gdq.New(config, gdq.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(ContainSubstring("failed to record audit trail: database unavailable")))
+		})
+
+		It("should not record what-if previews", func() {
+			sink := &recordingSink{}
+			q, err := gdq.New(config, gdq.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.WhatIf(map[string]int{}, map[string]int{"employed": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.records).To(BeEmpty())
+		})
+	})
+
+	Describe("Answer Hash Chain", func() {
+		genesis := "checksum"
+
+		chain := func() []gdq.AnswerLink {
+			first, err := gdq.ChainAnswers(genesis, map[string]int{"employed": 1}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			second, err := gdq.ChainAnswers(first.Hash, map[string]int{"employed": 1, "income": 2}, map[string]interface{}{"age": 42})
+			Expect(err).ToNot(HaveOccurred())
+			return []gdq.AnswerLink{first, second}
+		}
+
+		It("should verify an unmodified chain", func() {
+			links := chain()
+			Expect(links[1].Previous).To(Equal(links[0].Hash))
+			Expect(gdq.VerifyAnswerChain(genesis, links)).To(Succeed())
+		})
+
+		It("should detect modified answers", func() {
+			links := chain()
+			links[0].Answers = map[string]int{"employed": 2}
+			err := gdq.VerifyAnswerChain(genesis, links)
+			Expect(err).To(MatchError(gdq.ErrAnswerChainBroken))
+			Expect(err).To(MatchError(ContainSubstring("the answers of link 1 don't match its hash")))
+		})
+
+		It("should detect modified values", func() {
+			links := chain()
+			links[1].Values = map[string]interface{}{"age": 43}
+			Expect(gdq.VerifyAnswerChain(genesis, links)).To(MatchError(gdq.ErrAnswerChainBroken))
+		})
+
+		It("should detect removed links and another genesis", func() {
+			links := chain()
+			Expect(gdq.VerifyAnswerChain(genesis, links[1:])).To(MatchError(ContainSubstring("link 1 isn't chained to the previous link")))
+			Expect(gdq.VerifyAnswerChain("other", links)).To(MatchError(gdq.ErrAnswerChainBroken))
+		})
+
+		It("should verify links stored as JSON", func() {
+			content, err := json.Marshal(chain())
+			Expect(err).ToNot(HaveOccurred())
+			var links []gdq.AnswerLink
+			Expect(json.Unmarshal(content, &links)).To(Succeed())
+			Expect(gdq.VerifyAnswerChain(genesis, links)).To(Succeed())
+		})
+	})
+
+	Describe("Sensitive Answers", func() {
+		config := []byte(`
+questions:
+  - id: "employed"
+    text: "Are you employed?"
+    answers: ["Yes", "No"]
+  - id: "salary"
+    text: "What is your salary range?"
+    answers: ["Low", "High"]
+    sensitive: true
+  - id: "satisfied"
+    text: "Are you satisfied?"
+    answers: ["Yes", "No"]
+    condition: 'answers["salary"] == 2'`)
+		answers := map[string]int{"employed": 1, "salary": 2, "satisfied": 1}
+
+		It("should flag sensitive questions", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Questions()[1].Sensitive).To(BeTrue())
+
+			summary, err := q.Summary(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary[1].Answer).To(Equal("High"))
+		})
+
+		It("should redact sensitive answers", func() {
+			var completion gdq.Completion
+			sink := &recordingSink{}
+			q, err := gdq.New(config,
+				gdq.WithSensitiveAnswers(gdq.RedactSensitiveAnswers),
+				gdq.WithCompletionHook(func(c gdq.Completion) { completion = c }),
+				gdq.WithAuditSink(sink),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			summary, err := q.Summary(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(HaveLen(3))
+			Expect(summary[1]).To(Equal(gdq.SummaryItem{QuestionID: "salary", Question: "What is your salary range?", Answer: gdq.RedactedAnswer}))
+
+			review, err := q.Review(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(review[1].Answer).To(Equal(gdq.Answer{}))
+			Expect(review[1].AnswerText).To(Equal(gdq.RedactedAnswer))
+
+			response, err := q.Next(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(completion.Answers).To(Equal(map[string]int{"employed": 1, "satisfied": 1}))
+
+			hash, err := gdq.HashAnswers(map[string]int{"employed": 1, "satisfied": 1}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.records[0].AnswersHash).To(Equal(hash))
+
+			_, err = q.Next(map[string]int{"employed": 1, "salary": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.records[1].Evaluations).To(ContainElement(gdq.ConditionEvaluation{Condition: `answers["salary"] == 2`, Redacted: true}))
+
+			_, err = q.Next(map[string]int{"employed": 1, "salary": 5})
+			Expect(err).To(MatchError(gdq.ErrInvalidAnswerRange))
+			Expect(sink.records[2].Error).To(Equal("validation error (invalid_answer_range): " + gdq.RedactedAnswer))
+
+			_, err = q.Next(map[string]int{"employed": 3})
+			Expect(err).To(HaveOccurred())
+			Expect(sink.records[3].Error).To(Equal(err.Error()))
+		})
+
+		It("should strip sensitive answers", func() {
+			sink := &recordingSink{}
+			q, err := gdq.New(config, gdq.WithSensitiveAnswers(gdq.StripSensitiveAnswers), gdq.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+
+			summary, err := q.Summary(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(HaveLen(2))
+			Expect(summary[1].QuestionID).To(Equal("satisfied"))
+
+			review, err := q.Review(answers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(review).To(HaveLen(2))
+
+			response, err := q.Next(map[string]int{"employed": 1, "salary": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions[0].Id).To(Equal("satisfied"))
+			Expect(sink.records[0].Questions).To(Equal([]string{"satisfied"}))
+			for _, evaluation := range sink.records[0].Evaluations {
+				Expect(evaluation.Condition).ToNot(ContainSubstring("salary"))
+			}
+		})
+	})
+
+	Describe("Quotas", func() {
+		config := []byte(`
+quotas:
+  - id: "young_adults"
+    limit: 2
+    remark: "Thank you! We already have enough respondents in your age group."
+questions:
+  - id: "age"
+    text: "How old are you?"
+    answers:
+      - text: "18-24"
+        quota: "young_adults"
+      - "25 or more"
+  - id: "brand"
+    text: "Which brand do you prefer?"
+    answers: ["A", "B"]
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you for your answers!"`)
+
+		counts := map[string]int{}
+		checker := gdq.QuotaCheckerFunc(func(quota string) (int, error) {
+			return counts[quota], nil
+		})
+
+		BeforeEach(func() {
+			counts = map[string]int{}
+		})
+
+		It("should continue while the quota isn't full", func() {
+			var completion gdq.Completion
+			q, err := gdq.New(config, gdq.WithQuotaChecker(checker), gdq.WithCompletionHook(func(c gdq.Completion) { completion = c }))
+			Expect(err).ToNot(HaveOccurred())
+
+			counts["young_adults"] = 1
+			response, err := q.Next(map[string]int{"age": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeFalse())
+			Expect(response.Questions[0].Id).To(Equal("brand"))
+
+			response, err = q.Next(map[string]int{"age": 1, "brand": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.QuotaFull).To(BeEmpty())
+			Expect(response.ClosingRemarks[0].Id).To(Equal("thanks"))
+			Expect(completion.Quotas).To(Equal([]string{"young_adults"}))
+		})
+
+		It("should screen respondents out once the quota is full", func() {
+			var completion gdq.Completion
+			q, err := gdq.New(config, gdq.WithQuotaChecker(checker), gdq.WithCompletionHook(func(c gdq.Completion) { completion = c }))
+			Expect(err).ToNot(HaveOccurred())
+
+			counts["young_adults"] = 2
+			response, err := q.Next(map[string]int{"age": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+			Expect(response.Terminated).To(BeTrue())
+			Expect(response.QuotaFull).To(Equal("young_adults"))
+			Expect(response.ClosingRemarks).To(Equal([]gdq.ClosingRemark{
+				{Id: "young_adults", Text: "Thank you! We already have enough respondents in your age group."},
+			}))
+			Expect(completion.QuotaFull).To(Equal("young_adults"))
+
+			response, err = q.Next(map[string]int{"age": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeFalse())
+		})
+
+		It("should not enforce quotas without a checker", func() {
+			q, err := gdq.New(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{"age": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeFalse())
+		})
+
+		It("should fail when the quotas can't be counted", func() {
+			q, err := gdq.New(config, gdq.WithQuotaChecker(gdq.QuotaCheckerFunc(func(string) (int, error) {
+				return 0, errors.New("database unavailable")
+			})))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"age": 1})
+			Expect(err).To(MatchError(ContainSubstring("failed to count quota 'young_adults': database unavailable")))
+		})
+
+		It("should reject invalid quotas", func() {
+			_, err := gdq.New([]byte(`
+quotas:
+  - id: "young_adults"
+    limit: 0
+    remark: "Thank you!"
+questions:
+  - id: "age"
+    text: "How old are you?"
+    answers:
+      - text: "18-24"
+        quota: "teenagers"
+      - "25 or more"`))
+			Expect(err).To(MatchError(gdq.ErrInvalidQuota))
+			Expect(err).To(MatchError(ContainSubstring("quota 'young_adults' is invalid: limit must be at least 1")))
+			Expect(err).To(MatchError(ContainSubstring("quota 'teenagers' is invalid: answer options of question 'age' are counted toward it, but it is not declared")))
+		})
+	})
+
+	Describe("Availability Window", func() {
+		config := []byte(`
+available_from: 2025-03-01T09:00:00Z
+available_until: 2025-04-01T00:00:00Z
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`)
+
+		at := func(value string) gdq.Option {
+			now, err := time.Parse(time.RFC3339, value)
+			Expect(err).ToNot(HaveOccurred())
+			return gdq.WithClock(func() time.Time { return now })
+		}
+
+		It("should accept answers within the window", func() {
+			q, err := gdq.New(config, at("2025-03-15T12:00:00Z"))
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Questions).To(HaveLen(1))
+		})
+
+		It("should fail before the questionnaire opens", func() {
+			q, err := gdq.New(config, at("2025-03-01T08:59:59Z"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(gdq.ErrNotYetAvailable))
+			Expect(err).To(MatchError("validation error (not_yet_available): questionnaire is not available until 2025-03-01T09:00:00Z"))
+			Expect(gdq.LocalizeError(err, "fr")).To(Equal("le questionnaire n'est pas disponible avant 2025-03-01T09:00:00Z"))
+		})
+
+		It("should fail once the questionnaire has expired", func() {
+			q, err := gdq.New(config, at("2025-04-01T00:00:00Z"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).To(MatchError(gdq.ErrExpiredQuestionnaire))
+			Expect(err).To(MatchError(ContainSubstring("questionnaire expired on 2025-04-01T00:00:00Z")))
+		})
+
+		It("should reject an empty window", func() {
+			_, err := gdq.New([]byte(`
+available_from: 2025-04-01T00:00:00Z
+available_until: 2025-03-01T00:00:00Z
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`))
+			Expect(err).To(MatchError(gdq.ErrInvalidAvailability))
+		})
+	})
+
+	Describe("Directory Loading", func() {
+		write := func(dir, name, content string) {
+			Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+		}
+		valid := `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`
+
+		It("should load every configuration keyed by ID", func() {
+			dir := GinkgoT().TempDir()
+			write(dir, "onboarding.yaml", valid)
+			write(dir, "feedback.json", `{"id": "nps", "questions": [{"id": "q1", "text": "Question 1?", "answers": ["Yes", "No"]}]}`)
+			write(dir, "README.md", "Not a questionnaire")
+			Expect(os.Mkdir(filepath.Join(dir, "drafts.yaml"), 0o700)).To(Succeed())
+
+			questionnaires, err := gdq.LoadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(questionnaires).To(HaveLen(2))
+			Expect(questionnaires).To(HaveKey("onboarding"))
+			Expect(questionnaires).To(HaveKey("nps"))
+		})
+
+		It("should report the errors of every invalid file", func() {
+			dir := GinkgoT().TempDir()
+			write(dir, "valid.yaml", valid)
+			write(dir, "empty_answers.yaml", `
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: []`)
+			write(dir, "broken.yml", "questions: [")
+
+			questionnaires, err := gdq.LoadDir(dir)
+			Expect(questionnaires).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring(filepath.Join(dir, "empty_answers.yaml") + ": questionnaire validation failed")))
+			Expect(err).To(MatchError(ContainSubstring(filepath.Join(dir, "broken.yml") + ": failed to load config")))
+			Expect(err).To(MatchError(gdq.ErrEmptyAnswers))
+		})
+
+		It("should reject duplicated IDs", func() {
+			dir := GinkgoT().TempDir()
+			write(dir, "a.yaml", "id: \"survey\"\n"+valid)
+			write(dir, "b.yaml", "id: \"survey\"\n"+valid)
+
+			_, err := gdq.LoadDir(dir)
+			Expect(err).To(MatchError(ContainSubstring("questionnaire ID 'survey' is already used by " + filepath.Join(dir, "a.yaml"))))
+		})
+	})
+
+	Describe("Stats", func() {
+		It("should measure the complexity of the questionnaire", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers:
+      - "Yes"
+      - "No"
+      - text: "Stop"
+        terminates: true
+  - id: "q2"
+    text: "Question 2?"
+    condition: 'answers["q1"] == 1'
+    answers: ["A", "B"]
+  - id: "q3"
+    text: "Question 3?"
+    condition: 'answers["q2"] == 1'
+    answers: ["C", "D"]
+  - id: "q4"
+    text: "Question 4?"
+    condition: 'answers["q1"] == 2'
+    answers: ["E", "F"]
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Stats()).To(Equal(gdq.Stats{
+				Questions:            4,
+				QuestionsByType:      map[string]int{gdq.ChoiceQuestion: 4},
+				ConditionalQuestions: 3,
+				Answers:              9,
+				ClosingRemarks:       1,
+				Depth:                3,
+				BranchFactor:         1.5,
+				Outcomes:             4,
+				TerminatedOutcomes:   1,
+				LongestPath:          3,
+			}))
+		})
+
+		It("should report that the paths couldn't all be explored", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "name"
+    text: "What is your name?"
+    type: "text"
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]`))
+			Expect(err).ToNot(HaveOccurred())
+			stats := q.Stats()
+			Expect(stats.QuestionsByType).To(Equal(map[string]int{gdq.TextQuestion: 1, gdq.ChoiceQuestion: 1}))
+			Expect(stats.Depth).To(Equal(1))
+			Expect(stats.BranchFactor).To(BeZero())
+			Expect(stats.Outcomes).To(BeZero())
+			Expect(stats.Truncated).To(BeTrue())
+		})
+	})
+
+	Describe("Diff", func() {
+		load := func(config string) gdq.Questionnaire {
+			q, err := gdq.New([]byte(config))
+			Expect(err).ToNot(HaveOccurred())
+			return q
+		}
+		previous := `
+questions:
+  - id: "role"
+    text: "What is your role?"
+    answers: ["Developer", "Manager", "Other"]
+  - id: "team"
+    text: "How big is your team?"
+    condition: 'answers["role"] == 2'
+    answers: ["Small", "Large"]
+  - id: "languages"
+    text: "Which languages do you use?"
+    type: "multiple_choice"
+    skippable: true
+    answers: ["Go", "Python"]
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`
+
+		It("should report no change between equivalent versions", func() {
+			changes, err := gdq.Diff(load(previous), load(previous))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(BeNil())
+		})
+
+		It("should reject other implementations of Questionnaire", func() {
+			type wrapped struct{ gdq.Questionnaire }
+			_, err := gdq.Diff(load(previous), wrapped{load(previous)})
+			Expect(err).To(MatchError(gdq.ErrUnsupportedQuestionnaire))
+			Expect(err).To(MatchError(ContainSubstring("current version is")))
+		})
+
+		It("should report compatible changes", func() {
+			changes, err := gdq.Diff(load(previous), load(`
+questions:
+  - id: "role"
+    text: "What's your role?"
+    answers: ["Developer", "Manager", "Something else", "Designer"]
+  - id: "team"
+    text: "How big is your team?"
+    condition: 'answers["role"] == 1 || answers["role"] == 2'
+    answers: ["Small", "Large"]
+  - id: "languages"
+    text: "Which languages do you use?"
+    type: "multiple_choice"
+    skippable: true
+    answers: ["Go", "Python"]
+  - id: "feedback"
+    text: "Any feedback?"
+    type: "text"
+    required: false
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"
+  - id: "bye"
+    text: "Bye!"`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]gdq.Change{
+				{Type: gdq.TextChange, QuestionID: "role", Message: `text of question 'role' changed from "What is your role?" to "What's your role?"`},
+				{Type: gdq.TextChange, QuestionID: "role", Message: `text of answer 3 of question 'role' changed from "Other" to "Something else"`},
+				{Type: gdq.AnswerAddedChange, QuestionID: "role", Message: `answer 4 ("Designer") was added to question 'role'`},
+				{Type: gdq.ConditionChange, QuestionID: "team", Message: `condition of question 'team' changed from 'answers["role"] == 2' to 'answers["role"] == 1 || answers["role"] == 2'`},
+				{Type: gdq.QuestionAddedChange, QuestionID: "feedback", Message: "question 'feedback' was added"},
+				{Type: gdq.RemarkAddedChange, RemarkID: "bye", Message: "closing remark 'bye' was added"},
+			}))
+		})
+
+		It("should flag the changes breaking stored answers", func() {
+			changes, err := gdq.Diff(load(previous), load(`
+questions:
+  - id: "role"
+    text: "What is your role?"
+    answers: ["Manager", "Developer"]
+  - id: "languages"
+    text: "Which languages do you use?"
+    type: "multiple_choice"
+    max_choices: 1
+    answers: ["Go", "Python"]
+closing_remarks:
+  - id: "thanks"
+    text: "Thank you!"`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]gdq.Change{
+				{Type: gdq.AnswerMovedChange, QuestionID: "role", Breaking: true, Message: `answer 1 of question 'role' ("Developer") moved to 2: stored answers 1 now mean "Manager"`},
+				{Type: gdq.AnswerMovedChange, QuestionID: "role", Breaking: true, Message: `answer 2 of question 'role' ("Manager") moved to 1: stored answers 2 now mean "Developer"`},
+				{Type: gdq.AnswerRemovedChange, QuestionID: "role", Breaking: true, Message: `answer 3 of question 'role' ("Other") was removed: stored answers 3 are rejected`},
+				{Type: gdq.QuestionRemovedChange, QuestionID: "team", Breaking: true, Message: "question 'team' was removed: stored answers to it are rejected"},
+				{Type: gdq.RequirementChange, QuestionID: "languages", Breaking: true, Message: "question 'languages' can't be skipped anymore: stored skipped answers are rejected"},
+				{Type: gdq.ChoiceLimitsChange, QuestionID: "languages", Breaking: true, Message: "choice limits of question 'languages' changed from 0-any to 0-1: stored answers may be rejected"},
+			}))
+		})
+
+		It("should flag the number bounds made stricter", func() {
+			age := `
+questions:
+  - id: "age"
+    text: "How old are you?"
+    type: "number"
+%s`
+			changes, err := gdq.Diff(load(fmt.Sprintf(age, "    min: 18")), load(fmt.Sprintf(age, "    min: 21\n    max: 120")))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]gdq.Change{
+				{Type: gdq.NumberBoundsChange, QuestionID: "age", Breaking: true, Message: "bounds of question 'age' changed from 18-any to 21-120: stored answers may be rejected"},
+			}))
+
+			changes, err = gdq.Diff(load(fmt.Sprintf(age, "    min: 21\n    max: 120")), load(fmt.Sprintf(age, "    min: 0")))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]gdq.Change{
+				{Type: gdq.NumberBoundsChange, QuestionID: "age", Message: "bounds of question 'age' changed from 21-120 to 0-any"},
+			}))
+		})
+
+		It("should match answer options by ID", func() {
+			changes, err := gdq.Diff(load(`
+questions:
+  - id: "plan"
+    text: "Which plan?"
+    answers:
+      - id: "free"
+        text: "Free"
+      - id: "pro"
+        text: "Pro"`), load(`
+questions:
+  - id: "plan"
+    text: "Which plan?"
+    answers:
+      - id: "free"
+        text: "Starter"
+      - id: "team"
+        text: "Team"`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]gdq.Change{
+				{Type: gdq.TextChange, QuestionID: "plan", Message: `text of answer 1 of question 'plan' changed from "Free" to "Starter"`},
+				{Type: gdq.AnswerRemovedChange, QuestionID: "plan", Breaking: true, Message: `answer 2 of question 'plan' ("Pro") was removed: stored answers 2 now mean "Team"`},
+				{Type: gdq.AnswerAddedChange, QuestionID: "plan", Message: `answer 2 ("Team") was added to question 'plan'`},
+			}))
+		})
+	})
+
+	Describe("Logging", func() {
+		var logs *bytes.Buffer
+		var logger *slog.Logger
+
+		BeforeEach(func() {
+			logs = &bytes.Buffer{}
+			logger = slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		})
+
+		It("should log loading and condition evaluations", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'`), gdq.WithLogger(logger))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logs.String()).To(ContainSubstring(`level=DEBUG msg="questionnaire loaded" questions=2 closing_remarks=0`))
+
+			_, err = q.Next(map[string]int{"q1": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logs.String()).To(ContainSubstring(`msg="condition evaluated" condition="answers[\"q1\"] == 1" result=false`))
+		})
+
+		It("should log validation errors and warnings", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: []`), gdq.WithLogger(logger))
+			Expect(err).To(HaveOccurred())
+			Expect(logs.String()).To(ContainSubstring(`msg="questionnaire validation error" type=empty_answers`))
+
+			_, err = gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 3'`), gdq.WithLogger(logger))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logs.String()).To(ContainSubstring(`msg="questionnaire validation warning" type=unreachable_question`))
+		})
+	})
+
+	Describe("Metrics", func() {
+		var metrics *recordingMetrics
+
+		BeforeEach(func() {
+			metrics = &recordingMetrics{validationErrors: map[string]int{}}
+		})
+
+		It("should count Next calls, completions and condition evaluations", func() {
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]
+  - id: "q2"
+    text: "Question 2?"
+    answers: ["Yes", "No"]
+    depends_on: ["q1"]
+    condition: 'answers["q1"] == 1'`), gdq.WithMetrics(metrics))
+			Expect(err).ToNot(HaveOccurred())
+			evaluationsAtLoad := metrics.evaluations
+
+			_, err = q.Next(map[string]int{})
+			Expect(err).ToNot(HaveOccurred())
+			response, err := q.Next(map[string]int{"q1": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Completed).To(BeTrue())
+
+			Expect(metrics.nextCalls).To(Equal(2))
+			Expect(metrics.completions).To(Equal(1))
+			Expect(metrics.evaluations).To(BeNumerically(">", evaluationsAtLoad))
+		})
+
+		It("should count validation errors by type", func() {
+			_, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: []
+  - id: "q2"
+    text: "Question 2?"
+    answers: []`), gdq.WithMetrics(metrics))
+			Expect(err).To(HaveOccurred())
+			Expect(metrics.validationErrors).To(Equal(map[string]int{gdq.EmptyAnswersErrType: 2}))
+
+			q, err := gdq.New([]byte(`
+questions:
+  - id: "q1"
+    text: "Question 1?"
+    answers: ["Yes", "No"]`), gdq.WithMetrics(metrics))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Next(map[string]int{"q1": 3})
+			Expect(err).To(HaveOccurred())
+			Expect(metrics.nextCalls).To(Equal(1))
+			Expect(metrics.validationErrors).To(HaveKeyWithValue(gdq.InvalidAnswerRangeErrType, 1))
+		})
+	})
+})
+
+// recordingMetrics is a gdq.Metrics counting what it receives.
+type recordingMetrics struct {
+	nextCalls        int
+	completions      int
+	evaluations      int
+	validationErrors map[string]int
+}
+
+func (m *recordingMetrics) NextCalled()                      { m.nextCalls++ }
+func (m *recordingMetrics) Completed()                       { m.completions++ }
+func (m *recordingMetrics) ValidationFailed(errType string)  { m.validationErrors[errType]++ }
+func (m *recordingMetrics) ConditionEvaluated(time.Duration) { m.evaluations++ }
+
+// recordingSink is a gdq.AuditSink keeping the records it receives, or failing with err.
+type recordingSink struct {
+	records []gdq.AuditRecord
+	err     error
+}
+
+func (s *recordingSink) Record(record gdq.AuditRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+// objectStore is a gdq.ObjectStore serving the objects from memory, by "bucket/key".
+type objectStore map[string]string
+
+func (s objectStore) Object(bucket, key string) (io.ReadCloser, error) {
+	content, ok := s[bucket+"/"+key]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// kvStore is a gdq.KVStore holding a single value, whose changes are sent on the channel.
+type kvStore struct {
+	value   []byte
+	changes chan []byte
+}
+
+func (s *kvStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if s.value == nil {
+		return nil, errors.New("key not found")
+	}
+	return s.value, nil
+}
+
+func (s *kvStore) Watch(ctx context.Context, key string, onChange func([]byte)) error {
+	for {
+		select {
+		case value := <-s.changes:
+			onChange(value)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// QuotaChecker counts the respondents already counted toward the quotas of a questionnaire (see WithQuotaChecker),
+	// for instance from a database incremented by the completion hook with Completion.Quotas.
+	//
+	// Implementations must be safe for concurrent use, as a questionnaire is shared between goroutines.
+	QuotaChecker interface {
+		// Count returns the number of respondents counted toward the quota.
+		Count(quota string) (int, error)
+	}
+
+	// QuotaCheckerFunc is a function used as a QuotaChecker.
+	//
+	// Example usage:
+	//   checker := gdq.QuotaCheckerFunc(func(quota string) (int, error) {
+	//       return store.QuotaCount(surveyID, quota)
+	//   })
+	QuotaCheckerFunc func(quota string) (int, error)
+
+	// quota limits the number of respondents choosing the answer options counted toward it,
+	// e.g. in market-research screening.
+	//
+	// Example usage in YAML:
+	//
+	//	quotas:
+	//	  - id: "young_adults"
+	//	    limit: 100
+	//	    remark: "Thank you! We already have enough respondents in your age group."
+	//	questions:
+	//	  - id: "age"
+	//	    text: "How old are you?"
+	//	    answers:
+	//	      - text: "18-24"
+	//	        quota: "young_adults"
+	//	      - "25 or more"
+	quota struct {
+		Id     string        `yaml:"id" json:"id"`         // Unique identifier for the quota, referenced by the answer options
+		Limit  int           `yaml:"limit" json:"limit"`   // Number of respondents after which the quota is full
+		Remark localizedText `yaml:"remark" json:"remark"` // Closing remark shown to the respondents screened out by the full quota
+	}
+)
+
+// Count calls f(quota).
+func (f QuotaCheckerFunc) Count(quota string) (int, error) {
+	return f(quota)
+}
+
+// validateQuotas checks that the quotas have a unique ID, a limit of at least 1 and a remark,
+// and that the answer options are counted toward declared quotas.
+func (q *questionnaire) validateQuotas() error {
+	var errs []error
+	quotaIDs := make(map[string]bool)
+	for _, quota := range q.Quotas {
+		switch {
+		case quota.Id == "":
+			errs = append(errs, invalidQuotaError(quota.Id, errors.New("the quota has no ID")))
+		case quotaIDs[quota.Id]:
+			errs = append(errs, invalidQuotaError(quota.Id, errors.New("the quota ID is used more than once")))
+		case quota.Limit < 1:
+			errs = append(errs, invalidQuotaError(quota.Id, errors.New("limit must be at least 1")))
+		case quota.Remark.String() == "":
+			errs = append(errs, invalidQuotaError(quota.Id, errors.New("the quota has no remark")))
+		}
+		quotaIDs[quota.Id] = true
+	}
+
+	for _, question := range q.QuestionList {
+		for _, option := range question.Answers {
+			if option.Quota != "" && !quotaIDs[option.Quota] {
+				errs = append(errs, invalidQuotaError(option.Quota, fmt.Errorf("answer options of question '%s' are counted toward it, but it is not declared", question.Id)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// chosenQuotas returns the IDs of the quotas the chosen answer options are counted toward, in configuration order.
+func (q *questionnaire) chosenQuotas(answers map[string]int) []string {
+	var quotas []string
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		for _, choice := range q.selectedChoices(question, answers) {
+			if id := question.Answers[choice-1].Quota; id != "" && !contains(quotas, id) {
+				quotas = append(quotas, id)
+			}
+		}
+	}
+	return quotas
+}
+
+// fullQuota returns the first full quota the chosen answer options are counted toward, nil when there is none
+// or no quota checker is set (see WithQuotaChecker).
+func (q *questionnaire) fullQuota(answers map[string]int) (*quota, error) {
+	if q.quotaChecker == nil {
+		return nil, nil
+	}
+	for _, id := range q.chosenQuotas(answers) {
+		count, err := q.quotaChecker.Count(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count quota '%s': %w", id, err)
+		}
+		for i := range q.Quotas {
+			if quota := &q.Quotas[i]; quota.Id == id && count >= quota.Limit {
+				return quota, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,147 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+// detectOutOfRangeConstants reports the conditions comparing the answer of a choice question
+// to a constant that isn't one of its answers, such as answers["q1"] == 7 when q1 only has 3 options:
+// the comparison never holds, which silently makes a branch unreachable.
+//
+// Equality comparisons (==, !=), in lists and the anyOf and noneOf helpers are checked.
+// 0 (unanswered) and SkipAnswer, for questions that can be skipped, are valid constants.
+func (q *questionnaire) detectOutOfRangeConstants() []Warning {
+	var warnings []Warning
+	check := func(condition, questionID, remarkID string) {
+		for _, constant := range q.outOfRangeConstants(condition) {
+			warnings = append(warnings, Warning{
+				Type:       OutOfRangeConstantWarning,
+				QuestionID: questionID,
+				RemarkID:   remarkID,
+				Message: fmt.Sprintf("condition '%s' compares the answer of question '%s' to %d, which is out of range (valid: 1-%d)",
+					condition, constant.questionID, constant.value, constant.options),
+			})
+		}
+	}
+
+	for _, question := range q.QuestionList {
+		check(question.Condition, question.Id, "")
+		check(question.TerminateIf, question.Id, "")
+		for _, option := range question.Answers {
+			check(option.Condition, question.Id, "")
+		}
+	}
+	for _, remark := range q.Remarks {
+		check(remark.Condition, "", remark.Id)
+	}
+	for _, rule := range q.Rules {
+		check(rule.Condition, "", "")
+	}
+	check(q.CompleteWhen, "", "")
+	return warnings
+}
+
+// outOfRangeConstant is a constant compared to the answer of a question it can't be equal to.
+type outOfRangeConstant struct {
+	questionID string // The question whose answer is compared
+	value      int    // The constant
+	options    int    // The number of answer options of the question
+}
+
+// outOfRangeConstants returns the out-of-range constants the condition compares answers to.
+// Conditions that can't be parsed are reported by compileConditions.
+func (q *questionnaire) outOfRangeConstants(condition string) []outOfRangeConstant {
+	if condition == "" {
+		return nil
+	}
+	tree, err := parser.Parse(condition)
+	if err != nil {
+		return nil
+	}
+
+	visitor := &constantVisitor{q: q}
+	ast.Walk(&tree.Node, visitor)
+	return visitor.found
+}
+
+// constantVisitor walks a condition and collects the out-of-range constants compared to answers.
+type constantVisitor struct {
+	q     *questionnaire
+	found []outOfRangeConstant
+}
+
+// Visit checks the comparisons of an answer to constants.
+func (v *constantVisitor) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.BinaryNode:
+		switch n.Operator {
+		case "==", "!=":
+			v.check(n.Left, n.Right)
+			v.check(n.Right, n.Left)
+		case "in", "not in":
+			if list, ok := n.Right.(*ast.ArrayNode); ok {
+				v.check(n.Left, list.Nodes...)
+			}
+		}
+	case *ast.CallNode:
+		if callee, ok := n.Callee.(*ast.IdentifierNode); ok && len(n.Arguments) > 1 &&
+			(callee.Value == "anyOf" || callee.Value == "noneOf") {
+			v.check(n.Arguments[0], n.Arguments[1:]...)
+		}
+	}
+}
+
+// check records the constants that can't be the answer of the question referenced by the node, if any.
+func (v *constantVisitor) check(node ast.Node, constants ...ast.Node) {
+	question := v.q.answerReference(node)
+	if question == nil || question.hasProvidedOptions() {
+		return
+	}
+
+	for _, constant := range constants {
+		value, ok := integerConstant(constant)
+		if !ok || (value >= 0 && value <= len(question.Answers)) || (value == SkipAnswer && question.canBeSkipped()) {
+			continue
+		}
+		v.found = append(v.found, outOfRangeConstant{questionID: question.Id, value: value, options: len(question.Answers)})
+	}
+}
+
+// answerReference returns the choice question whose answer the node reads (answers["q1"] or answers.q1),
+// nil when the node reads something else.
+func (q *questionnaire) answerReference(node ast.Node) *question {
+	member, ok := node.(*ast.MemberNode)
+	if !ok {
+		return nil
+	}
+	identifier, ok := member.Node.(*ast.IdentifierNode)
+	if !ok || identifier.Value != "answers" {
+		return nil
+	}
+	property, ok := member.Property.(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+
+	question := q.findQuestionByID(property.Value)
+	if question == nil || !question.isSingleChoice() {
+		return nil
+	}
+	return question
+}
+
+// integerConstant returns the value of an integer literal, including negative ones.
+func integerConstant(node ast.Node) (int, bool) {
+	switch n := node.(type) {
+	case *ast.IntegerNode:
+		return n.Value, true
+	case *ast.UnaryNode:
+		if value, ok := integerConstant(n.Node); ok && n.Operator == "-" {
+			return -value, true
+		}
+	}
+	return 0, false
+}
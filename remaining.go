@@ -0,0 +1,57 @@
+package go_dynamic_questionnaire
+
+import "fmt"
+
+// Remaining returns the unanswered questions that may still be shown given the answers, in configuration order:
+// the questions Next would return now, and those that later answers may unlock.
+// Questions that can't be shown anymore, because a dependency can't be shown or their condition is already known
+// to be false, are left out, as are hidden questions and, with WithTags, the questions without one of the tags. Nothing remains once the questionnaire is terminated.
+//
+// Like the progress, the set is an upper bound: questions whose condition can't be decided before more questions
+// are answered are included. Answer options are filtered with the current answers.
+func (q *questionnaire) Remaining(answers map[string]int, opts ...NextOption) ([]Question, error) {
+	options := newNextOptions(opts, q.DefaultLocale)
+	q = q.withTags(options.tags)
+	q, err := q.withProvidedOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	q, answers, err = q.fillHiddenAnswers(answers, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer hidden questions: %w", err)
+	}
+	if err := q.validateAnswers(answers, false); err != nil {
+		q.recordValidationErrors(err)
+		return nil, fmt.Errorf("invalid answers provided: %w", err)
+	}
+
+	terminated, err := q.isTerminated(answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate termination: %w", err)
+	}
+	if terminated {
+		return nil, nil
+	}
+
+	var remaining []Question
+	possible := make([]int, len(q.QuestionList))
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		if _, answered := answers[question.Id]; answered || question.Hidden || !q.inScope(question) {
+			continue
+		}
+		stillPossible, err := q.isStillPossible(i, answers, possible)
+		if err != nil {
+			return nil, err
+		}
+		if !stillPossible {
+			continue
+		}
+		result, err := q.toQuestion(*question, answers, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to show question: %w", err)
+		}
+		remaining = append(remaining, result)
+	}
+	return remaining, nil
+}
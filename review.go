@@ -0,0 +1,79 @@
+package go_dynamic_questionnaire
+
+import "fmt"
+
+// ReviewItem is a question answered on the path taken through the questionnaire, with its answer,
+// as returned by Questionnaire.Review to render an editable review page.
+//
+// Example JSON representation:
+//
+//	{
+//	  "question": {"id": "employed", "text": "Are you employed?", "answers": ["Yes", "No"]},
+//	  "answer": 1,
+//	  "answer_text": "Yes"
+//	}
+type ReviewItem struct {
+	Question   Question `json:"question"`              // The question, as Next returns it, to be shown with its answer
+	Answer     Answer   `json:"answer"`                // The answer given, which can be changed and passed back to NextAnswers
+	AnswerText string   `json:"answer_text,omitempty"` // Text of the answer (see SummaryItem), empty when skipped
+}
+
+// Review returns the answered questions on the path taken through the questionnaire, in configuration order,
+// along with their answer (see ReviewItem), so that clients can render an editable review page before completion.
+// Like Summary, it leaves out the stale answers and the hidden questions.
+//
+// The questions are rendered as Next would return them: WithLocale sets the locale of the texts,
+// WithSeed the order of shuffled options and WithContextAnswers answers the hidden questions; other options are ignored.
+func (q *questionnaire) Review(answers map[string]int, opts ...NextOption) ([]ReviewItem, error) {
+	options := newNextOptions(opts, q.DefaultLocale)
+	q, answers, err := q.answeredPath(answers, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []ReviewItem
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		answer, answered := answers[question.Id]
+		if !answered || question.Hidden {
+			continue
+		}
+		sensitive := q.isSensitive(question.Id)
+		if sensitive && q.sensitiveAnswers == StripSensitiveAnswers {
+			continue
+		}
+		rendered, err := q.toQuestion(*question, answers, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to show question: %w", err)
+		}
+		item := ReviewItem{Question: rendered, Answer: q.givenAnswer(question, answer)}
+		switch {
+		case sensitive:
+			item.Answer, item.AnswerText = Answer{}, RedactedAnswer
+		case answer != SkipAnswer:
+			item.AnswerText = q.formatAnswer(question, answers, options.locale)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// givenAnswer returns the answer given to the question: the answer choice, or the value for the questions
+// that aren't single choice, unknown (the zero Answer) when their value wasn't given through NextAnswers.
+func (q *questionnaire) givenAnswer(question *question, answer int) Answer {
+	if answer == SkipAnswer {
+		return Skipped()
+	}
+	if question.isSingleChoice() {
+		return Choice(answer)
+	}
+	switch value := q.values[question.Id].(type) {
+	case []int:
+		return Choices(value...)
+	case string:
+		return Text(value)
+	case float64:
+		return Number(value)
+	}
+	return Answer{}
+}
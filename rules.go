@@ -0,0 +1,71 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+)
+
+// consistencyRule is an expression across several answers that must hold for the answers to be accepted:
+//
+//	rules:
+//	  - id: "years_order"
+//	    condition: 'answers["end_year"] >= answers["start_year"]'
+//	    message: "The end year can't be before the start year."
+//
+// Rules are evaluated by Next once every question they reference is answered (and not skipped),
+// and answers violating a rule are rejected with a RuleViolationErrType error.
+type consistencyRule struct {
+	Id        string        `yaml:"id" json:"id"`                               // Unique identifier for the rule
+	Condition string        `yaml:"condition" json:"condition"`                 // Expression that must hold
+	Message   localizedText `yaml:"message,omitempty" json:"message,omitempty"` // Optional message explaining the violation to users
+}
+
+// validateRules checks that every rule has a unique ID and a condition.
+// Conditions are compiled along with the other conditions of the questionnaire.
+func (q *questionnaire) validateRules() error {
+	var errs []error
+	ids := make(map[string]bool, len(q.Rules))
+	for _, rule := range q.Rules {
+		switch {
+		case rule.Id == "":
+			errs = append(errs, invalidConditionError("rule_id", rule.Id, rule.Condition, errors.New("rule has no ID")))
+		case ids[rule.Id]:
+			errs = append(errs, invalidConditionError("rule_id", rule.Id, rule.Condition, errors.New("rule ID is used more than once")))
+		case rule.Condition == "":
+			errs = append(errs, invalidConditionError("rule_id", rule.Id, rule.Condition, errors.New("rule has no condition")))
+		}
+		ids[rule.Id] = true
+	}
+	return errors.Join(errs...)
+}
+
+// checkRules evaluates the rules whose referenced questions are all answered,
+// and returns a RuleViolationErrType error for each rule that doesn't hold.
+// Rule messages are returned in the requested locale.
+func (q *questionnaire) checkRules(answers map[string]int, options *nextOptions) error {
+	var errs []error
+	for _, rule := range q.Rules {
+		if !q.ruleApplies(rule, answers) {
+			continue
+		}
+		satisfied, err := q.evaluateCondition(rule.Condition, answers)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate rule '%s': %w", rule.Id, err)
+		}
+		if !satisfied {
+			errs = append(errs, ruleViolationError(&rule, options.translate(rule.Message)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ruleApplies reports whether every question referenced by the rule, including through computed values,
+// is answered and not skipped. Rules referencing no question (e.g. using the score) always apply.
+func (q *questionnaire) ruleApplies(rule consistencyRule, answers map[string]int) bool {
+	for _, id := range extractReferences(rule.Condition, q.Computed) {
+		if answer, answered := answers[id]; !answered || answer == SkipAnswer {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnswersSchema describes the answers accepted by NextAnswers as a JSON Schema object.
+// It has one property per question, titled with the question text in the default locale:
+// an integer restricted to the answer choices for choice questions (and SkipAnswer when the question
+// can be skipped), an array of answer choices for multiple_choice questions, a string for text questions
+// and a number for number questions. Since choice questions take integers, the schema also describes
+// the answers of Next for questionnaires only made of choice questions.
+func (q *questionnaire) AnswersSchema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(q.QuestionList))
+	for _, question := range q.QuestionList {
+		schema := map[string]interface{}{
+			"title": question.Text.resolve(q.DefaultLocale, q.DefaultLocale),
+		}
+
+		switch question.kind() {
+		case ChoiceQuestion, ConsentQuestion:
+			values := answerValues(question)
+			schema["type"] = "integer"
+			schema["enum"] = values
+			schema["description"] = q.describeChoices(question, values)
+		case MultipleChoiceQuestion:
+			values := answerValues(question)
+			if question.canBeSkipped() {
+				values = values[:len(values)-1]
+			}
+			schema["type"] = "array"
+			schema["items"] = map[string]interface{}{"type": "integer", "enum": values}
+			schema["uniqueItems"] = true
+			if question.MinChoices > 0 {
+				schema["minItems"] = question.MinChoices
+			}
+			if question.MaxChoices > 0 {
+				schema["maxItems"] = question.MaxChoices
+			}
+			schema["description"] = q.describeChoices(question, values)
+		case TextQuestion:
+			schema["type"] = "string"
+		case NumberQuestion:
+			schema["type"] = "number"
+			if question.Min != nil {
+				schema["minimum"] = *question.Min
+			}
+			if question.Max != nil {
+				schema["maximum"] = *question.Max
+			}
+		case NPSQuestion:
+			schema["type"] = "integer"
+			schema["minimum"] = 0
+			schema["maximum"] = 10
+		}
+		if question.hasProvidedOptions() {
+			// The options are only known once supplied by the DataProvider
+			delete(schema, "enum")
+			if question.kind() == MultipleChoiceQuestion {
+				schema["items"] = map[string]interface{}{"type": "integer"}
+			}
+			schema["description"] = fmt.Sprintf("options supplied from source '%s'", question.OptionsFrom)
+		}
+		if question.canBeSkipped() && !question.isSingleChoice() {
+			schema["nullable"] = true
+		}
+		properties[question.Id] = schema
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// describeChoices lists the answer choices with their text in the default locale, e.g. "1 = Yes, 2 = No".
+func (q *questionnaire) describeChoices(question question, values []int) string {
+	choices := make([]string, 0, len(values))
+	for _, value := range values {
+		label := "skipped"
+		if value != SkipAnswer {
+			label = question.Answers[value-1].Text.resolve(q.DefaultLocale, q.DefaultLocale)
+		}
+		choices = append(choices, fmt.Sprintf("%d = %s", value, label))
+	}
+	return strings.Join(choices, ", ")
+}
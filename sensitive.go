@@ -0,0 +1,108 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RedactedAnswer replaces the text of the sensitive answers in summaries and reviews (see RedactSensitiveAnswers).
+const RedactedAnswer = "[REDACTED]"
+
+// SensitiveAnswerPolicy tells what to do with the answers to the questions marked as sensitive,
+// such as personal data, outside of the responses of Next (see WithSensitiveAnswers).
+type SensitiveAnswerPolicy int
+
+const (
+	// KeepSensitiveAnswers handles sensitive answers as any other answer. This is the default.
+	KeepSensitiveAnswers SensitiveAnswerPolicy = iota
+
+	// RedactSensitiveAnswers replaces the text of sensitive answers with RedactedAnswer in summaries and reviews,
+	// and hides the result of the conditions referencing sensitive questions in the audit trail.
+	// Sensitive answers are left out of the completion hook and the audit trail, whose maps and hashes can't be masked.
+	RedactSensitiveAnswers
+
+	// StripSensitiveAnswers leaves sensitive answers out of summaries, reviews, the completion hook and the audit trail,
+	// along with the conditions referencing sensitive questions, as if the questions didn't exist.
+	StripSensitiveAnswers
+)
+
+// isSensitive reports whether the question is marked as sensitive and its answers are redacted or stripped.
+func (q *questionnaire) isSensitive(questionID string) bool {
+	if q.sensitiveAnswers == KeepSensitiveAnswers {
+		return false
+	}
+	question := q.findQuestionByID(questionID)
+	return question != nil && question.Sensitive
+}
+
+// referencesSensitive reports whether the condition references a question whose answers are redacted or stripped.
+func (q *questionnaire) referencesSensitive(condition string) bool {
+	for _, id := range extractQuestionIDs(condition) {
+		if q.isSensitive(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutSensitive returns a copy of the map, by question ID, without the answers to sensitive questions,
+// or the map itself when sensitive answers are kept.
+func withoutSensitive[V any](q *questionnaire, m map[string]V) map[string]V {
+	if q.sensitiveAnswers == KeepSensitiveAnswers || m == nil {
+		return m
+	}
+	m = maps.Clone(m)
+	maps.DeleteFunc(m, func(id string, _ V) bool {
+		return q.isSensitive(id)
+	})
+	return m
+}
+
+// redactEvaluations hides the result of the evaluations of conditions referencing sensitive questions,
+// or leaves them out when sensitive answers are stripped.
+func (q *questionnaire) redactEvaluations(evaluations []ConditionEvaluation) []ConditionEvaluation {
+	if q.sensitiveAnswers == KeepSensitiveAnswers {
+		return evaluations
+	}
+	redacted := make([]ConditionEvaluation, 0, len(evaluations))
+	for _, evaluation := range evaluations {
+		if q.referencesSensitive(evaluation.Condition) {
+			if q.sensitiveAnswers == StripSensitiveAnswers {
+				continue
+			}
+			evaluation = ConditionEvaluation{Condition: evaluation.Condition, Redacted: true}
+		}
+		redacted = append(redacted, evaluation)
+	}
+	return redacted
+}
+
+// redactError returns the message of an error of Next for the audit trail. The messages of validation errors
+// concerning sensitive questions may quote the answers (e.g. out of range answers), so they are replaced
+// with RedactedAnswer, only the error types being kept.
+func (q *questionnaire) redactError(err error) string {
+	if q.sensitiveAnswers == KeepSensitiveAnswers {
+		return err.Error()
+	}
+	validationErrs := ValidationErrors(err)
+	if !slices.ContainsFunc(validationErrs, q.concernsSensitive) {
+		return err.Error()
+	}
+	messages := make([]string, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		if q.concernsSensitive(validationErr) {
+			messages[i] = fmt.Sprintf("validation error (%s): %s", validationErr.Type, RedactedAnswer)
+		} else {
+			messages[i] = validationErr.Error()
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+// concernsSensitive reports whether the validation error is about a question whose answers are redacted or stripped.
+func (q *questionnaire) concernsSensitive(err ValidationError) bool {
+	id, ok := err.Context["question_id"].(string)
+	return ok && q.isSensitive(id)
+}
@@ -0,0 +1,28 @@
+package go_dynamic_questionnaire
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+)
+
+// signature holds the detached signature checked before the configuration is parsed (see WithSignature).
+type signature struct {
+	publicKey ed25519.PublicKey // Key of the trusted author of the configuration
+	value     []byte            // Ed25519 signature of the configuration content
+}
+
+// verifySignature checks that the configuration content is signed by the trusted key (see WithSignature),
+// or does nothing when no signature is expected.
+func (q *questionnaire) verifySignature(content []byte) error {
+	if q.signature == nil {
+		return nil
+	}
+	if len(q.signature.publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid signature: public key must be %d bytes, got %d", ed25519.PublicKeySize, len(q.signature.publicKey))
+	}
+	if !ed25519.Verify(q.signature.publicKey, content, q.signature.value) {
+		return errors.New("invalid signature: the configuration isn't signed by the trusted key")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Revision is the result of changing an answer with Questionnaire.Revise.
+//
+// Example JSON representation:
+//
+//	{
+//	  "answers": {"employed": 2, "hobbies": 1},
+//	  "invalidated": ["company_size", "remote"]
+//	}
+type Revision struct {
+	Answers     map[string]int `json:"answers"`               // The answers with the changed answer, without the invalidated answers
+	Invalidated []string       `json:"invalidated,omitempty"` // The questions whose answer was invalidated by the change, in configuration order
+}
+
+// StaleAnswerPolicy tells Next what to do with stale answers (see WithStaleAnswers).
+type StaleAnswerPolicy int
+
+const (
+	// KeepStaleAnswers keeps stale answers as any other answer, without detecting them. This is the default.
+	KeepStaleAnswers StaleAnswerPolicy = iota
+
+	// FlagStaleAnswers reports stale answers in Response.Stale, and otherwise keeps them.
+	FlagStaleAnswers
+
+	// StripStaleAnswers leaves stale answers out, as if they were never given, and reports them in Response.Stale.
+	StripStaleAnswers
+)
+
+// Revise changes the answer of a question and leaves out the answers it invalidates (see WithStaleAnswers).
+// The provided answers map is not modified.
+func (q *questionnaire) Revise(answers map[string]int, questionID string, answer int) (*Revision, error) {
+	revised := maps.Clone(answers)
+	if revised == nil {
+		revised = make(map[string]int, 1)
+	}
+	revised[questionID] = answer
+	if err := q.validateSingleAnswer(questionID, answer, revised); err != nil {
+		q.recordValidationErrors(err)
+		return nil, fmt.Errorf("invalid revised answer: %w", err)
+	}
+
+	invalidated, err := q.staleAnswers(revised)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect invalidated answers: %w", err)
+	}
+	for _, id := range invalidated {
+		delete(revised, id)
+	}
+	return &Revision{Answers: revised, Invalidated: invalidated}, nil
+}
+
+// staleAnswers returns, in configuration order, the answered questions that wouldn't be shown given the other answers:
+// a dependency isn't answered or is stale itself, the condition doesn't hold, or the chosen option isn't available anymore.
+// This happens when an earlier answer is changed after the question was answered.
+//
+// Stale answers are left out one after the other until the remaining answers are consistent,
+// so that the questions only shown because of a stale answer are stale too.
+// Conditions failing to evaluate don't make answers stale: Next reports the error.
+func (q *questionnaire) staleAnswers(answers map[string]int) ([]string, error) {
+	var stale []string
+	kept := answers
+	for round, changed := 1, true; changed; round++ {
+		if err := q.checkEvaluationDepth(round, "stale answers"); err != nil {
+			return nil, err
+		}
+		changed = false
+		for i := range q.QuestionList {
+			question := &q.QuestionList[i]
+			if _, answered := kept[question.Id]; !answered || q.isOnPath(question, kept) {
+				continue
+			}
+			if len(stale) == 0 {
+				kept = maps.Clone(answers)
+			}
+			delete(kept, question.Id)
+			stale = append(stale, question.Id)
+			changed = true
+		}
+	}
+
+	if len(stale) > 1 {
+		slices.SortFunc(stale, func(a, b string) int { return q.questionIndex[a] - q.questionIndex[b] })
+	}
+	return stale, nil
+}
+
+// isOnPath reports whether the answered question would be shown given the other answers,
+// and whether its chosen options are available.
+func (q *questionnaire) isOnPath(question *question, answers map[string]int) bool {
+	others := maps.Clone(answers)
+	delete(others, question.Id)
+
+	if !q.areDependenciesSatisfied(*question, others) {
+		return false
+	}
+	if question.Condition != "" {
+		if show, err := q.evaluateCondition(question.Condition, others); err == nil && !show {
+			return false
+		}
+	}
+	for _, choice := range q.selectedChoices(question, answers) {
+		if available, err := q.isAnswerAvailable(question.Answers[choice-1], answers); err == nil && !available {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutAnswers returns the answers without the answers to the questions.
+// When the answers include values (see NextAnswers), the returned questionnaire is a copy holding the remaining values,
+// so that the questionnaire itself is never modified.
+func (q *questionnaire) withoutAnswers(answers map[string]int, questionIDs []string) (*questionnaire, map[string]int) {
+	answers = maps.Clone(answers)
+	for _, id := range questionIDs {
+		delete(answers, id)
+	}
+	if q.values == nil {
+		return q, answers
+	}
+
+	scoped := *q
+	scoped.values = maps.Clone(q.values)
+	for _, id := range questionIDs {
+		delete(scoped.values, id)
+	}
+	return &scoped, answers
+}
@@ -0,0 +1,90 @@
+package go_dynamic_questionnaire
+
+// Stats measures the complexity of a questionnaire (see Questionnaire.Stats), to help reviewers judge
+// whether a questionnaire is getting too intricate to maintain.
+//
+// Example JSON representation:
+//
+//	{
+//	  "questions": 6,
+//	  "questions_by_type": {"choice": 5, "consent": 1},
+//	  "conditional_questions": 4,
+//	  "answers": 14,
+//	  "closing_remarks": 2,
+//	  "depth": 3,
+//	  "branch_factor": 2,
+//	  "outcomes": 5,
+//	  "terminated_outcomes": 1,
+//	  "longest_path": 4
+//	}
+type Stats struct {
+	Questions            int            `json:"questions"`                  // Questions after repeating groups are expanded, hidden ones included
+	QuestionsByType      map[string]int `json:"questions_by_type"`          // Number of questions of each type
+	HiddenQuestions      int            `json:"hidden_questions,omitempty"` // Questions never displayed, answered from the context or their value
+	ConditionalQuestions int            `json:"conditional_questions"`      // Questions shown only when their condition is true
+	Answers              int            `json:"answers"`                    // Answer options of every question, options supplied by a data provider excluded
+	ClosingRemarks       int            `json:"closing_remarks"`            // Closing remarks of the questionnaire
+	Depth                int            `json:"depth"`                      // Length of the longest dependency chain, 1 when no question depends on another
+	BranchFactor         float64        `json:"branch_factor"`              // Average number of questions depending on a question, among the questions others depend on
+	Outcomes             int            `json:"outcomes"`                   // Answer paths completing the questionnaire, answers that change nothing to the flow merged
+	TerminatedOutcomes   int            `json:"terminated_outcomes"`        // Outcomes ending the questionnaire early
+	LongestPath          int            `json:"longest_path"`               // Most questions answered on a path completing the questionnaire
+	Truncated            bool           `json:"truncated,omitempty"`        // Whether the paths couldn't all be explored (see Analyze), outcomes and longest path then being lower bounds
+}
+
+// Stats measures the complexity of the questionnaire. Outcomes and paths are counted
+// by exploring the answer paths, the way Analyze does.
+func (q *questionnaire) Stats() Stats {
+	stats := Stats{
+		Questions:       len(q.QuestionList),
+		QuestionsByType: make(map[string]int),
+		ClosingRemarks:  len(q.Remarks),
+	}
+
+	dependents := make(map[string]int)
+	depths := make(map[string]int)
+	for _, question := range q.QuestionList {
+		stats.QuestionsByType[question.kind()]++
+		stats.Answers += len(question.Answers)
+		if question.Hidden {
+			stats.HiddenQuestions++
+		}
+		if question.Condition != "" {
+			stats.ConditionalQuestions++
+		}
+		for _, depID := range question.DependsOn {
+			dependents[depID]++
+		}
+		stats.Depth = max(stats.Depth, q.dependencyDepth(question.Id, depths))
+	}
+	if len(dependents) > 0 {
+		total := 0
+		for _, count := range dependents {
+			total += count
+		}
+		stats.BranchFactor = float64(total) / float64(len(dependents))
+	}
+
+	explorer := q.explorePaths()
+	stats.Outcomes = explorer.outcomes
+	stats.TerminatedOutcomes = explorer.ended
+	stats.LongestPath = explorer.longest
+	stats.Truncated = explorer.truncation != ""
+	return stats
+}
+
+// dependencyDepth returns the length of the longest dependency chain ending with the question,
+// memoized in depths. Dependencies are acyclic, as checked when the questionnaire is created.
+func (q *questionnaire) dependencyDepth(questionID string, depths map[string]int) int {
+	if depth, ok := depths[questionID]; ok {
+		return depth
+	}
+	depth := 1
+	if question := q.findQuestionByID(questionID); question != nil {
+		for _, depID := range question.DependsOn {
+			depth = max(depth, q.dependencyDepth(depID, depths)+1)
+		}
+	}
+	depths[questionID] = depth
+	return depth
+}
@@ -0,0 +1,97 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions caps the number of question IDs suggested for an unknown question ID.
+const maxSuggestions = 3
+
+// withSuggestedQuestionIDs adds the question IDs closest to the unknown question ID to the validation error,
+// to help spotting typos: they are listed in the suggestions context key and appended to the message
+// (e.g. "question does not exist (did you mean 'plan'?)"). Errors are returned unchanged when nothing is close enough.
+func (q *questionnaire) withSuggestedQuestionIDs(err error, questionID string) error {
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	suggestions := q.suggestQuestionIDs(questionID)
+	if len(suggestions) == 0 {
+		return err
+	}
+
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = "'" + suggestion + "'"
+	}
+	validationErr.Message += " (did you mean " + strings.Join(quoted, ", ") + "?)"
+	context := make(map[string]interface{}, len(validationErr.Context)+1)
+	for key, value := range validationErr.Context {
+		context[key] = value
+	}
+	context["suggestions"] = suggestions
+	validationErr.Context = context
+	return validationErr
+}
+
+// suggestQuestionIDs returns the question IDs closest to the unknown question ID, closest first:
+// the ones within an edit distance of a third of its length (see editDistance), at most maxSuggestions.
+// Short IDs get no suggestion: with IDs such as q1 and q2, any of them would be one edit away.
+func (q *questionnaire) suggestQuestionIDs(questionID string) []string {
+	type candidate struct {
+		id       string
+		distance int
+	}
+
+	threshold := len(questionID) / 3
+	if threshold == 0 {
+		return nil
+	}
+	var candidates []candidate
+	for _, question := range q.QuestionList {
+		if question.Id == "" || question.Id == questionID {
+			continue
+		}
+		if distance := editDistance(questionID, question.Id); distance <= threshold {
+			candidates = append(candidates, candidate{question.Id, distance})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.distance - b.distance })
+
+	var suggestions []string
+	for _, c := range candidates {
+		if !contains(suggestions, c.id) && len(suggestions) < maxSuggestions {
+			suggestions = append(suggestions, c.id)
+		}
+	}
+	return suggestions
+}
+
+// editDistance returns the edit distance between a and b (optimal string alignment distance):
+// the minimum number of single-byte insertions, deletions, substitutions and transpositions of adjacent bytes
+// turning a into b.
+func editDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
@@ -0,0 +1,77 @@
+package go_dynamic_questionnaire
+
+import "fmt"
+
+// SummaryItem is a question answered on the path taken through the questionnaire,
+// as returned by Questionnaire.Summary.
+//
+// Example JSON representation:
+//
+//	{"question_id": "employed", "question": "Are you employed?", "answer": "Yes"}
+type SummaryItem struct {
+	QuestionID string `json:"question_id"`       // ID of the question
+	Question   string `json:"question"`          // Text of the question, in the requested locale
+	Answer     string `json:"answer"`            // Text of the answer: the chosen options (comma-separated), the text or the number
+	Skipped    bool   `json:"skipped,omitempty"` // Whether the question was skipped, in which case the answer is empty
+}
+
+// Summary returns the answered questions on the path taken through the questionnaire, in configuration order,
+// with the texts of their answers (see SummaryItem).
+// Answers to questions that wouldn't be shown anymore given the other answers (see WithStaleAnswers) are left out,
+// as are hidden questions, which respondents never see.
+//
+// WithLocale sets the locale of the texts and WithContextAnswers answers the hidden questions
+// the path depends on; other options are ignored.
+func (q *questionnaire) Summary(answers map[string]int, opts ...NextOption) ([]SummaryItem, error) {
+	options := newNextOptions(opts, q.DefaultLocale)
+	q, answers, err := q.answeredPath(answers, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []SummaryItem
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+		answer, answered := answers[question.Id]
+		if !answered || question.Hidden {
+			continue
+		}
+		sensitive := q.isSensitive(question.Id)
+		if sensitive && q.sensitiveAnswers == StripSensitiveAnswers {
+			continue
+		}
+		item := SummaryItem{QuestionID: question.Id, Question: options.translate(question.Text), Skipped: answer == SkipAnswer}
+		switch {
+		case sensitive:
+			item.Answer, item.Skipped = RedactedAnswer, false
+		case !item.Skipped:
+			item.Answer = q.formatAnswer(question, answers, options.locale)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// answeredPath returns the answers on the path taken through the questionnaire: the answers are validated,
+// the hidden questions answered and the stale answers left out. The returned questionnaire is the copy
+// holding the values of these answers.
+func (q *questionnaire) answeredPath(answers map[string]int, options *nextOptions) (*questionnaire, map[string]int, error) {
+	q, err := q.withProvidedOptions(options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	q, answers, err = q.fillHiddenAnswers(answers, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to answer hidden questions: %w", err)
+	}
+	if err := q.validateAnswers(answers, false); err != nil {
+		q.recordValidationErrors(err)
+		return nil, nil, fmt.Errorf("invalid answers provided: %w", err)
+	}
+	stale, err := q.staleAnswers(answers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to detect stale answers: %w", err)
+	}
+	q, answers = q.withoutAnswers(answers, stale)
+	return q, answers, nil
+}
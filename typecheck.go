@@ -0,0 +1,134 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// typedEnv builds the environment used to type-check expressions when the questionnaire is created:
+// values holds a field for every question that isn't single choice, typed after the question type
+// ([]int for multiple_choice, string for text, float64 for number and nps), and computed holds a field
+// for every computed value, typed after its expression when expr can infer it.
+//
+// Indexing a field that doesn't exist or using a field with the wrong type (e.g. values["seats"] == "ten")
+// then fails to compile, while the maps of the runtime environment (see builtinEnv) would accept it.
+func (q *questionnaire) typedEnv() map[string]interface{} {
+	var fields []reflect.StructField
+	for _, question := range q.QuestionList {
+		var kind reflect.Type
+		switch question.kind() {
+		case MultipleChoiceQuestion:
+			kind = reflect.TypeFor[[]int]()
+		case TextQuestion:
+			kind = reflect.TypeFor[string]()
+		case NumberQuestion, NPSQuestion:
+			kind = reflect.TypeFor[float64]()
+		default:
+			continue
+		}
+		fields = append(fields, typedField(len(fields), question.Id, kind))
+	}
+	env := map[string]interface{}{"values": reflect.New(reflect.StructOf(fields)).Elem().Interface()}
+
+	// Computed values can't reference other computed values, so their types only depend on values
+	fields = nil
+	for _, name := range slices.Sorted(maps.Keys(q.Computed)) {
+		kind := reflect.TypeFor[interface{}]()
+		checkEnv := q.conditionEnv(nil)
+		checkEnv["values"] = env["values"]
+		if program, err := expr.Compile(q.Computed[name], q.compileOptions(checkEnv, false)...); err == nil {
+			if inferred := program.Node().Type(); inferred != nil {
+				kind = inferred
+			}
+		}
+		fields = append(fields, typedField(len(fields), name, kind))
+	}
+	env["computed"] = reflect.New(reflect.StructOf(fields)).Elem().Interface()
+	return env
+}
+
+// typedField returns the struct field exposing the key with the type to expressions.
+func typedField(position int, key string, kind reflect.Type) reflect.StructField {
+	return reflect.StructField{
+		Name: "F" + strconv.Itoa(position),
+		Type: kind,
+		Tag:  reflect.StructTag("expr:" + strconv.Quote(key)),
+	}
+}
+
+// typeCheck compiles the expression against the typed environment (see typedEnv),
+// so that the answers of the questions that aren't single choice and the computed values are type-checked.
+//
+// An expression using values or computed other than by indexing them with a constant key (e.g. len(values))
+// is checked against the untyped map. So is an expression indexing an unknown key, unless in strict validation mode,
+// where the unknown key is reported.
+func (q *questionnaire) typeCheck(expression, ownerKey, ownerID string, isCondition bool) error {
+	env := q.conditionEnv(nil)
+	typed := false
+	for _, name := range []string{"values", "computed"} {
+		if !referencesIdentifier(expression, name) || usesWholeMap(expression, name) {
+			continue
+		}
+		if key, known := q.unknownKey(expression, name); !known {
+			if !q.strictValidation {
+				continue
+			}
+			switch {
+			case name == "computed":
+				err := fmt.Errorf("computed value '%s' does not exist", key)
+				return invalidConditionError(ownerKey, ownerID, expression, err)
+			case q.findQuestionByID(key) == nil:
+				return q.withSuggestedQuestionIDs(unknownQuestionReferenceError(ownerKey, ownerID, expression, key), key)
+			default:
+				err := fmt.Errorf("question '%s' is single choice: its answer is in answers, not in values", key)
+				return invalidConditionError(ownerKey, ownerID, expression, err)
+			}
+		}
+		env[name] = q.typedEnvironment[name]
+		typed = true
+	}
+	if !typed {
+		return nil
+	}
+
+	if _, err := expr.Compile(expression, q.compileOptions(env, isCondition)...); err != nil {
+		return invalidConditionError(ownerKey, ownerID, expression, err)
+	}
+	return nil
+}
+
+// unknownKey returns the first key indexing the map that has no field in the typed environment,
+// and false, or true when every key is known.
+func (q *questionnaire) unknownKey(expression, name string) (string, bool) {
+	fields := reflect.TypeOf(q.typedEnvironment[name])
+	for _, key := range indexedKeys(expression, name) {
+		known := false
+		for i := range fields.NumField() {
+			if fields.Field(i).Tag.Get("expr") == key {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return key, false
+		}
+	}
+	return "", true
+}
+
+// indexedKeys returns the constant keys used to index the map in the expression (e.g. "bmi" for computed["bmi"]).
+func indexedKeys(expression, name string) []string {
+	var keys []string
+	for i := 0; i < len(expression); i++ {
+		if strings.HasPrefix(expression[i:], name+"[") && (i == 0 || !isIdentifierChar(expression[i-1])) {
+			keys = appendQuotedStrings(keys, expression, i+len(name)+1, "]")
+		}
+	}
+	return keys
+}
@@ -1,5 +1,7 @@
 package go_dynamic_questionnaire
 
+import "hash/fnv"
+
 // contains checks if a slice contains a specific string.
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -9,3 +11,37 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// hashString returns a 64-bit FNV-1a hash of the string.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// isIdentifierChar reports whether the character can be part of an expression identifier.
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isIdentifier reports whether the name is a valid identifier:
+// letters, digits and underscores, not starting with a digit.
+func isIdentifier(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipSpaces returns the position of the first non-space character of s at or after start.
+func skipSpaces(s string, start int) int {
+	for start < len(s) && (s[start] == ' ' || s[start] == '\t' || s[start] == '\n' || s[start] == '\r') {
+		start++
+	}
+	return start
+}
@@ -55,4 +55,36 @@ var _ = Describe("Util", func() {
 			})
 		})
 	})
+
+	Describe("hashString", func() {
+		It("should be deterministic", func() {
+			Expect(hashString("q1")).To(Equal(hashString("q1")))
+		})
+
+		It("should differ for different strings", func() {
+			Expect(hashString("q1")).ToNot(Equal(hashString("q2")))
+		})
+	})
+
+	Describe("isIdentifierChar", func() {
+		It("should accept letters, digits and underscores", func() {
+			for _, c := range []byte("azAZ09_") {
+				Expect(isIdentifierChar(c)).To(BeTrue())
+			}
+		})
+
+		It("should reject other characters", func() {
+			for _, c := range []byte(` "'[(.-`) {
+				Expect(isIdentifierChar(c)).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("skipSpaces", func() {
+		It("should return the position of the next non-space character", func() {
+			Expect(skipSpaces("a  \t\nb", 1)).To(Equal(5))
+			Expect(skipSpaces("ab", 1)).To(Equal(1))
+			Expect(skipSpaces("a  ", 1)).To(Equal(3))
+		})
+	})
 })
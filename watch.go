@@ -0,0 +1,118 @@
+package go_dynamic_questionnaire
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// KVStore reads and watches the keys of a key-value store such as Consul or etcd,
+// so that a questionnaire stored under a key is updated without restarting (see WatchKV).
+//
+// It adapts the client of the store, which the package doesn't depend on:
+//
+//	type etcdStore struct{ client *clientv3.Client }
+//
+//	func (s etcdStore) Get(ctx context.Context, key string) ([]byte, error) {
+//	    resp, err := s.client.Get(ctx, key)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    if len(resp.Kvs) == 0 {
+//	        return nil, fmt.Errorf("key %q not found", key)
+//	    }
+//	    return resp.Kvs[0].Value, nil
+//	}
+//
+//	func (s etcdStore) Watch(ctx context.Context, key string, onChange func([]byte)) error {
+//	    for resp := range s.client.Watch(ctx, key) {
+//	        for _, event := range resp.Events {
+//	            if event.Type == clientv3.EventTypePut {
+//	                onChange(event.Kv.Value)
+//	            }
+//	        }
+//	    }
+//	    return ctx.Err()
+//	}
+type KVStore interface {
+	// Get returns the value of the key.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Watch calls onChange with the new value of the key every time it changes, until the context is canceled
+	// or the watch fails. It blocks meanwhile, and returns why the watch stopped.
+	Watch(ctx context.Context, key string, onChange func(value []byte)) error
+}
+
+// Watcher holds the questionnaire stored under a key of a KVStore, replaced every time the key changes (see WatchKV).
+// It is safe for concurrent use.
+type Watcher struct {
+	current atomic.Value // Questionnaire built from the latest valid value of the key
+
+	mu  sync.Mutex
+	err error
+}
+
+// WatchKV creates the questionnaire stored under the key of the store, then watches the key until the context is canceled:
+// every new value is loaded with the options, as with New, and atomically replaces the questionnaire,
+// enabling zero-downtime updates in clustered deployments:
+//
+//	watcher, err := gdq.WatchKV(ctx, etcdStore{client}, "surveys/onboarding", gdq.WithStrictValidation())
+//	if err != nil {
+//	    return err
+//	}
+//
+//	// In every request
+//	response, err := watcher.Questionnaire().Next(answers)
+//
+// A value that fails to load leaves the current questionnaire in place and is reported by Err.
+// WatchKV only fails when the first value can't be read or loaded.
+func WatchKV(ctx context.Context, store KVStore, key string, opts ...Option) (*Watcher, error) {
+	value, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %q: %w", key, err)
+	}
+	q, err := New(value, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{}
+	w.current.Store(q)
+	go func() {
+		err := store.Watch(ctx, key, func(value []byte) {
+			updated, err := New(value, opts...)
+			if err != nil {
+				w.setErr(fmt.Errorf("failed to update questionnaire from key %q: %w", key, err))
+				return
+			}
+			w.current.Store(updated)
+			w.setErr(nil)
+		})
+		if ctx.Err() == nil {
+			w.setErr(fmt.Errorf("stopped watching key %q: %w", key, err))
+		}
+	}()
+	return w, nil
+}
+
+// Questionnaire returns the questionnaire built from the latest valid value of the key.
+// Callers should get it for every use rather than keep it, so that they see the updates.
+func (w *Watcher) Questionnaire() Questionnaire {
+	return w.current.Load().(Questionnaire)
+}
+
+// Err returns why the latest value of the key couldn't replace the questionnaire, or why the watch stopped,
+// and nil when the questionnaire is up to date.
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// setErr records the outcome of the latest update.
+func (w *Watcher) setErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+}
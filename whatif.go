@@ -0,0 +1,100 @@
+package go_dynamic_questionnaire
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// WhatIfResult is the result of evaluating hypothetical answers with Questionnaire.WhatIf.
+//
+// Example JSON representation:
+//
+//	{
+//	  "response": {"questions": [{"id": "company_size", ...}], "completed": false, ...},
+//	  "shown": ["company_size"],
+//	  "hidden": ["hobbies"]
+//	}
+type WhatIfResult struct {
+	Response      *Response `json:"response"`                 // The response Next would return with the hypothetical answers
+	Shown         []string  `json:"shown,omitempty"`          // Questions that would be returned, and aren't returned with the current answers
+	Hidden        []string  `json:"hidden,omitempty"`         // Questions returned with the current answers that wouldn't be returned anymore, other than the hypothetically answered ones
+	ShownRemarks  []string  `json:"shown_remarks,omitempty"`  // Closing remarks that would be shown, and aren't shown with the current answers
+	HiddenRemarks []string  `json:"hidden_remarks,omitempty"` // Closing remarks shown with the current answers that wouldn't be shown anymore
+}
+
+// WhatIf evaluates the questionnaire with the hypothetical answers added to the answers, without committing them:
+// neither the completion hook, the metrics nor the audit sink are notified. The hypothetical answers override the answers to the same questions.
+// The options are applied to both evaluations; the provided maps are not modified.
+func (q *questionnaire) WhatIf(answers map[string]int, hypothetical map[string]int, opts ...NextOption) (*WhatIfResult, error) {
+	// The evaluations are previews: they are run on a copy of the questionnaire that doesn't notify anyone
+	// The answer options supplied by the DataProvider are requested once for both evaluations
+	provided, err := q.withProvidedOptions(newNextOptions(opts, q.DefaultLocale))
+	if err != nil {
+		return nil, fmt.Errorf("failed to provide answer options: %w", err)
+	}
+	scoped := *provided
+	scoped.metrics = nil
+	scoped.completionHook = nil
+	scoped.auditSink = nil
+
+	current, err := scoped.Next(answers, opts...)
+	if err != nil {
+		return nil, err
+	}
+	merged := maps.Clone(answers)
+	if merged == nil {
+		merged = make(map[string]int, len(hypothetical))
+	}
+	maps.Copy(merged, hypothetical)
+	response, err := scoped.Next(merged, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hypothetical answers: %w", err)
+	}
+
+	result := &WhatIfResult{Response: response}
+	currentIDs := questionIDs(current.Questions)
+	hypotheticalIDs := questionIDs(response.Questions)
+	for _, id := range hypotheticalIDs {
+		if !slices.Contains(currentIDs, id) {
+			result.Shown = append(result.Shown, id)
+		}
+	}
+	for _, id := range currentIDs {
+		if _, answered := hypothetical[id]; !answered && !slices.Contains(hypotheticalIDs, id) {
+			result.Hidden = append(result.Hidden, id)
+		}
+	}
+
+	currentRemarks := remarkIDs(current.ClosingRemarks)
+	hypotheticalRemarks := remarkIDs(response.ClosingRemarks)
+	for _, id := range hypotheticalRemarks {
+		if !slices.Contains(currentRemarks, id) {
+			result.ShownRemarks = append(result.ShownRemarks, id)
+		}
+	}
+	for _, id := range currentRemarks {
+		if !slices.Contains(hypotheticalRemarks, id) {
+			result.HiddenRemarks = append(result.HiddenRemarks, id)
+		}
+	}
+	return result, nil
+}
+
+// questionIDs returns the IDs of the questions, in order.
+func questionIDs(questions []Question) []string {
+	ids := make([]string, len(questions))
+	for i, question := range questions {
+		ids[i] = question.Id
+	}
+	return ids
+}
+
+// remarkIDs returns the IDs of the closing remarks, in order.
+func remarkIDs(remarks []ClosingRemark) []string {
+	ids := make([]string, len(remarks))
+	for i, remark := range remarks {
+		ids[i] = remark.Id
+	}
+	return ids
+}
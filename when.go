@@ -0,0 +1,203 @@
+package go_dynamic_questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// whenRule is a structured alternative to condition expressions, aimed at survey authors
+// who don't want to learn the expression language:
+//
+//	when:
+//	  question: "q1"
+//	  equals: 1
+//
+// Rules are combined with all, any and not:
+//
+//	when:
+//	  any:
+//	    - question: "q1"
+//	      in: [1, 2]
+//	    - not:
+//	        question: "q2"
+//	        answered: true
+//
+// A rule is translated into an equivalent condition expression when the questionnaire is loaded.
+// When a rule declares several comparisons, all of them must hold.
+type whenRule struct {
+	Question    string     `yaml:"question,omitempty" json:"question,omitempty"`         // ID of the question whose answer is compared
+	Equals      *int       `yaml:"equals,omitempty" json:"equals,omitempty"`             // The answer must be this value
+	NotEquals   *int       `yaml:"not_equals,omitempty" json:"not_equals,omitempty"`     // The answer must not be this value
+	In          []int      `yaml:"in,omitempty" json:"in,omitempty"`                     // The answer must be one of these values
+	NotIn       []int      `yaml:"not_in,omitempty" json:"not_in,omitempty"`             // The answer must not be any of these values
+	GreaterThan *int       `yaml:"greater_than,omitempty" json:"greater_than,omitempty"` // The answer must be greater than this value
+	LessThan    *int       `yaml:"less_than,omitempty" json:"less_than,omitempty"`       // The answer must be less than this value
+	Answered    *bool      `yaml:"answered,omitempty" json:"answered,omitempty"`         // Whether the question must (or must not) be answered
+	Skipped     *bool      `yaml:"skipped,omitempty" json:"skipped,omitempty"`           // Whether the question must (or must not) be skipped
+	All         []whenRule `yaml:"all,omitempty" json:"all,omitempty"`                   // Every nested rule must hold
+	Any         []whenRule `yaml:"any,omitempty" json:"any,omitempty"`                   // At least one nested rule must hold
+	Not         *whenRule  `yaml:"not,omitempty" json:"not,omitempty"`                   // The nested rule must not hold
+}
+
+// expression translates the rule into the equivalent condition expression.
+// Returns an error when the rule is incomplete.
+func (r *whenRule) expression() (string, error) {
+	var parts []string
+
+	if r.Question != "" {
+		answer := fmt.Sprintf("answers[%s]", strconv.Quote(r.Question))
+		if r.Equals != nil {
+			parts = append(parts, fmt.Sprintf("%s == %d", answer, *r.Equals))
+		}
+		if r.NotEquals != nil {
+			parts = append(parts, fmt.Sprintf("%s != %d", answer, *r.NotEquals))
+		}
+		if len(r.In) > 0 {
+			parts = append(parts, fmt.Sprintf("%s in %s", answer, intList(r.In)))
+		}
+		if len(r.NotIn) > 0 {
+			parts = append(parts, fmt.Sprintf("%s not in %s", answer, intList(r.NotIn)))
+		}
+		if r.GreaterThan != nil {
+			parts = append(parts, fmt.Sprintf("%s > %d", answer, *r.GreaterThan))
+		}
+		if r.LessThan != nil {
+			parts = append(parts, fmt.Sprintf("%s < %d", answer, *r.LessThan))
+		}
+		if r.Answered != nil {
+			parts = append(parts, negate(fmt.Sprintf("answered(%s)", strconv.Quote(r.Question)), !*r.Answered))
+		}
+		if r.Skipped != nil {
+			parts = append(parts, negate(fmt.Sprintf("skipped(%s)", strconv.Quote(r.Question)), !*r.Skipped))
+		}
+		if len(parts) == 0 {
+			return "", fmt.Errorf("rule on question '%s' has no comparison", r.Question)
+		}
+	}
+
+	if len(r.All) > 0 {
+		all, err := joinRules(r.All, " and ")
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, all)
+	}
+	if len(r.Any) > 0 {
+		anyRule, err := joinRules(r.Any, " or ")
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, anyRule)
+	}
+	if r.Not != nil {
+		not, err := r.Not.expression()
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, negate(not, true))
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", errors.New("rule must reference a question or combine rules with all, any or not")
+	case 1:
+		return parts[0], nil
+	default:
+		return "(" + strings.Join(parts, ") and (") + ")", nil
+	}
+}
+
+// joinRules translates the rules and joins their expressions with the operator.
+func joinRules(rules []whenRule, operator string) (string, error) {
+	expressions := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		expression, err := rule.expression()
+		if err != nil {
+			return "", err
+		}
+		expressions = append(expressions, "("+expression+")")
+	}
+	if len(expressions) == 1 {
+		return expressions[0], nil
+	}
+	return "(" + strings.Join(expressions, operator) + ")", nil
+}
+
+// negate wraps the expression in a "not" when negated is true.
+func negate(expression string, negated bool) string {
+	if !negated {
+		return expression
+	}
+	return "not (" + expression + ")"
+}
+
+// intList formats values as an expression array literal.
+func intList(values []int) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = strconv.Itoa(value)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// combineCondition merges a when rule into a condition expression.
+// When both are set, the element is shown only if both hold.
+func combineCondition(condition string, rule *whenRule) (string, error) {
+	if rule == nil {
+		return condition, nil
+	}
+
+	expression, err := rule.expression()
+	if err != nil {
+		return "", err
+	}
+	return andConditions(condition, expression), nil
+}
+
+// andConditions joins two conditions so that both must hold. Empty conditions are ignored.
+func andConditions(condition, other string) string {
+	if condition == "" {
+		return other
+	}
+	return "(" + condition + ") and (" + other + ")"
+}
+
+// expandWhenRules translates the when rules of questions, answer options and closing remarks
+// into their condition expressions, so that the rest of the engine only deals with expressions.
+func (q *questionnaire) expandWhenRules() error {
+	var errs []error
+	for i := range q.QuestionList {
+		question := &q.QuestionList[i]
+
+		condition, err := combineCondition(question.Condition, question.When)
+		if err != nil {
+			errs = append(errs, invalidWhenRuleError("question_id", question.Id, err))
+		} else {
+			question.Condition = condition
+		}
+
+		for j := range question.Answers {
+			option := &question.Answers[j]
+			condition, err := combineCondition(option.Condition, option.When)
+			if err != nil {
+				errs = append(errs, invalidWhenRuleError("question_id", question.Id, err))
+			} else {
+				option.Condition = condition
+			}
+		}
+	}
+
+	for i := range q.Remarks {
+		remark := &q.Remarks[i]
+		condition, err := combineCondition(remark.Condition, remark.When)
+		if err != nil {
+			errs = append(errs, invalidWhenRuleError("remark_id", remark.Id, err))
+		} else {
+			remark.Condition = condition
+		}
+	}
+
+	return errors.Join(errs...)
+}
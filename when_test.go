@@ -0,0 +1,45 @@
+package go_dynamic_questionnaire
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("When rules", func() {
+	one, three := 1, 3
+	yes, no := true, false
+
+	DescribeTable("translating rules into expressions",
+		func(rule whenRule, expected string) {
+			expression, err := rule.expression()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expression).To(Equal(expected))
+		},
+		Entry("equals", whenRule{Question: "q1", Equals: &one}, `answers["q1"] == 1`),
+		Entry("not equals", whenRule{Question: "q1", NotEquals: &one}, `answers["q1"] != 1`),
+		Entry("in", whenRule{Question: "q1", In: []int{1, 2}}, `answers["q1"] in [1, 2]`),
+		Entry("not in", whenRule{Question: "q1", NotIn: []int{3}}, `answers["q1"] not in [3]`),
+		Entry("range", whenRule{Question: "q1", GreaterThan: &one, LessThan: &three}, `(answers["q1"] > 1) and (answers["q1"] < 3)`),
+		Entry("answered", whenRule{Question: "q1", Answered: &yes}, `answered("q1")`),
+		Entry("not skipped", whenRule{Question: "q1", Skipped: &no}, `not (skipped("q1"))`),
+		Entry("all", whenRule{All: []whenRule{{Question: "q1", Equals: &one}, {Question: "q2", Answered: &yes}}}, `((answers["q1"] == 1) and (answered("q2")))`),
+		Entry("any", whenRule{Any: []whenRule{{Question: "q1", Equals: &one}, {Question: "q1", Equals: &three}}}, `((answers["q1"] == 1) or (answers["q1"] == 3))`),
+		Entry("not", whenRule{Not: &whenRule{Question: "q1", Equals: &one}}, `not (answers["q1"] == 1)`),
+	)
+
+	It("should reject rules without comparison", func() {
+		_, err := (&whenRule{Question: "q1"}).expression()
+		Expect(err).To(MatchError("rule on question 'q1' has no comparison"))
+	})
+
+	It("should reject empty rules", func() {
+		_, err := (&whenRule{Any: []whenRule{{}}}).expression()
+		Expect(err).To(MatchError("rule must reference a question or combine rules with all, any or not"))
+	})
+
+	It("should combine rules with conditions", func() {
+		condition, err := combineCondition(`answers["q0"] == 2`, &whenRule{Question: "q1", Equals: &one})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(condition).To(Equal(`(answers["q0"] == 2) and (answers["q1"] == 1)`))
+	})
+})